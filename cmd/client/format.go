@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// outputMode selects how replies are printed
+type outputMode int
+
+const (
+	// outputStandard is the human readable format used on terminals
+	outputStandard outputMode = iota
+	// outputRaw prints values as they are, one per line
+	outputRaw
+	// outputCSV prints values as comma separated quoted strings
+	outputCSV
+)
+
+// formatReply formats a reply as redis-cli does in the given mode
+func formatReply(reply interface{}, mode outputMode) string {
+	switch mode {
+	case outputRaw:
+		return formatRaw(reply) + "\n"
+	case outputCSV:
+		return formatCSV(reply) + "\n"
+	default:
+		return formatStandard(reply, "")
+	}
+}
+
+// formatStandard formats a reply for a terminal. Nested elements are
+// indented with prefix, which grows with every level of nesting.
+func formatStandard(reply interface{}, prefix string) string {
+	switch v := reply.(type) {
+	case nil:
+		return "(nil)\n"
+	case *errs.Error:
+		return "(error) " + v.Error() + "\n"
+	case types.SimpleString:
+		return string(v) + "\n"
+	case int64:
+		return "(integer) " + strconv.FormatInt(v, 10) + "\n"
+	case float64:
+		return "(double) " + resp.FormatFloat(v) + "\n"
+	case bool:
+		if v {
+			return "(true)\n"
+		}
+		return "(false)\n"
+	case types.BigNumber:
+		return "(big number) " + string(v) + "\n"
+	case string:
+		return quote(v) + "\n"
+	case types.VerbatimString:
+		// Verbatim strings are meant to be shown as they are
+		return v.Text + "\n"
+	case []interface{}:
+		return formatAggregate(v, false, ')', "(empty array)", prefix)
+	case types.Set:
+		return formatAggregate(v, false, '~', "(empty set)", prefix)
+	case types.Push:
+		return formatAggregate(v, false, ')', "(empty push)", prefix)
+	case types.Map:
+		return formatAggregate(flattenMap(v), true, '#', "(empty hash)", prefix)
+	default:
+		return fmt.Sprintf("%v\n", v)
+	}
+}
+
+// formatAggregate numbers the elements of an aggregate one per line, as in
+// `1) "a"`, with nested aggregates indented under their index. Map entries
+// are numbered in pairs and printed as `1# key => value`.
+func formatAggregate(elements []interface{}, isMap bool, sep byte, empty, prefix string) string {
+	if len(elements) == 0 {
+		return empty + "\n"
+	}
+
+	count := len(elements)
+	if isMap {
+		count /= 2
+	}
+	idxLen := len(strconv.Itoa(count))
+	nestedPrefix := prefix + strings.Repeat(" ", idxLen+2)
+
+	var out []byte
+	for i := 0; i < len(elements); i++ {
+		index := i + 1
+		if isMap {
+			index = i/2 + 1
+		}
+
+		// The first element follows the index the parent already printed
+		if i > 0 {
+			out = append(out, prefix...)
+		}
+		out = fmt.Appendf(out, "%*d%c ", idxLen, index, sep)
+		out = append(out, formatStandard(elements[i], nestedPrefix)...)
+
+		if isMap {
+			i++
+			out = append(out[:len(out)-1], " => "...)
+			out = append(out, formatStandard(elements[i], nestedPrefix)...)
+		}
+	}
+	return string(out)
+}
+
+// formatRaw formats a reply without any decoration, as used when the output
+// isn't a terminal
+func formatRaw(reply interface{}) string {
+	switch v := reply.(type) {
+	case nil:
+		return ""
+	case *errs.Error:
+		return v.Error()
+	case types.SimpleString:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return resp.FormatFloat(v)
+	case bool:
+		if v {
+			return "(true)"
+		}
+		return "(false)"
+	case types.BigNumber:
+		return string(v)
+	case types.VerbatimString:
+		return v.Text
+	case []interface{}:
+		return joinFormatted(v, "\n", formatRaw)
+	case types.Set:
+		return joinFormatted(v, "\n", formatRaw)
+	case types.Push:
+		return joinFormatted(v, "\n", formatRaw)
+	case types.Map:
+		entries := make([]string, len(v))
+		for i, e := range v {
+			entries[i] = formatRaw(e.Key) + " " + formatRaw(e.Value)
+		}
+		return strings.Join(entries, "\n")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatCSV formats a reply as comma separated values
+func formatCSV(reply interface{}) string {
+	switch v := reply.(type) {
+	case nil:
+		return "NULL"
+	case *errs.Error:
+		return "ERROR," + quote(v.Error())
+	case types.SimpleString:
+		return quote(string(v))
+	case string:
+		return quote(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return resp.FormatFloat(v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case types.BigNumber:
+		return string(v)
+	case types.VerbatimString:
+		return quote(v.Text)
+	case []interface{}:
+		return joinFormatted(v, ",", formatCSV)
+	case types.Set:
+		return joinFormatted(v, ",", formatCSV)
+	case types.Push:
+		return joinFormatted(v, ",", formatCSV)
+	case types.Map:
+		return joinFormatted(flattenMap(v), ",", formatCSV)
+	default:
+		return quote(fmt.Sprint(v))
+	}
+}
+
+func joinFormatted(elements []interface{}, sep string, format func(interface{}) string) string {
+	parts := make([]string, len(elements))
+	for i, el := range elements {
+		parts[i] = format(el)
+	}
+	return strings.Join(parts, sep)
+}
+
+// flattenMap returns the keys and values of m as alternating elements
+func flattenMap(m types.Map) []interface{} {
+	flat := make([]interface{}, 0, len(m)*2)
+	for _, e := range m {
+		flat = append(flat, e.Key, e.Value)
+	}
+	return flat
+}
+
+// quote returns s in double quotes with special and non-printable bytes
+// escaped, like Redis's sdscatrepr
+func quote(s string) string {
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', '"':
+			b = append(b, '\\', c)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\a':
+			b = append(b, `\a`...)
+		case '\b':
+			b = append(b, `\b`...)
+		default:
+			if c >= ' ' && c <= '~' {
+				b = append(b, c)
+			} else {
+				b = fmt.Appendf(b, `\x%02x`, c)
+			}
+		}
+	}
+	return string(append(b, '"'))
+}
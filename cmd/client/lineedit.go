@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHistoryLen is the number of lines kept in the history, as in linenoise
+const maxHistoryLen = 100
+
+// errInterrupted is returned by ReadLine when the user presses Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// Keys handled by the line editor
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlT     = 20
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEsc       = 27
+	keyBackspace = 127
+)
+
+// lineEditor reads lines from a terminal with basic editing and history, in
+// the spirit of the linenoise library redis-cli is built on. When stdin isn't
+// a terminal, lines are read as they come, without a prompt.
+type lineEditor struct {
+	in     *os.File
+	out    *os.File
+	reader *bufio.Reader
+	tty    bool
+
+	history []string
+}
+
+func newLineEditor(in, out *os.File) *lineEditor {
+	return &lineEditor{
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+		tty:    isTerminal(in.Fd()),
+	}
+}
+
+// ReadLine shows prompt and returns the line the user entered. It returns
+// io.EOF on Ctrl-D or at the end of the input, and errInterrupted on Ctrl-C.
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	if !e.tty {
+		return e.readPlainLine()
+	}
+
+	restore, err := makeRaw(e.in.Fd())
+	if err != nil {
+		// Not a terminal we can drive; fall back to plain input
+		e.out.WriteString(prompt)
+		return e.readPlainLine()
+	}
+	defer restore()
+
+	line, err := e.edit(prompt)
+	e.out.WriteString("\n")
+	return line, err
+}
+
+// readPlainLine reads a line without any editing
+func (e *lineEditor) readPlainLine() (string, error) {
+	line, err := e.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editState is the line being edited
+type editState struct {
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+// edit runs the editing loop until the line is entered
+func (e *lineEditor) edit(prompt string) (string, error) {
+	s := &editState{prompt: prompt}
+
+	// histIndex is the history entry being shown; len(e.history) is the new
+	// line, whose contents are kept in current while browsing the history
+	histIndex := len(e.history)
+	var current []rune
+	showHistory := func(index int) {
+		if index < 0 || index > len(e.history) {
+			return
+		}
+		if histIndex == len(e.history) {
+			current = append(current[:0], s.buf...)
+		}
+		histIndex = index
+		if index == len(e.history) {
+			s.buf = append(s.buf[:0], current...)
+		} else {
+			s.buf = []rune(e.history[index])
+		}
+		s.pos = len(s.buf)
+	}
+
+	e.refresh(s)
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case keyEnter, '\n':
+			return string(s.buf), nil
+		case keyCtrlC:
+			return "", errInterrupted
+		case keyCtrlD:
+			// Ctrl-D exits on an empty line and deletes forward otherwise
+			if len(s.buf) == 0 {
+				return "", io.EOF
+			}
+			s.deleteForward()
+		case keyBackspace, keyCtrlH:
+			if s.pos > 0 {
+				s.buf = append(s.buf[:s.pos-1], s.buf[s.pos:]...)
+				s.pos--
+			}
+		case keyCtrlA:
+			s.pos = 0
+		case keyCtrlE:
+			s.pos = len(s.buf)
+		case keyCtrlB:
+			s.moveLeft()
+		case keyCtrlF:
+			s.moveRight()
+		case keyCtrlP:
+			showHistory(histIndex - 1)
+		case keyCtrlN:
+			showHistory(histIndex + 1)
+		case keyCtrlK:
+			s.buf = s.buf[:s.pos]
+		case keyCtrlU:
+			s.buf = s.buf[:0]
+			s.pos = 0
+		case keyCtrlW:
+			// Delete the previous word and the spaces after it
+			start := s.pos
+			for start > 0 && s.buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && s.buf[start-1] != ' ' {
+				start--
+			}
+			s.buf = append(s.buf[:start], s.buf[s.pos:]...)
+			s.pos = start
+		case keyCtrlT:
+			// Swap the character before the cursor with the one under it
+			if s.pos > 0 && s.pos < len(s.buf) {
+				s.buf[s.pos-1], s.buf[s.pos] = s.buf[s.pos], s.buf[s.pos-1]
+				if s.pos < len(s.buf)-1 {
+					s.pos++
+				}
+			}
+		case keyCtrlL:
+			e.out.WriteString("\x1b[H\x1b[2J")
+		case keyEsc:
+			switch e.readEscape() {
+			case 'A':
+				showHistory(histIndex - 1)
+			case 'B':
+				showHistory(histIndex + 1)
+			case 'C':
+				s.moveRight()
+			case 'D':
+				s.moveLeft()
+			case 'H':
+				s.pos = 0
+			case 'F':
+				s.pos = len(s.buf)
+			case '3':
+				s.deleteForward()
+			}
+		case keyTab:
+			// No completion; ignore rather than inserting a tab
+		default:
+			if r < ' ' {
+				continue
+			}
+			s.buf = append(s.buf, 0)
+			copy(s.buf[s.pos+1:], s.buf[s.pos:])
+			s.buf[s.pos] = r
+			s.pos++
+		}
+		e.refresh(s)
+	}
+}
+
+// readEscape reads the rest of an escape sequence and returns the final
+// letter of arrow, home and end keys, or '3' for the delete key
+func (e *lineEditor) readEscape() byte {
+	seq0, err := e.reader.ReadByte()
+	if err != nil {
+		return 0
+	}
+	seq1, err := e.reader.ReadByte()
+	if err != nil {
+		return 0
+	}
+
+	switch seq0 {
+	case '[':
+		if seq1 < '0' || seq1 > '9' {
+			return seq1
+		}
+		// Extended sequences such as ESC [ 3 ~
+		seq2, err := e.reader.ReadByte()
+		if err != nil || seq2 != '~' {
+			return 0
+		}
+		switch seq1 {
+		case '1', '7':
+			return 'H'
+		case '4', '8':
+			return 'F'
+		case '3':
+			return '3'
+		}
+	case 'O':
+		return seq1
+	}
+	return 0
+}
+
+// refresh redraws the line, scrolling it horizontally when it doesn't fit
+func (e *lineEditor) refresh(s *editState) {
+	cols := terminalWidth(e.out.Fd())
+	plen := utf8.RuneCountInString(s.prompt)
+
+	start, end := 0, len(s.buf)
+	for plen+s.pos-start >= cols && start < s.pos {
+		start++
+	}
+	for plen+end-start > cols && end > s.pos {
+		end--
+	}
+
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(s.prompt)
+	b.WriteString(string(s.buf[start:end]))
+	// Erase to the end of the line, then put the cursor back in place
+	b.WriteString("\x1b[0K\r")
+	if col := plen + s.pos - start; col > 0 {
+		b.WriteString("\x1b[" + strconv.Itoa(col) + "C")
+	}
+	e.out.WriteString(b.String())
+}
+
+func (s *editState) moveLeft() {
+	if s.pos > 0 {
+		s.pos--
+	}
+}
+
+func (s *editState) moveRight() {
+	if s.pos < len(s.buf) {
+		s.pos++
+	}
+}
+
+func (s *editState) deleteForward() {
+	if s.pos < len(s.buf) {
+		s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+	}
+}
+
+// AddHistory appends line to the history, skipping empty lines and repeats of
+// the previous line
+func (e *lineEditor) AddHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistoryLen {
+		e.history = e.history[len(e.history)-maxHistoryLen:]
+	}
+}
+
+// LoadHistory reads the history saved at path. A missing file is not an error.
+func (e *lineEditor) LoadHistory(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		e.AddHistory(strings.TrimRight(line, "\r"))
+	}
+	return nil
+}
+
+// SaveHistory writes the history to path, readable only by the user since it
+// may contain sensitive values
+func (e *lineEditor) SaveHistory(path string) error {
+	var b strings.Builder
+	for _, line := range e.history {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
@@ -1,7 +1,353 @@
+// Command client is a command-line client for the server, modelled on
+// redis-cli. Run it without a command for an interactive prompt, or pass a
+// command to run it once and print the reply:
+//
+//	client -h 127.0.0.1 -p 6379
+//	client -p 6380 -a secret SET greeting hello
 package main
 
-import "fmt"
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/resp"
+)
+
+// dialTimeout bounds how long connecting to the server may take
+const dialTimeout = 5 * time.Second
+
+// cli holds the connection settings and the current connection, if any
+type cli struct {
+	host     string
+	port     int
+	user     string
+	password string
+	output   outputMode
+	// inTx is set between MULTI and EXEC or DISCARD
+	inTx bool
+
+	conn   net.Conn
+	reader *resp.Reader
+	writer *resp.Writer
+}
 
 func main() {
-	fmt.Println("Hello, Go!")
+	c := &cli{}
+	flag.StringVar(&c.host, "h", "127.0.0.1", "server hostname")
+	flag.IntVar(&c.port, "p", 6379, "server port")
+	flag.StringVar(&c.password, "a", "", "password to use when connecting to the server")
+	flag.StringVar(&c.user, "user", "", "username to send with AUTH; needs -a")
+	raw := flag.Bool("raw", false, "use raw formatting for replies (the default when stdout is not a tty)")
+	noRaw := flag.Bool("no-raw", false, "force formatted output even when stdout is not a tty")
+	csv := flag.Bool("csv", false, "output in CSV format")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] [cmd [arg [arg ...]]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if c.password != "" {
+		fmt.Fprintln(os.Stderr, "Warning: Using a password with '-a' option on the command line interface may not be safe.")
+	}
+
+	// Replies are formatted for humans on a terminal and raw otherwise, so
+	// the client's output can be piped into other tools
+	c.output = outputRaw
+	if isTerminal(os.Stdout.Fd()) || *noRaw {
+		c.output = outputStandard
+	}
+	if *raw {
+		c.output = outputRaw
+	}
+	if *csv {
+		c.output = outputCSV
+	}
+
+	if flag.NArg() > 0 {
+		os.Exit(c.runOnce(flag.Args()))
+	}
+	c.repl()
+}
+
+// runOnce runs a single command from the command line and returns the exit
+// status: 1 when the server can't be reached or replies with an error
+func (c *cli) runOnce(args []string) int {
+	if err := c.connect(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer c.conn.Close()
+
+	if isSubscribe(args) && c.output == outputStandard {
+		fmt.Println(readingMessages)
+	}
+	reply, err := c.do(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Print(formatReply(reply, c.output))
+	if _, ok := reply.(*errs.Error); ok {
+		return 1
+	}
+	if isSubscribe(args) {
+		c.readMessages()
+	}
+	return 0
+}
+
+// repl reads commands from the prompt until the user quits
+func (c *cli) repl() {
+	editor := newLineEditor(os.Stdin, os.Stdout)
+	historyFile := historyPath()
+	if historyFile != "" {
+		editor.LoadHistory(historyFile)
+	}
+
+	if err := c.connect(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	for {
+		line, err := editor.ReadLine(c.prompt())
+		if err != nil {
+			break
+		}
+
+		args, err := resp.SplitArgs(line)
+		if err != nil {
+			fmt.Println("Invalid argument(s)")
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		if !isSensitive(args) {
+			editor.AddHistory(line)
+			if historyFile != "" {
+				editor.SaveHistory(historyFile)
+			}
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "quit", "exit":
+			return
+		case "clear":
+			fmt.Print("\x1b[H\x1b[2J")
+			continue
+		}
+
+		// A leading number repeats the command, as in "3 PING"
+		repeat := 1
+		if n, err := strconv.Atoi(args[0]); err == nil && len(args) > 1 {
+			repeat, args = n, args[1:]
+		}
+		for i := 0; i < repeat; i++ {
+			if !c.runInteractive(args) {
+				break
+			}
+		}
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// runInteractive runs a command typed at the prompt and prints its reply,
+// reconnecting first if the connection was lost. It reports whether the
+// command reached the server.
+func (c *cli) runInteractive(args []string) bool {
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return false
+		}
+	}
+
+	if isSubscribe(args) && c.output == outputStandard {
+		fmt.Println(readingMessages)
+	}
+	reply, err := c.do(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	fmt.Print(formatReply(reply, c.output))
+	if _, failed := reply.(*errs.Error); !failed && isSubscribe(args) {
+		c.readMessages()
+		return false
+	}
+
+	// Keep what the connection was switched to, so reconnecting restores it
+	_, failed := reply.(*errs.Error)
+	switch strings.ToLower(args[0]) {
+	case "multi":
+		c.inTx = c.inTx || !failed
+	case "exec", "discard":
+		c.inTx = false
+	}
+	if !failed {
+		switch strings.ToLower(args[0]) {
+		case "auth":
+			switch len(args) {
+			case 2:
+				c.user, c.password = "", args[1]
+			case 3:
+				c.user, c.password = args[1], args[2]
+			}
+		}
+	}
+	return true
+}
+
+// connect dials the server and authenticates with the credentials given on
+// the command line. The server has a single database, so there is none to
+// select.
+func (c *cli) connect() error {
+	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("Could not connect to Redis at %s: %v", addr, connectError(err))
+	}
+	c.conn = conn
+	c.inTx = false
+	c.reader = resp.NewReader(bufio.NewReader(conn))
+	c.writer = resp.NewWriter(bufio.NewWriter(conn))
+
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.user != "" {
+			args = []string{"AUTH", c.user, c.password}
+		}
+		reply, err := c.do(args)
+		if err != nil {
+			return err
+		}
+		if e, ok := reply.(*errs.Error); ok {
+			fmt.Fprintf(os.Stderr, "AUTH failed: %s\n", e.Error())
+		}
+	}
+	return nil
+}
+
+// do sends a command and reads its reply. Connection failures drop the
+// connection, so the next command reconnects.
+func (c *cli) do(args []string) (interface{}, error) {
+	err := c.writer.WriteArray(args)
+	if err == nil {
+		err = c.writer.Flush()
+	}
+	var reply interface{}
+	if err == nil {
+		reply, err = c.reader.ReadReply()
+	}
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("Error: Server closed the connection")
+		}
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	return reply, nil
+}
+
+// readingMessages is printed before a subscription's messages
+const readingMessages = "Reading messages... (press Ctrl-C to quit)"
+
+// isSubscribe reports whether args subscribe the connection to messages
+func isSubscribe(args []string) bool {
+	switch strings.ToLower(args[0]) {
+	case "subscribe", "psubscribe", "ssubscribe":
+		return true
+	}
+	return false
+}
+
+// readMessages prints the remaining subscription replies and the messages
+// that follow until the connection closes. Like redis-cli, there is no way
+// back to the prompt but interrupting the client.
+func (c *cli) readMessages() {
+	for {
+		reply, err := c.reader.ReadReply()
+		if err != nil {
+			c.conn.Close()
+			c.conn = nil
+			fmt.Fprintln(os.Stderr, "Error: Server closed the connection")
+			return
+		}
+		fmt.Print(formatReply(reply, c.output))
+	}
+}
+
+// prompt returns the prompt showing where the client is connected
+func (c *cli) prompt() string {
+	if c.conn == nil {
+		return "not connected> "
+	}
+	prompt := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	if c.inTx {
+		prompt += "(TX)"
+	}
+	return prompt + "> "
+}
+
+// historyPath returns the file the prompt history is kept in, or "" to keep
+// no history. REDISCLI_HISTFILE overrides the default ~/.rediscli_history.
+func historyPath() string {
+	if path, ok := os.LookupEnv("REDISCLI_HISTFILE"); ok {
+		if path == "/dev/null" {
+			return ""
+		}
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".rediscli_history")
+}
+
+// isSensitive reports whether a command carries a password and must not be
+// saved in the history
+func isSensitive(args []string) bool {
+	switch strings.ToLower(args[0]) {
+	case "auth":
+		return true
+	case "hello":
+		for _, arg := range args[1:] {
+			if strings.EqualFold(arg, "AUTH") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// connectError strips the Go specific context from a dial error, leaving the
+// reason, such as "Connection refused"
+func connectError(err error) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil {
+		msg := opErr.Err.Error()
+		if i := strings.LastIndex(msg, ": "); i >= 0 {
+			msg = msg[i+2:]
+		}
+		if msg != "" {
+			return strings.ToUpper(msg[:1]) + msg[1:]
+		}
+	}
+	return err.Error()
 }
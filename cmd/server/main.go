@@ -1,17 +1,43 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/hardikphalet/go-redis/internal/notify"
 	"github.com/hardikphalet/go-redis/internal/server"
 )
 
 func main() {
+	config := server.DefaultConfig()
+	port := flag.Int("port", 6379, "TCP port to listen on")
+	flag.StringVar(&config.RequirePass, "requirepass", config.RequirePass, "password clients must AUTH with")
+	flag.Func("proto-max-bulk-len", "largest bulk string a request may contain (e.g. 512mb)", memoryFlag(&config.ProtoMaxBulkLen))
+	flag.Func("client-query-buffer-limit", "largest request a client may send (e.g. 1gb)", memoryFlag(&config.ClientQueryBufferLimit))
+	flag.Func("client-output-buffer-limit-pubsub", "bytes of messages a subscriber may fall behind before it is disconnected (e.g. 32mb, 0 for no limit)", memoryFlag(&config.PubSubBufferLimit))
+	flag.Func("notify-keyspace-events", "keyspace notifications to publish, as in redis.conf (e.g. KEA)", func(s string) error {
+		classes, err := notify.Parse(s)
+		config.NotifyKeyspaceEvents = classes
+		return err
+	})
+	flag.IntVar(&config.Hz, "hz", config.Hz, "how many times a second background tasks such as expiring keys run (1-500)")
+	flag.Func("enable-debug-command", "allow DEBUG: no, yes, or local for loopback connections only", func(s string) error {
+		switch s {
+		case "no", "yes", "local":
+			config.EnableDebugCommand = s
+			return nil
+		}
+		return fmt.Errorf("argument must be one of the following: no, yes, local")
+	})
+	flag.Parse()
+	config.Addr = fmt.Sprintf(":%d", *port)
+
 	// Create a new server instance
-	srv := server.New(":6379") // Default Redis port
+	srv := server.New(config)
 
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -19,7 +45,7 @@ func main() {
 
 	// Start the server in a goroutine
 	go func() {
-		log.Printf("Starting Redis server on port 6379...")
+		log.Printf("Starting Redis server on port %d...", *port)
 		if err := srv.Start(); err != nil {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -37,3 +63,15 @@ func main() {
 
 	log.Println("Server shutdown complete")
 }
+
+// memoryFlag returns a flag setter that parses Redis-style memory amounts into dst
+func memoryFlag(dst *int64) func(string) error {
+	return func(s string) error {
+		n, err := server.ParseMemory(s)
+		if err != nil {
+			return err
+		}
+		*dst = n
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+// Package clock abstracts the time the server reads, so that expiry can be
+// tested without waiting and controlled in test deployments.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the real clock
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Fake is a clock that only moves when told to
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a fake clock stopped at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Adjustable follows a base clock, shifted by the time it was advanced by.
+// Frozen, it stops at the time it was frozen at until advanced or unfrozen.
+type Adjustable struct {
+	base Clock
+
+	mu     sync.Mutex
+	offset time.Duration
+	frozen bool
+	at     time.Time
+}
+
+// NewAdjustable returns a clock following base
+func NewAdjustable(base Clock) *Adjustable {
+	return &Adjustable{base: base}
+}
+
+func (a *Adjustable) Now() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.frozen {
+		return a.at
+	}
+	return a.base.Now().Add(a.offset)
+}
+
+// Freeze stops the clock at the current time
+func (a *Adjustable) Freeze() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.frozen {
+		a.at = a.base.Now().Add(a.offset)
+		a.frozen = true
+	}
+}
+
+// Unfreeze lets the clock run again from where it was frozen
+func (a *Adjustable) Unfreeze() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.frozen {
+		a.offset = a.at.Sub(a.base.Now())
+		a.frozen = false
+	}
+}
+
+// Advance moves the clock forward by d, which may be negative
+func (a *Adjustable) Advance(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.frozen {
+		a.at = a.at.Add(d)
+	} else {
+		a.offset += d
+	}
+}
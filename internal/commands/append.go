@@ -0,0 +1,39 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+type AppendCommand struct {
+	Key   string
+	Value string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "append",
+		Arity:         3,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyInsert)},
+		Summary:       "Appends a string to the value of a key. Creates the key if it doesn't exist.",
+		Since:         "2.0.0",
+		Group:         GroupString,
+		Complexity:    "O(1). The amortized time complexity is O(1) assuming the appended value is small and the already present value is of any size, since the dynamic string library used by Redis will double the free space available on every reallocation.",
+		Arguments:     []Arg{keyArg("key", 0), arg("value", ArgString)},
+		Parse:         parseAppend,
+	})
+}
+
+func parseAppend(args []string) (Command, error) {
+	return &AppendCommand{
+		Key:   args[1],
+		Value: args[2],
+	}, nil
+}
+
+// Execute replies with the length of the string after the append
+func (c *AppendCommand) Execute(store store.Store) (interface{}, error) {
+	return store.Append(c.Key, c.Value)
+}
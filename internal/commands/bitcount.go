@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BitCountCommand counts the set bits of a string
+type BitCountCommand struct {
+	Key string
+	// Range is nil for the whole string
+	Range *options.BitRange
+}
+
+// BitPosCommand finds the first set or clear bit of a string
+type BitPosCommand struct {
+	Key   string
+	Bit   int
+	Range *options.BitRange
+}
+
+func init() {
+	unit := oneOf("unit", tokenArg("byte", "BYTE"), tokenArg("bit", "BIT")).optional().since("7.0.0")
+
+	Register(&CommandSpec{
+		Name:          "bitcount",
+		Arity:         -2,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Counts the number of set bits (population counting) in a string.",
+		Since:         "2.6.0",
+		Group:         GroupBitmap,
+		Complexity:    "O(N)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			block("range",
+				arg("start", ArgInteger),
+				arg("end", ArgInteger),
+				unit,
+			).optional(),
+		},
+		Parse: parseBitCount,
+	})
+
+	Register(&CommandSpec{
+		Name:          "bitpos",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Finds the first set (1) or clear (0) bit in a string.",
+		Since:         "2.8.7",
+		Group:         GroupBitmap,
+		Complexity:    "O(N)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("bit", ArgInteger),
+			block("range",
+				arg("start", ArgInteger),
+				block("end-unit-block",
+					arg("end", ArgInteger),
+					unit,
+				).optional(),
+			).optional(),
+		},
+		Parse: parseBitPos,
+	})
+}
+
+// parseBitRange parses the start, end and unit arguments of BITCOUNT and
+// BITPOS. The end may only be left out if endOptional.
+func parseBitRange(args []string, endOptional bool) (*options.BitRange, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if len(args) > 3 || (len(args) == 1 && !endOptional) {
+		return nil, errs.ErrSyntax
+	}
+
+	r := &options.BitRange{}
+	var err error
+	if r.Start, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	if len(args) >= 2 {
+		if r.End, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return nil, errs.ErrNotInteger
+		}
+		r.HasEnd = true
+	}
+	if len(args) == 3 {
+		switch strings.ToUpper(args[2]) {
+		case "BYTE":
+		case "BIT":
+			r.Bit = true
+		default:
+			return nil, errs.ErrSyntax
+		}
+	}
+	return r, nil
+}
+
+func parseBitCount(args []string) (Command, error) {
+	r, err := parseBitRange(args[2:], false)
+	if err != nil {
+		return nil, err
+	}
+	return &BitCountCommand{Key: args[1], Range: r}, nil
+}
+
+func parseBitPos(args []string) (Command, error) {
+	bit, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	if bit != 0 && bit != 1 {
+		return nil, errs.Errorf("The bit argument must be 1 or 0.")
+	}
+	r, err := parseBitRange(args[3:], true)
+	if err != nil {
+		return nil, err
+	}
+	return &BitPosCommand{Key: args[1], Bit: int(bit), Range: r}, nil
+}
+
+// Execute replies with the number of set bits in the range
+func (c *BitCountCommand) Execute(store store.Store) (interface{}, error) {
+	return store.BitCount(c.Key, c.Range)
+}
+
+// Execute replies with the offset of the first matching bit, or -1
+func (c *BitPosCommand) Execute(store store.Store) (interface{}, error) {
+	return store.BitPos(c.Key, c.Bit, c.Range)
+}
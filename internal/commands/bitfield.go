@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BitFieldCommand implements BITFIELD and BITFIELD_RO, which read and write
+// integers of any width at any bit offset of a string
+type BitFieldCommand struct {
+	Key string
+	Ops []options.BitFieldOp
+}
+
+var errBitFieldType = errs.Errorf("Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+
+func init() {
+	encoding := arg("encoding", ArgString)
+	offset := arg("offset", ArgInteger)
+
+	Register(&CommandSpec{
+		Name:          "bitfield",
+		Arity:         -2,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs: []KeySpec{{
+			Notes:      "This command allows both access and modification of the key",
+			Flags:      []string{KeyRW, KeyUpdate, KeyAccess},
+			BeginIndex: 1,
+			KeyStep:    1,
+		}},
+		Summary:    "Performs arbitrary bitfield integer operations on strings.",
+		Since:      "3.2.0",
+		Group:      GroupBitmap,
+		Complexity: "O(1) for each subcommand specified",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("operation",
+				block("get-block", encoding, offset).token("GET"),
+				block("write",
+					oneOf("overflow-block",
+						tokenArg("wrap", "WRAP"),
+						tokenArg("sat", "SAT"),
+						tokenArg("fail", "FAIL"),
+					).token("OVERFLOW").optional(),
+					oneOf("write-operation",
+						block("set-block", encoding, offset, arg("value", ArgInteger)).token("SET"),
+						block("incrby-block", encoding, offset, arg("increment", ArgInteger)).token("INCRBY"),
+					),
+				),
+			).optional().multiple(),
+		},
+		Parse: parseBitField,
+	})
+
+	Register(&CommandSpec{
+		Name:          "bitfield_ro",
+		Arity:         -2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Performs arbitrary read-only bitfield integer operations on strings.",
+		Since:         "6.0.0",
+		Group:         GroupBitmap,
+		Complexity:    "O(1) for each subcommand specified",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			block("get-block", encoding, offset).token("GET").optional().multiple().multipleToken(),
+		},
+		Parse: parseBitField,
+	})
+}
+
+// parseBitFieldType parses an encoding such as i16 or u8 into its signedness
+// and width
+func parseBitFieldType(s string) (bool, int, error) {
+	if s == "" {
+		return false, 0, errBitFieldType
+	}
+	var signed bool
+	switch s[0] {
+	case 'i', 'I':
+		signed = true
+	case 'u', 'U':
+	default:
+		return false, 0, errBitFieldType
+	}
+	bits, ok := store.ParseInt(s[1:])
+	if !ok || bits < 1 || (signed && bits > 64) || (!signed && bits > 63) {
+		return false, 0, errBitFieldType
+	}
+	return signed, int(bits), nil
+}
+
+func parseBitField(args []string) (Command, error) {
+	cmd := &BitFieldCommand{Key: args[1]}
+	overflow := options.OverflowWrap
+	write := false
+	for i := 2; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		op := options.BitFieldOp{}
+		switch name := strings.ToUpper(args[i]); {
+		case name == "GET" && remaining >= 2:
+			op.Op = options.BitFieldGet
+		case name == "SET" && remaining >= 3:
+			op.Op = options.BitFieldSet
+		case name == "INCRBY" && remaining >= 3:
+			op.Op = options.BitFieldIncrBy
+		case name == "OVERFLOW" && remaining >= 1:
+			i++
+			switch strings.ToUpper(args[i]) {
+			case "WRAP":
+				overflow = options.OverflowWrap
+			case "SAT":
+				overflow = options.OverflowSat
+			case "FAIL":
+				overflow = options.OverflowFail
+			default:
+				return nil, errs.Errorf("Invalid OVERFLOW type specified")
+			}
+			continue
+		default:
+			return nil, errs.ErrSyntax
+		}
+
+		var err error
+		if op.Signed, op.Bits, err = parseBitFieldType(args[i+1]); err != nil {
+			return nil, err
+		}
+		if op.Offset, err = parseBitOffset(args[i+2], true, op.Bits); err != nil {
+			return nil, err
+		}
+		if op.Op == options.BitFieldGet {
+			i += 2
+		} else {
+			var ok bool
+			if op.Value, ok = store.ParseInt(args[i+3]); !ok {
+				return nil, errs.ErrNotInteger
+			}
+			op.Overflow = overflow
+			write = true
+			i += 3
+		}
+		cmd.Ops = append(cmd.Ops, op)
+	}
+
+	if write && strings.EqualFold(args[0], "bitfield_ro") {
+		return nil, errs.Errorf("BITFIELD_RO only supports the GET subcommand")
+	}
+	return cmd, nil
+}
+
+// Execute replies with the result of each GET, SET and INCRBY in order: the
+// value read, the previous value and the new value respectively, or nil for
+// a write that would overflow with OVERFLOW FAIL
+func (c *BitFieldCommand) Execute(store store.Store) (interface{}, error) {
+	return store.BitField(c.Key, c.Ops)
+}
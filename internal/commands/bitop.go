@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BitOpCommand stores the result of a bitwise operation between strings
+type BitOpCommand struct {
+	// Op is one of AND, OR, XOR, NOT and DIFF
+	Op      string
+	DestKey string
+	Keys    []string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "bitop",
+		Arity:         -4,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      2,
+		LastKey:       -1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs: []KeySpec{
+			rangeKeys(2, 0, 1, KeyOW, KeyUpdate),
+			rangeKeys(3, -1, 1, KeyRO, KeyAccess),
+		},
+		Summary:    "Performs bitwise operations on multiple strings, and stores the result.",
+		Since:      "2.6.0",
+		Group:      GroupBitmap,
+		Complexity: "O(N)",
+		Arguments: []Arg{
+			oneOf("operation",
+				tokenArg("and", "AND"),
+				tokenArg("or", "OR"),
+				tokenArg("xor", "XOR"),
+				tokenArg("not", "NOT"),
+				tokenArg("diff", "DIFF").since("8.2.0"),
+			),
+			keyArg("destkey", 0),
+			keyArg("key", 1).multiple(),
+		},
+		Parse: parseBitOp,
+	})
+}
+
+func parseBitOp(args []string) (Command, error) {
+	op := strings.ToUpper(args[1])
+	keys := args[3:]
+	switch op {
+	case "AND", "OR", "XOR":
+	case "NOT":
+		if len(keys) != 1 {
+			return nil, errs.Errorf("BITOP NOT must be called with a single source key.")
+		}
+	case "DIFF":
+		if len(keys) < 2 {
+			return nil, errs.Errorf("BITOP DIFF must be called with at least two source keys.")
+		}
+	default:
+		return nil, errs.ErrSyntax
+	}
+	return &BitOpCommand{Op: op, DestKey: args[2], Keys: keys}, nil
+}
+
+// Execute replies with the length of the string stored at the destination
+func (c *BitOpCommand) Execute(store store.Store) (interface{}, error) {
+	return store.BitOp(c.Op, c.DestKey, c.Keys)
+}
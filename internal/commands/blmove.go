@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BLMoveCommand is LMOVE blocking until the source list has an element
+type BLMoveCommand struct {
+	LMoveCommand
+	Timeout time.Duration
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "blmove",
+		Arity:         6,
+		Flags:         []string{FlagWrite, FlagDenyOOM, FlagBlocking},
+		FirstKey:      1,
+		LastKey:       2,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs: []KeySpec{
+			rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyDelete),
+			rangeKeys(2, 0, 1, KeyRW, KeyInsert),
+		},
+		Summary:    "Pops an element from a list, pushes it to another list and returns it. Blocks until an element is available otherwise. Deletes the list if the last element was moved.",
+		Since:      "6.2.0",
+		Group:      GroupList,
+		Complexity: "O(1)",
+		Arguments: []Arg{
+			keyArg("source", 0),
+			keyArg("destination", 1),
+			sideArg("wherefrom"),
+			sideArg("whereto"),
+			arg("timeout", ArgDouble),
+		},
+		Parse: func(args []string) (Command, error) {
+			cmd, err := parseLMove(args[:5])
+			if err != nil {
+				return nil, err
+			}
+			timeout, err := parseTimeout(args[5])
+			if err != nil {
+				return nil, err
+			}
+			return &BLMoveCommand{LMoveCommand: *cmd.(*LMoveCommand), Timeout: timeout}, nil
+		},
+	})
+}
+
+func (c *BLMoveCommand) BlockKeys() []string         { return []string{c.Source} }
+func (c *BLMoveCommand) BlockTimeout() time.Duration { return c.Timeout }
+func (c *BLMoveCommand) TimeoutReply() interface{}   { return nil }
+
+// Execute replies with the element moved, or nil if the source is missing
+func (c *BLMoveCommand) Execute(store store.Store) (interface{}, error) {
+	return c.LMoveCommand.Execute(store)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BLMPopCommand is LMPOP blocking until one of the lists has elements
+type BLMPopCommand struct {
+	LMPopCommand
+	Timeout time.Duration
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "blmpop",
+		Arity:         -5,
+		Flags:         []string{FlagWrite, FlagBlocking, FlagMovableKeys},
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{numKeys(2, 0, 1, 1, KeyRW, KeyAccess, KeyDelete)},
+		Summary:       "Pops the first element from one of multiple lists. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+		Since:         "7.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N+M) where N is the number of provided keys and M is the number of elements returned.",
+		Arguments: []Arg{
+			arg("timeout", ArgDouble),
+			arg("numkeys", ArgInteger),
+			keyArg("key", 0).multiple(),
+			sideArg("where"),
+			arg("count", ArgInteger).token("COUNT").optional(),
+		},
+		Parse: func(args []string) (Command, error) {
+			timeout, err := parseTimeout(args[1])
+			if err != nil {
+				return nil, err
+			}
+			keys, front, count, err := parseMPop(args, 2, parseSide)
+			if err != nil {
+				return nil, err
+			}
+			return &BLMPopCommand{
+				LMPopCommand: LMPopCommand{Keys: keys, Front: front, Count: count},
+				Timeout:      timeout,
+			}, nil
+		},
+	})
+}
+
+func (c *BLMPopCommand) BlockKeys() []string         { return c.Keys }
+func (c *BLMPopCommand) BlockTimeout() time.Duration { return c.Timeout }
+func (c *BLMPopCommand) TimeoutReply() interface{}   { return []interface{}(nil) }
+
+// Execute replies as LMPOP does, or with nil if all of the lists are missing
+func (c *BLMPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.LMPop(c.Keys, c.Front, c.Count)
+	if err != nil || popped == nil {
+		return nil, err
+	}
+	return []interface{}{key, popped}, nil
+}
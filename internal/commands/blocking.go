@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"math"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BlockingCommand is implemented by commands that block the client until
+// they can be served, such as BLPOP. Execute makes a single attempt and
+// returns a nil reply when there is nothing to serve yet, in which case the
+// server waits for one of the keys to change and tries again. Inside MULTI
+// the attempt is all there is, and nothing to serve times out right away.
+type BlockingCommand interface {
+	Command
+	// BlockKeys returns the keys whose changes may let the command be served
+	BlockKeys() []string
+	// BlockTimeout returns how long to block, 0 meaning forever
+	BlockTimeout() time.Duration
+	// TimeoutReply returns the reply sent when the timeout elapses
+	TimeoutReply() interface{}
+}
+
+// parseTimeout parses the timeout of a blocking command, in seconds with a
+// fractional part, rounding it up to a millisecond as Redis does
+func parseTimeout(arg string) (time.Duration, error) {
+	seconds, ok := store.ParseFloat(arg)
+	if !ok {
+		return 0, errs.Errorf("timeout is not a float or out of range")
+	}
+	ms := math.Ceil(seconds * 1000)
+	if ms > math.MaxInt64 {
+		return 0, errs.Errorf("timeout is out of range")
+	}
+	if ms < 0 {
+		return 0, errs.Errorf("timeout is negative")
+	}
+	// Redis accepts timeouts of up to hundreds of millions of years, which
+	// don't fit a Duration and might as well be forever
+	if ms > float64(math.MaxInt64/int64(time.Millisecond)) {
+		return 0, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BPopCommand implements BLPOP and BRPOP, which pop an element from the
+// first non-empty list of several, blocking until there is one
+type BPopCommand struct {
+	Keys    []string
+	Front   bool
+	Timeout time.Duration
+}
+
+func init() {
+	bpop := func(name, summary string, front bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -3,
+			Flags:         []string{FlagWrite, FlagBlocking},
+			FirstKey:      1,
+			LastKey:       -2,
+			Step:          1,
+			ACLCategories: []string{"list"},
+			KeySpecs:      []KeySpec{rangeKeys(1, -2, 1, KeyRW, KeyAccess, KeyDelete)},
+			Summary:       summary,
+			Since:         "2.0.0",
+			Group:         GroupList,
+			Complexity:    "O(N) where N is the number of provided keys.",
+			Arguments: []Arg{
+				keyArg("key", 0).multiple(),
+				arg("timeout", ArgDouble),
+			},
+			Parse: func(args []string) (Command, error) {
+				timeout, err := parseTimeout(args[len(args)-1])
+				if err != nil {
+					return nil, err
+				}
+				return &BPopCommand{Keys: args[1 : len(args)-1], Front: front, Timeout: timeout}, nil
+			},
+		}
+	}
+
+	Register(bpop("blpop", "Removes and returns the first element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.", true))
+	Register(bpop("brpop", "Removes and returns the last element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.", false))
+}
+
+func (c *BPopCommand) BlockKeys() []string         { return c.Keys }
+func (c *BPopCommand) BlockTimeout() time.Duration { return c.Timeout }
+func (c *BPopCommand) TimeoutReply() interface{}   { return []interface{}(nil) }
+
+// Execute replies with the key popped from and the element, or nil if all
+// of the lists are missing
+func (c *BPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.LMPop(c.Keys, c.Front, 1)
+	if err != nil || popped == nil {
+		return nil, err
+	}
+	return []interface{}{key, popped[0]}, nil
+}
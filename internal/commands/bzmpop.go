@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BZMPopCommand is ZMPOP blocking until one of the sorted sets has members
+type BZMPopCommand struct {
+	ZMPopCommand
+	Timeout time.Duration
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "bzmpop",
+		Arity:         -5,
+		Flags:         []string{FlagWrite, FlagBlocking, FlagMovableKeys},
+		ACLCategories: []string{"sortedset"},
+		KeySpecs:      []KeySpec{numKeys(2, 0, 1, 1, KeyRW, KeyAccess, KeyDelete)},
+		Summary:       "Removes and returns a member by score from one or more sorted sets. Blocks until a member is available otherwise. Deletes the sorted set if the last element was popped.",
+		Since:         "7.0.0",
+		Group:         GroupSortedSet,
+		Complexity:    "O(K) + O(M*log(N)) where K is the number of provided keys, N being the number of elements in the sorted set, and M being the number of elements popped.",
+		Arguments: []Arg{
+			arg("timeout", ArgDouble),
+			arg("numkeys", ArgInteger),
+			keyArg("key", 0).multiple(),
+			minMaxArg(),
+			arg("count", ArgInteger).token("COUNT").optional(),
+		},
+		Parse: func(args []string) (Command, error) {
+			timeout, err := parseTimeout(args[1])
+			if err != nil {
+				return nil, err
+			}
+			keys, max, count, err := parseMPop(args, 2, parseMinMax)
+			if err != nil {
+				return nil, err
+			}
+			return &BZMPopCommand{
+				ZMPopCommand: ZMPopCommand{Keys: keys, Max: max, Count: count},
+				Timeout:      timeout,
+			}, nil
+		},
+	})
+}
+
+func (c *BZMPopCommand) BlockKeys() []string         { return c.Keys }
+func (c *BZMPopCommand) BlockTimeout() time.Duration { return c.Timeout }
+func (c *BZMPopCommand) TimeoutReply() interface{}   { return []interface{}(nil) }
+
+// Execute replies as ZMPOP does, or with nil if all of the sorted sets are
+// missing
+func (c *BZMPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.ZMPop(c.Keys, c.Max, c.Count)
+	if err != nil || popped == nil {
+		return nil, err
+	}
+	return zmpopReply(key, popped), nil
+}
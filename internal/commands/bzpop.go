@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// BZPopCommand implements BZPOPMIN and BZPOPMAX, which pop the member with
+// the lowest or highest score from the first non-empty sorted set of
+// several, blocking until there is one
+type BZPopCommand struct {
+	Keys    []string
+	Max     bool
+	Timeout time.Duration
+}
+
+func init() {
+	bzpop := func(name, summary string, max bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -3,
+			Flags:         []string{FlagWrite, FlagFast, FlagBlocking},
+			FirstKey:      1,
+			LastKey:       -2,
+			Step:          1,
+			ACLCategories: []string{"sortedset"},
+			KeySpecs:      []KeySpec{rangeKeys(1, -2, 1, KeyRW, KeyAccess, KeyDelete)},
+			Summary:       summary,
+			Since:         "5.0.0",
+			Group:         GroupSortedSet,
+			Complexity:    "O(log(N)) with N being the number of elements in the sorted set.",
+			Arguments: []Arg{
+				keyArg("key", 0).multiple(),
+				arg("timeout", ArgDouble),
+			},
+			Parse: func(args []string) (Command, error) {
+				timeout, err := parseTimeout(args[len(args)-1])
+				if err != nil {
+					return nil, err
+				}
+				return &BZPopCommand{Keys: args[1 : len(args)-1], Max: max, Timeout: timeout}, nil
+			},
+		}
+	}
+
+	Register(bzpop("bzpopmin", "Removes and returns the member with the lowest score from one or more sorted sets. Blocks until a member is available otherwise. Deletes the sorted set if the last element was popped.", false))
+	Register(bzpop("bzpopmax", "Removes and returns the member with the highest score from one or more sorted sets. Blocks until a member is available otherwise. Deletes the sorted set if the last element was popped.", true))
+}
+
+func (c *BZPopCommand) BlockKeys() []string         { return c.Keys }
+func (c *BZPopCommand) BlockTimeout() time.Duration { return c.Timeout }
+func (c *BZPopCommand) TimeoutReply() interface{}   { return []interface{}(nil) }
+
+// Execute replies with the key popped from, the member and its score, or
+// nil if all of the sorted sets are missing
+func (c *BZPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.ZMPop(c.Keys, c.Max, 1)
+	if err != nil || popped == nil {
+		return nil, err
+	}
+	return []interface{}{key, popped[0].Member, popped[0].Score}, nil
+}
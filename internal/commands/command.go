@@ -1,33 +1,352 @@
 package commands
 
 import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/glob"
 	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
 )
 
+// OK is the reply of commands that succeed without returning a value
+const OK = types.SimpleString("OK")
+
 type Command interface {
 	Execute(store store.Store) (interface{}, error)
 }
 
+// CommandCommand returns the COMMAND INFO reply for every command
 type CommandCommand struct{}
 
-func (c *CommandCommand) Execute(store store.Store) (interface{}, error) {
-	return []interface{}{
-		"set",
-		map[string]interface{}{
-			"arity":     3,
-			"flags":     []string{"write", "denyoom"},
-			"key_start": 1,
-			"key_end":   1,
-			"key_step":  1,
-		},
-		"get",
-		map[string]interface{}{
-			"arity":     2,
-			"flags":     []string{"readonly", "fast"},
-			"key_start": 1,
-			"key_end":   1,
-			"key_step":  1,
+// CommandCountCommand returns the number of commands
+type CommandCountCommand struct{}
+
+// CommandInfoCommand returns COMMAND INFO replies for the named commands, or
+// for all commands when Names is empty
+type CommandInfoCommand struct {
+	Names []string
+}
+
+// CommandDocsCommand returns documentation for the named commands, or for
+// all commands when Names is empty
+type CommandDocsCommand struct {
+	Names []string
+}
+
+// CommandGetKeysCommand extracts the keys of a full command line
+type CommandGetKeysCommand struct {
+	Args      []string
+	WithFlags bool
+}
+
+// CommandListCommand lists command names, optionally filtered
+type CommandListCommand struct {
+	FilterBy string // "", "MODULE", "ACLCAT" or "PATTERN"
+	Filter   string
+}
+
+// CommandHelpCommand describes the COMMAND subcommands
+type CommandHelpCommand struct{}
+
+func init() {
+	subcommandFlags := []string{FlagLoading, FlagStale}
+	commandCategories := []string{"connection"}
+
+	Register(&CommandSpec{
+		Name:          "command",
+		Arity:         -1,
+		Flags:         subcommandFlags,
+		ACLCategories: commandCategories,
+		Tips:          []string{"nondeterministic_output_order"},
+		Summary:       "Returns detailed information about all commands.",
+		Since:         "2.8.13",
+		Group:         GroupServer,
+		Complexity:    "O(N) where N is the total number of Redis commands",
+		Parse:         parseCommand,
+		Subcommands: []*CommandSpec{
+			{
+				Name:          "count",
+				Arity:         2,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Summary:       "Returns a count of commands.",
+				Since:         "2.8.13",
+				Group:         GroupServer,
+				Complexity:    "O(1)",
+				Parse:         parseCommandCount,
+			},
+			{
+				Name:          "docs",
+				Arity:         -2,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Tips:          []string{"nondeterministic_output_order"},
+				Summary:       "Returns documentary information about one, multiple or all commands.",
+				Since:         "7.0.0",
+				Group:         GroupServer,
+				Complexity:    "O(N) where N is the number of commands to look up",
+				Arguments:     []Arg{arg("command-name", ArgString).optional().multiple()},
+				Parse:         parseCommandDocs,
+			},
+			{
+				Name:          "getkeys",
+				Arity:         -3,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Summary:       "Extracts the key names from an arbitrary command.",
+				Since:         "2.8.13",
+				Group:         GroupServer,
+				Complexity:    "O(N) where N is the number of arguments to the command",
+				Arguments: []Arg{
+					arg("command", ArgString),
+					arg("arg", ArgString).optional().multiple(),
+				},
+				Parse: parseCommandGetKeys,
+			},
+			{
+				Name:          "getkeysandflags",
+				Arity:         -3,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Summary:       "Extracts the key names and access flags for an arbitrary command.",
+				Since:         "7.0.0",
+				Group:         GroupServer,
+				Complexity:    "O(N) where N is the number of arguments to the command",
+				Arguments: []Arg{
+					arg("command", ArgString),
+					arg("arg", ArgString).optional().multiple(),
+				},
+				Parse: parseCommandGetKeys,
+			},
+			{
+				Name:          "help",
+				Arity:         2,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Summary:       "Returns helpful text about the different subcommands.",
+				Since:         "5.0.0",
+				Group:         GroupServer,
+				Complexity:    "O(1)",
+				Parse:         parseCommandHelp,
+			},
+			{
+				Name:          "info",
+				Arity:         -2,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Tips:          []string{"nondeterministic_output_order"},
+				Summary:       "Returns information about one, multiple or all commands.",
+				Since:         "2.8.13",
+				Group:         GroupServer,
+				Complexity:    "O(N) where N is the number of commands to look up",
+				Arguments:     []Arg{arg("command-name", ArgString).optional().multiple()},
+				Parse:         parseCommandInfo,
+			},
+			{
+				Name:          "list",
+				Arity:         -2,
+				Flags:         subcommandFlags,
+				ACLCategories: commandCategories,
+				Tips:          []string{"nondeterministic_output_order"},
+				Summary:       "Returns a list of command names.",
+				Since:         "7.0.0",
+				Group:         GroupServer,
+				Complexity:    "O(N) where N is the total number of Redis commands",
+				Arguments: []Arg{
+					oneOf("filterby",
+						arg("module-name", ArgString).token("MODULE"),
+						arg("category", ArgString).token("ACLCAT"),
+						arg("pattern", ArgPattern).token("PATTERN"),
+					).token("FILTERBY").optional(),
+				},
+				Parse: parseCommandList,
+			},
 		},
-		// ...
+	})
+}
+
+func parseCommand(args []string) (Command, error) {
+	return &CommandCommand{}, nil
+}
+
+func parseCommandCount(args []string) (Command, error) {
+	return &CommandCountCommand{}, nil
+}
+
+func parseCommandInfo(args []string) (Command, error) {
+	return &CommandInfoCommand{Names: args[2:]}, nil
+}
+
+func parseCommandDocs(args []string) (Command, error) {
+	return &CommandDocsCommand{Names: args[2:]}, nil
+}
+
+func parseCommandGetKeys(args []string) (Command, error) {
+	return &CommandGetKeysCommand{
+		Args:      args[2:],
+		WithFlags: strings.EqualFold(args[1], "getkeysandflags"),
 	}, nil
 }
+
+func parseCommandList(args []string) (Command, error) {
+	if len(args) == 2 {
+		return &CommandListCommand{}, nil
+	}
+	if len(args) != 5 || !strings.EqualFold(args[2], "FILTERBY") {
+		return nil, errs.ErrSyntax
+	}
+
+	filterBy := strings.ToUpper(args[3])
+	switch filterBy {
+	case "MODULE", "ACLCAT", "PATTERN":
+	default:
+		return nil, errs.ErrSyntax
+	}
+
+	return &CommandListCommand{
+		FilterBy: filterBy,
+		Filter:   args[4],
+	}, nil
+}
+
+func parseCommandHelp(args []string) (Command, error) {
+	return &CommandHelpCommand{}, nil
+}
+
+func (c *CommandCommand) Execute(store store.Store) (interface{}, error) {
+	specs := All()
+	result := make([]interface{}, len(specs))
+	for i, spec := range specs {
+		result[i] = spec.infoReply()
+	}
+	return result, nil
+}
+
+func (c *CommandCountCommand) Execute(store store.Store) (interface{}, error) {
+	return len(All()), nil
+}
+
+func (c *CommandInfoCommand) Execute(store store.Store) (interface{}, error) {
+	if len(c.Names) == 0 {
+		return (&CommandCommand{}).Execute(store)
+	}
+
+	result := make([]interface{}, len(c.Names))
+	for i, name := range c.Names {
+		if spec, ok := Lookup(name); ok {
+			result[i] = spec.infoReply()
+		}
+	}
+	return result, nil
+}
+
+func (c *CommandDocsCommand) Execute(store store.Store) (interface{}, error) {
+	var specs []*CommandSpec
+	if len(c.Names) == 0 {
+		specs = All()
+	} else {
+		for _, name := range c.Names {
+			if spec, ok := Lookup(name); ok {
+				specs = append(specs, spec)
+			}
+		}
+	}
+
+	docs := make(types.Map, len(specs))
+	for i, spec := range specs {
+		docs[i] = types.MapEntry{Key: spec.Name, Value: spec.docsReply()}
+	}
+	return docs, nil
+}
+
+func (c *CommandGetKeysCommand) Execute(store store.Store) (interface{}, error) {
+	refs, err := getKeyReferences(c.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(refs))
+	for i, ref := range refs {
+		if !c.WithFlags {
+			result[i] = ref.Key
+			continue
+		}
+		flags := make(types.Set, len(ref.Flags))
+		for j, f := range ref.Flags {
+			flags[j] = types.SimpleString(f)
+		}
+		result[i] = []interface{}{ref.Key, flags}
+	}
+	return result, nil
+}
+
+func (c *CommandListCommand) Execute(store store.Store) (interface{}, error) {
+	result := make([]interface{}, 0)
+	var add func(spec *CommandSpec)
+	add = func(spec *CommandSpec) {
+		if c.matches(spec) {
+			result = append(result, spec.Name)
+		}
+		for _, sub := range spec.Subcommands {
+			add(sub)
+		}
+	}
+	for _, spec := range All() {
+		add(spec)
+	}
+	return result, nil
+}
+
+// matches reports whether spec passes the FILTERBY clause
+func (c *CommandListCommand) matches(spec *CommandSpec) bool {
+	switch c.FilterBy {
+	case "MODULE":
+		// Modules are not supported, so no command belongs to one
+		return false
+	case "ACLCAT":
+		category := strings.TrimPrefix(strings.ToLower(c.Filter), "@")
+		for _, cat := range spec.Categories() {
+			if cat == category {
+				return true
+			}
+		}
+		return false
+	case "PATTERN":
+		return glob.MatchNoCase(c.Filter, spec.Name)
+	default:
+		return true
+	}
+}
+
+func (c *CommandHelpCommand) Execute(store store.Store) (interface{}, error) {
+	lines := []string{
+		"COMMAND <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+		"(no subcommand)",
+		"    Return details about all Redis commands.",
+		"COUNT",
+		"    Return the total number of commands in this Redis server.",
+		"LIST",
+		"    Return a list of all commands in this Redis server.",
+		"INFO [<command-name> ...]",
+		"    Return details about multiple Redis commands.",
+		"    If no command names are given, documentation details for all",
+		"    commands are returned.",
+		"DOCS [<command-name> ...]",
+		"    Return documentation details about multiple Redis commands.",
+		"    If no command names are given, documentation details for all",
+		"    commands are returned.",
+		"GETKEYS <full-command>",
+		"    Return the keys from a full Redis command.",
+		"GETKEYSANDFLAGS <full-command>",
+		"    Return the keys and the access flags from a full Redis command.",
+		"HELP",
+		"    Print this help.",
+	}
+
+	result := make([]interface{}, len(lines))
+	for i, line := range lines {
+		result[i] = types.SimpleString(line)
+	}
+	return result, nil
+}
@@ -6,11 +6,40 @@ type DelCommand struct {
 	Keys []string
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "del",
+		Arity:         -2,
+		Flags:         []string{FlagWrite},
+		FirstKey:      1,
+		LastKey:       -1,
+		Step:          1,
+		ACLCategories: []string{"keyspace"},
+		Tips:          []string{"request_policy:multi_shard", "response_policy:agg_sum"},
+		KeySpecs:      []KeySpec{rangeKeys(1, -1, 1, KeyRM, KeyDelete)},
+		Summary:       "Deletes one or more keys.",
+		Since:         "1.0.0",
+		Group:         GroupGeneric,
+		Complexity:    "O(N) where N is the number of keys that will be removed. When a key to remove holds a value other than a string, the individual complexity for this key is O(M) where M is the number of elements in the list, set, sorted set or hash. Removing a single key that holds a string value is O(1).",
+		Arguments:     []Arg{keyArg("key", 0).multiple()},
+		Parse:         parseDel,
+	})
+}
+
+func parseDel(args []string) (Command, error) {
+	return &DelCommand{
+		Keys: args[1:],
+	}, nil
+}
+
 func (c *DelCommand) Execute(store store.Store) (interface{}, error) {
 	var deleted int
 	for _, key := range c.Keys {
-		err := store.Del(key)
-		if err == nil {
+		existed, err := store.Del(key)
+		if err != nil {
+			return nil, err
+		}
+		if existed {
 			deleted++
 		}
 	}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Argument types, as reported by COMMAND DOCS
+const (
+	ArgString    = "string"
+	ArgInteger   = "integer"
+	ArgDouble    = "double"
+	ArgKey       = "key"
+	ArgPattern   = "pattern"
+	ArgUnixTime  = "unix-time"
+	ArgPureToken = "pure-token"
+	ArgOneOf     = "oneof"
+	ArgBlock     = "block"
+)
+
+// Arg documents a single command argument. OneOf and block arguments hold
+// their alternatives or members in Args.
+type Arg struct {
+	Name          string
+	Type          string
+	KeySpecIndex  int
+	Token         string
+	Since         string
+	Optional      bool
+	Multiple      bool
+	MultipleToken bool
+	Args          []Arg
+}
+
+// arg returns a plain argument of the given type
+func arg(name, typ string) Arg {
+	return Arg{Name: name, Type: typ}
+}
+
+// keyArg returns a key argument found by the key spec at keySpecIndex
+func keyArg(name string, keySpecIndex int) Arg {
+	return Arg{Name: name, Type: ArgKey, KeySpecIndex: keySpecIndex}
+}
+
+// tokenArg returns a pure-token argument
+func tokenArg(name, token string) Arg {
+	return Arg{Name: name, Type: ArgPureToken, Token: token}
+}
+
+// oneOf returns an argument that takes exactly one of the given alternatives
+func oneOf(name string, args ...Arg) Arg {
+	return Arg{Name: name, Type: ArgOneOf, Args: args}
+}
+
+// block returns an argument made of several members in sequence
+func block(name string, args ...Arg) Arg {
+	return Arg{Name: name, Type: ArgBlock, Args: args}
+}
+
+func (a Arg) optional() Arg {
+	a.Optional = true
+	return a
+}
+
+func (a Arg) multiple() Arg {
+	a.Multiple = true
+	return a
+}
+
+func (a Arg) multipleToken() Arg {
+	a.MultipleToken = true
+	return a
+}
+
+func (a Arg) token(token string) Arg {
+	a.Token = token
+	return a
+}
+
+func (a Arg) since(version string) Arg {
+	a.Since = version
+	return a
+}
+
+// reply renders the argument the way COMMAND DOCS does
+func (a Arg) reply() types.Map {
+	doc := types.Map{
+		{Key: "name", Value: a.Name},
+		{Key: "type", Value: a.Type},
+	}
+	if a.Type != ArgOneOf && a.Type != ArgBlock {
+		doc = append(doc, types.MapEntry{Key: "display_text", Value: a.Name})
+	}
+	if a.Type == ArgKey {
+		doc = append(doc, types.MapEntry{Key: "key_spec_index", Value: a.KeySpecIndex})
+	}
+	if a.Token != "" {
+		doc = append(doc, types.MapEntry{Key: "token", Value: a.Token})
+	}
+	if a.Since != "" {
+		doc = append(doc, types.MapEntry{Key: "since", Value: a.Since})
+	}
+
+	var flags []interface{}
+	if a.Optional {
+		flags = append(flags, types.SimpleString("optional"))
+	}
+	if a.Multiple {
+		flags = append(flags, types.SimpleString("multiple"))
+	}
+	if a.MultipleToken {
+		flags = append(flags, types.SimpleString("multiple_token"))
+	}
+	if len(flags) > 0 {
+		doc = append(doc, types.MapEntry{Key: "flags", Value: flags})
+	}
+
+	if len(a.Args) > 0 {
+		args := make([]interface{}, len(a.Args))
+		for i, sub := range a.Args {
+			args[i] = sub.reply()
+		}
+		doc = append(doc, types.MapEntry{Key: "arguments", Value: args})
+	}
+	return doc
+}
+
+// infoReply renders the spec the way COMMAND INFO does
+func (s *CommandSpec) infoReply() []interface{} {
+	flags := make(types.Set, 0, len(s.Flags))
+	for _, f := range s.Flags {
+		flags = append(flags, types.SimpleString(f))
+	}
+
+	cats := s.Categories()
+	categories := make(types.Set, 0, len(cats))
+	for _, c := range cats {
+		categories = append(categories, types.SimpleString("@"+c))
+	}
+
+	tips := make(types.Set, 0, len(s.Tips))
+	for _, t := range s.Tips {
+		tips = append(tips, t)
+	}
+
+	keySpecs := make([]interface{}, len(s.KeySpecs))
+	for i, ks := range s.KeySpecs {
+		keySpecs[i] = ks.reply()
+	}
+
+	subcommands := make([]interface{}, len(s.Subcommands))
+	for i, sub := range s.Subcommands {
+		subcommands[i] = sub.infoReply()
+	}
+
+	return []interface{}{
+		s.Name,
+		s.Arity,
+		flags,
+		s.FirstKey,
+		s.LastKey,
+		s.Step,
+		categories,
+		tips,
+		keySpecs,
+		subcommands,
+	}
+}
+
+// docsReply renders the spec the way COMMAND DOCS does
+func (s *CommandSpec) docsReply() types.Map {
+	doc := types.Map{
+		{Key: "summary", Value: s.Summary},
+		{Key: "since", Value: s.Since},
+		{Key: "group", Value: s.Group},
+		{Key: "complexity", Value: s.Complexity},
+	}
+	if s.DeprecatedSince != "" {
+		doc = append(doc,
+			types.MapEntry{Key: "doc_flags", Value: types.Set{types.SimpleString("deprecated")}},
+			types.MapEntry{Key: "deprecated_since", Value: s.DeprecatedSince},
+			types.MapEntry{Key: "replaced_by", Value: s.ReplacedBy},
+		)
+	}
+
+	if len(s.Arguments) > 0 {
+		args := make([]interface{}, len(s.Arguments))
+		for i, a := range s.Arguments {
+			args[i] = a.reply()
+		}
+		doc = append(doc, types.MapEntry{Key: "arguments", Value: args})
+	}
+
+	if len(s.Subcommands) > 0 {
+		subcommands := make(types.Map, len(s.Subcommands))
+		for i, sub := range s.Subcommands {
+			subcommands[i] = types.MapEntry{Key: sub.Name, Value: sub.docsReply()}
+		}
+		doc = append(doc, types.MapEntry{Key: "subcommands", Value: subcommands})
+	}
+	return doc
+}
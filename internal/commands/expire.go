@@ -1,18 +1,134 @@
 package commands
 
 import (
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
 	"github.com/hardikphalet/go-redis/internal/store"
 )
 
+// ExpireCommand implements EXPIRE, PEXPIRE, EXPIREAT and PEXPIREAT, which
+// differ only in how the expiration time is given
 type ExpireCommand struct {
-	Key     string
-	TTL     time.Duration
-	Options *options.ExpireOptions
+	Key string
+	// When is a Unix time in milliseconds or, if Relative, a timeout
+	When     int64
+	Relative bool
+	Options  *options.ExpireOptions
+	// name is the command name for errors
+	name string
 }
 
+func init() {
+	expire := func(name, summary, since, timeArg, timeType string, unit time.Duration, absolute bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -3,
+			Flags:         []string{FlagWrite, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"keyspace"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupGeneric,
+			Complexity:    "O(1)",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg(timeArg, timeType),
+				oneOf("condition",
+					tokenArg("nx", "NX"),
+					tokenArg("xx", "XX"),
+					tokenArg("gt", "GT"),
+					tokenArg("lt", "LT"),
+				).optional().since("7.0.0"),
+			},
+			Parse: func(args []string) (Command, error) {
+				return parseExpire(args, unit, absolute)
+			},
+		}
+	}
+
+	Register(expire("expire", "Sets the expiration time of a key in seconds.",
+		"1.0.0", "seconds", ArgInteger, time.Second, false))
+	Register(expire("pexpire", "Sets the expiration time of a key in milliseconds.",
+		"2.6.0", "milliseconds", ArgInteger, time.Millisecond, false))
+	Register(expire("expireat", "Sets the expiration time of a key to a Unix timestamp.",
+		"1.2.0", "unix-time-seconds", ArgUnixTime, time.Second, true))
+	Register(expire("pexpireat", "Sets the expiration time of a key to a Unix milliseconds timestamp.",
+		"2.6.0", "unix-time-milliseconds", ArgUnixTime, time.Millisecond, true))
+}
+
+// parseExpire parses a timeout in unit or, when absolute, a Unix timestamp.
+// Like Redis, it works in milliseconds and rejects times that overflow them.
+func parseExpire(args []string, unit time.Duration, absolute bool) (Command, error) {
+	// Create options
+	opts := options.NewExpireOptions()
+
+	// Parse options, which Redis checks before the time
+	for i := 3; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "NX", "XX", "GT", "LT":
+		default:
+			return nil, errs.Errorf("Unsupported option %s", args[i])
+		}
+		if err := opts.Set(opt); err != nil {
+			if (opt == "GT" && opts.IsLT()) || (opt == "LT" && opts.IsGT()) {
+				return nil, errs.Errorf("GT and LT options at the same time are not compatible")
+			}
+			return nil, errs.Errorf("NX and XX, GT or LT options at the same time are not compatible")
+		}
+	}
+
+	when, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	name := strings.ToLower(args[0])
+	if unit == time.Second {
+		if when > math.MaxInt64/1000 || when < math.MinInt64/1000 {
+			return nil, invalidExpireTime(name)
+		}
+		when *= 1000
+	}
+
+	return &ExpireCommand{
+		Key:      args[1],
+		When:     when,
+		Relative: !absolute,
+		Options:  opts,
+		name:     name,
+	}, nil
+}
+
+func invalidExpireTime(command string) error {
+	return errs.Errorf("invalid expire time in '%s' command", command)
+}
+
+// Execute replies 1 if the timeout was set, and 0 if the key doesn't exist or
+// a condition wasn't met
 func (c *ExpireCommand) Execute(store store.Store) (interface{}, error) {
-	return nil, store.Expire(c.Key, c.TTL, c.Options)
+	when := c.When
+	if c.Relative {
+		now := store.Now().UnixMilli()
+		if when > math.MaxInt64-now {
+			return nil, invalidExpireTime(c.name)
+		}
+		when += now
+	}
+
+	set, err := store.Expire(c.Key, time.UnixMilli(when), c.Options)
+	if err != nil {
+		return nil, err
+	}
+	if !set {
+		return 0, nil
+	}
+	return 1, nil
 }
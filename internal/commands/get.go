@@ -6,6 +6,31 @@ type GetCommand struct {
 	Key string
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "get",
+		Arity:         2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns the string value of a key.",
+		Since:         "1.0.0",
+		Group:         GroupString,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse:         parseGet,
+	})
+}
+
+func parseGet(args []string) (Command, error) {
+	return &GetCommand{
+		Key: args[1],
+	}, nil
+}
+
 func (c *GetCommand) Execute(store store.Store) (interface{}, error) {
 	return store.Get(c.Key)
 }
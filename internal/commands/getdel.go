@@ -0,0 +1,36 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+type GetDelCommand struct {
+	Key string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "getdel",
+		Arity:         2,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyDelete)},
+		Summary:       "Returns the string value of a key after deleting the key.",
+		Since:         "6.2.0",
+		Group:         GroupString,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse:         parseGetDel,
+	})
+}
+
+func parseGetDel(args []string) (Command, error) {
+	return &GetDelCommand{
+		Key: args[1],
+	}, nil
+}
+
+func (c *GetDelCommand) Execute(store store.Store) (interface{}, error) {
+	return store.GetDel(c.Key)
+}
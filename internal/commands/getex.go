@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+type GetExCommand struct {
+	Key     string
+	Options *options.GetExOptions
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "getex",
+		Arity:         -2,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs: []KeySpec{{
+			Notes:      "RW and UPDATE because it changes the TTL",
+			Flags:      []string{KeyRW, KeyAccess, KeyUpdate},
+			BeginIndex: 1,
+			KeyStep:    1,
+		}},
+		Summary:    "Returns the string value of a key after setting its expiration time.",
+		Since:      "6.2.0",
+		Group:      GroupString,
+		Complexity: "O(1)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("expiration",
+				arg("seconds", ArgInteger).token("EX"),
+				arg("milliseconds", ArgInteger).token("PX"),
+				arg("unix-time-seconds", ArgUnixTime).token("EXAT"),
+				arg("unix-time-milliseconds", ArgUnixTime).token("PXAT"),
+				tokenArg("persist", "PERSIST"),
+			).optional(),
+		},
+		Parse: parseGetEx,
+	})
+}
+
+func parseGetEx(args []string) (Command, error) {
+	opts := options.NewGetExOptions()
+
+	for i := 2; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "EX", "PX", "EXAT", "PXAT", "PERSIST":
+		default:
+			return nil, errs.ErrSyntax
+		}
+		// Only one expiration option may be given
+		if opts.ExpiryType != "" {
+			return nil, errs.ErrSyntax
+		}
+
+		var value int64
+		if opt != "PERSIST" {
+			if i+1 >= len(args) {
+				return nil, errs.ErrSyntax
+			}
+			i++
+			var err error
+			if value, err = parseExpiryValue(args[0], args[i], opt == "EX" || opt == "EXAT"); err != nil {
+				return nil, err
+			}
+		}
+		if err := opts.SetExpiry(opt, value); err != nil {
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	return &GetExCommand{
+		Key:     args[1],
+		Options: opts,
+	}, nil
+}
+
+// Execute replies with the value, or nil for a missing key
+func (c *GetExCommand) Execute(store store.Store) (interface{}, error) {
+	if c.Options.Overflows(store.Now()) {
+		return nil, invalidExpireTime("getex")
+	}
+	return store.GetEx(c.Key, c.Options)
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// GetRangeCommand implements GETRANGE and its old name SUBSTR
+type GetRangeCommand struct {
+	Key   string
+	Start int64
+	End   int64
+}
+
+func init() {
+	getRange := func(name, summary, since string) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         4,
+			Flags:         []string{FlagReadOnly},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"string"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupString,
+			Complexity:    "O(N) where N is the length of the returned string. The complexity is ultimately determined by the returned length, but because creating a substring from an existing string is very cheap, it can be considered O(1) for small strings.",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("start", ArgInteger),
+				arg("end", ArgInteger),
+			},
+			Parse: parseGetRange,
+		}
+	}
+
+	Register(getRange("getrange", "Returns a substring of the string stored at a key.", "2.4.0"))
+
+	substr := getRange("substr", "Returns a substring from a string value.", "1.0.0")
+	substr.DeprecatedSince = "2.0.0"
+	substr.ReplacedBy = "`GETRANGE`"
+	Register(substr)
+}
+
+func parseGetRange(args []string) (Command, error) {
+	start, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	end, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	return &GetRangeCommand{
+		Key:   args[1],
+		Start: start,
+		End:   end,
+	}, nil
+}
+
+// Execute replies with the bytes from Start to End, both included. Negative
+// offsets count from the end of the string, and offsets out of range are
+// clamped to it.
+func (c *GetRangeCommand) Execute(store store.Store) (interface{}, error) {
+	val, err := store.Get(c.Key)
+	if err != nil || val == nil {
+		return "", err
+	}
+	s := val.(string)
+
+	start, end, n := c.Start, c.End, int64(len(s))
+	if start < 0 && end < 0 && start > end {
+		return "", nil
+	}
+	if start < 0 {
+		start += n
+	}
+	if end < 0 {
+		end += n
+	}
+	start, end = max(start, 0), min(max(end, 0), n-1)
+	if start > end {
+		return "", nil
+	}
+	return s[start : end+1], nil
+}
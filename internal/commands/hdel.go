@@ -0,0 +1,40 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+// HDelCommand deletes fields of a hash
+type HDelCommand struct {
+	Key    string
+	Fields []string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "hdel",
+		Arity:         -3,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyDelete)},
+		Summary:       "Deletes one or more fields and their values from a hash. Deletes the hash if no fields remain.",
+		Since:         "2.0.0",
+		Group:         GroupHash,
+		Complexity:    "O(N) where N is the number of fields to be removed.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("field", ArgString).multiple(),
+		},
+		Parse: parseHDel,
+	})
+}
+
+func parseHDel(args []string) (Command, error) {
+	return &HDelCommand{Key: args[1], Fields: args[2:]}, nil
+}
+
+// Execute replies with the number of fields deleted
+func (c *HDelCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HDel(c.Key, c.Fields)
+}
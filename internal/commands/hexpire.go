@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// HExpireCommand implements HEXPIRE, HPEXPIRE, HEXPIREAT and HPEXPIREAT,
+// which set timeouts on hash fields and differ only in how the time is given
+type HExpireCommand struct {
+	Key string
+	// When is a Unix time in milliseconds or, if Relative, a timeout
+	When     int64
+	Relative bool
+	Options  *options.ExpireOptions
+	Fields   []string
+	// name is the command name for errors
+	name string
+}
+
+// hashFieldMaxExpire is the latest Unix time in milliseconds a hash field
+// may expire at
+const hashFieldMaxExpire = store.HashFieldMaxExpire
+
+// fieldsArg is the FIELDS numfields field... argument of the hash field
+// expiration commands
+func fieldsArg() Arg {
+	return block("fields",
+		arg("numfields", ArgInteger),
+		arg("field", ArgString).multiple(),
+	).token("FIELDS")
+}
+
+func init() {
+	hexpire := func(name, summary, timeArg, timeType string, unit time.Duration, absolute bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -6,
+			Flags:         []string{FlagWrite, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+			Summary:       summary,
+			Since:         "7.4.0",
+			Group:         GroupHash,
+			Complexity:    "O(N) where N is the number of specified fields",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg(timeArg, timeType),
+				oneOf("condition",
+					tokenArg("nx", "NX"),
+					tokenArg("xx", "XX"),
+					tokenArg("gt", "GT"),
+					tokenArg("lt", "LT"),
+				).optional(),
+				fieldsArg(),
+			},
+			Parse: func(args []string) (Command, error) {
+				return parseHExpire(args, unit, absolute)
+			},
+		}
+	}
+
+	Register(hexpire("hexpire", "Set expiry for hash field using relative time to expire (seconds)",
+		"seconds", ArgInteger, time.Second, false))
+	Register(hexpire("hpexpire", "Set expiry for hash field using relative time to expire (milliseconds)",
+		"milliseconds", ArgInteger, time.Millisecond, false))
+	Register(hexpire("hexpireat", "Set expiry for hash field using an absolute Unix timestamp (seconds)",
+		"unix-time-seconds", ArgUnixTime, time.Second, true))
+	Register(hexpire("hpexpireat", "Set expiry for hash field using an absolute Unix timestamp (milliseconds)",
+		"unix-time-milliseconds", ArgUnixTime, time.Millisecond, true))
+}
+
+// parseFields parses the FIELDS numfields argument at args[at] and returns
+// the arguments that follow it, which must be numfields times perField
+func parseFields(args []string, at, perField int) ([]string, error) {
+	if at >= len(args) || !strings.EqualFold(args[at], "FIELDS") {
+		return nil, errs.Errorf("Mandatory argument FIELDS is missing or not at the right position")
+	}
+	if at+1 >= len(args) {
+		return nil, errWrongArgs(args[0])
+	}
+	n, ok := store.ParseInt(args[at+1])
+	if !ok || n < 1 {
+		return nil, errs.Errorf("Parameter `numFields` should be greater than 0")
+	}
+	rest := args[at+2:]
+	if n > int64(len(rest)) || int(n)*perField != len(rest) {
+		return nil, errs.Errorf("The `numfields` parameter must match the number of arguments")
+	}
+	return rest, nil
+}
+
+// parseHExpire parses a timeout in unit or, when absolute, a Unix timestamp,
+// which must fit the 48 bits Redis keeps field timeouts in
+func parseHExpire(args []string, unit time.Duration, absolute bool) (Command, error) {
+	name := strings.ToLower(args[0])
+	when, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	if when < 0 {
+		return nil, errs.Errorf("invalid expire time, must be >= 0")
+	}
+	if unit == time.Second {
+		if when > hashFieldMaxExpire/1000 {
+			return nil, invalidExpireTime(name)
+		}
+		when *= 1000
+	}
+	if absolute && when > hashFieldMaxExpire {
+		return nil, invalidExpireTime(name)
+	}
+
+	opts := options.NewExpireOptions()
+	fieldsAt := 3
+	switch cond := strings.ToUpper(args[3]); cond {
+	case "NX", "XX", "GT", "LT":
+		if err := opts.Set(cond); err != nil {
+			return nil, errs.ErrSyntax
+		}
+		fieldsAt++
+	}
+	fields, err := parseFields(args, fieldsAt, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HExpireCommand{
+		Key:      args[1],
+		When:     when,
+		Relative: !absolute,
+		Options:  opts,
+		Fields:   fields,
+		name:     name,
+	}, nil
+}
+
+// intsReply is an array reply of integers
+func intsReply[T int | int64](ns []T) []interface{} {
+	items := make([]interface{}, len(ns))
+	for i, n := range ns {
+		items[i] = n
+	}
+	return items
+}
+
+// Execute replies with an array of one code per field: -2 if the field
+// doesn't exist, 0 if the condition wasn't met, 1 if the timeout was set
+// and 2 if the field was deleted because the time is already past
+func (c *HExpireCommand) Execute(store store.Store) (interface{}, error) {
+	when := c.When
+	if c.Relative {
+		now := store.Now().UnixMilli()
+		if when > hashFieldMaxExpire-now {
+			return nil, invalidExpireTime(c.name)
+		}
+		when += now
+	}
+
+	results, err := store.HExpire(c.Key, time.UnixMilli(when), c.Options, c.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return intsReply(results), nil
+}
@@ -0,0 +1,114 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+// HGetCommand returns the value of a field of a hash
+type HGetCommand struct {
+	Key   string
+	Field string
+}
+
+// HMGetCommand returns the values of several fields of a hash
+type HMGetCommand struct {
+	Key    string
+	Fields []string
+}
+
+// HExistsCommand reports whether a field of a hash exists
+type HExistsCommand struct {
+	Key   string
+	Field string
+}
+
+// HStrLenCommand returns the length of the value of a field of a hash
+type HStrLenCommand struct {
+	Key   string
+	Field string
+}
+
+func init() {
+	hget := func(name, summary, since string, parse ParseFunc) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         3,
+			Flags:         []string{FlagReadOnly, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupHash,
+			Complexity:    "O(1)",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("field", ArgString),
+			},
+			Parse: parse,
+		}
+	}
+
+	Register(hget("hget", "Returns the value of a field in a hash.", "2.0.0",
+		func(args []string) (Command, error) {
+			return &HGetCommand{Key: args[1], Field: args[2]}, nil
+		}))
+	Register(hget("hexists", "Determines whether a field exists in a hash.", "2.0.0",
+		func(args []string) (Command, error) {
+			return &HExistsCommand{Key: args[1], Field: args[2]}, nil
+		}))
+	Register(hget("hstrlen", "Returns the length of the value of a field.", "3.2.0",
+		func(args []string) (Command, error) {
+			return &HStrLenCommand{Key: args[1], Field: args[2]}, nil
+		}))
+
+	Register(&CommandSpec{
+		Name:          "hmget",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns the values of all fields in a hash.",
+		Since:         "2.0.0",
+		Group:         GroupHash,
+		Complexity:    "O(N) where N is the number of fields being requested.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("field", ArgString).multiple(),
+		},
+		Parse: func(args []string) (Command, error) {
+			return &HMGetCommand{Key: args[1], Fields: args[2:]}, nil
+		},
+	})
+}
+
+// Execute replies with the value, or nil if the field or key is missing
+func (c *HGetCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HGet(c.Key, c.Field)
+}
+
+// Execute replies with the values, nil for the missing fields
+func (c *HMGetCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HMGet(c.Key, c.Fields)
+}
+
+// Execute replies 1 if the field exists and 0 otherwise
+func (c *HExistsCommand) Execute(store store.Store) (interface{}, error) {
+	val, err := store.HGet(c.Key, c.Field)
+	if err != nil || val == nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Execute replies with the length of the value, 0 for a missing field
+func (c *HStrLenCommand) Execute(store store.Store) (interface{}, error) {
+	val, err := store.HGet(c.Key, c.Field)
+	if err != nil || val == nil {
+		return 0, err
+	}
+	return len(val.(string)), nil
+}
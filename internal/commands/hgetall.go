@@ -0,0 +1,89 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+// HGetAllCommand implements HGETALL, HKEYS and HVALS, which return the
+// fields of a hash, their values or both
+type HGetAllCommand struct {
+	Key    string
+	Fields bool
+	Values bool
+}
+
+// HLenCommand returns the number of fields of a hash
+type HLenCommand struct {
+	Key string
+}
+
+func init() {
+	hgetall := func(name, summary string, fields, values bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         2,
+			Flags:         []string{FlagReadOnly},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			Tips:          []string{"nondeterministic_output_order"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+			Summary:       summary,
+			Since:         "2.0.0",
+			Group:         GroupHash,
+			Complexity:    "O(N) where N is the size of the hash.",
+			Arguments:     []Arg{keyArg("key", 0)},
+			Parse: func(args []string) (Command, error) {
+				return &HGetAllCommand{Key: args[1], Fields: fields, Values: values}, nil
+			},
+		}
+	}
+
+	Register(hgetall("hgetall", "Returns all fields and values in a hash.", true, true))
+	Register(hgetall("hkeys", "Returns all fields in a hash.", true, false))
+	Register(hgetall("hvals", "Returns all values in a hash.", false, true))
+
+	Register(&CommandSpec{
+		Name:          "hlen",
+		Arity:         2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO)},
+		Summary:       "Returns the number of fields in a hash.",
+		Since:         "2.0.0",
+		Group:         GroupHash,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse: func(args []string) (Command, error) {
+			return &HLenCommand{Key: args[1]}, nil
+		},
+	})
+}
+
+// Execute replies with a map of fields to values for HGETALL, and an array
+// of the fields or values for HKEYS and HVALS
+func (c *HGetAllCommand) Execute(store store.Store) (interface{}, error) {
+	entries, err := store.HGetAll(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if c.Fields && c.Values {
+		return entries, nil
+	}
+	items := make([]interface{}, len(entries))
+	for i, e := range entries {
+		if c.Fields {
+			items[i] = e.Key
+		} else {
+			items[i] = e.Value
+		}
+	}
+	return items, nil
+}
+
+// Execute replies with the number of fields, 0 for a missing key
+func (c *HLenCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HLen(c.Key)
+}
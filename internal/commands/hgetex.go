@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// HGetExCommand returns fields of a hash and optionally sets or removes
+// their timeouts
+type HGetExCommand struct {
+	Key     string
+	Fields  []string
+	Options *options.GetExOptions
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "hgetex",
+		Arity:         -5,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs: []KeySpec{{
+			Notes:      "RW and UPDATE because it changes the TTL",
+			Flags:      []string{KeyRW, KeyAccess, KeyUpdate},
+			BeginIndex: 1,
+			KeyStep:    1,
+		}},
+		Summary:    "Get the value of one or more fields of a given hash key, and optionally set their expiration.",
+		Since:      "8.0.0",
+		Group:      GroupHash,
+		Complexity: "O(N) where N is the number of specified fields",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("expiration",
+				arg("seconds", ArgInteger).token("EX"),
+				arg("milliseconds", ArgInteger).token("PX"),
+				arg("unix-time-seconds", ArgUnixTime).token("EXAT"),
+				arg("unix-time-milliseconds", ArgUnixTime).token("PXAT"),
+				tokenArg("persist", "PERSIST"),
+			).optional(),
+			fieldsArg(),
+		},
+		Parse: parseHGetEx,
+	})
+}
+
+func parseHGetEx(args []string) (Command, error) {
+	opts := options.NewGetExOptions()
+
+	for i := 2; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "FIELDS":
+			fields, err := parseFields(args, i, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &HGetExCommand{Key: args[1], Fields: fields, Options: opts}, nil
+		case "EX", "PX", "EXAT", "PXAT", "PERSIST":
+		default:
+			return nil, errs.ErrSyntax
+		}
+		if opts.ExpiryType != "" {
+			return nil, errs.Errorf("Only one of EX, PX, EXAT, PXAT or PERSIST arguments can be specified")
+		}
+
+		var value int64
+		if opt != "PERSIST" {
+			if i+1 >= len(args) {
+				return nil, errs.ErrSyntax
+			}
+			i++
+			var err error
+			if value, err = parseExpiryValue(args[0], args[i], opt == "EX" || opt == "EXAT"); err != nil {
+				return nil, err
+			}
+		}
+		if err := opts.SetExpiry(opt, value); err != nil {
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	return nil, errs.Errorf("Mandatory argument FIELDS is missing or not at the right position")
+}
+
+// Execute replies with an array of the values of the fields, nil for the
+// ones missing
+func (c *HGetExCommand) Execute(store store.Store) (interface{}, error) {
+	now := store.Now()
+	if c.Options.Overflows(now) || (c.Options.ExpiryType != "" && !c.Options.IsPERSIST() &&
+		c.Options.ExpiryTime(now).UnixMilli() > hashFieldMaxExpire) {
+		return nil, invalidExpireTime("hgetex")
+	}
+	return store.HGetEx(c.Key, c.Fields, c.Options)
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// HIncrByCommand adds an increment to an integer field of a hash
+type HIncrByCommand struct {
+	Key       string
+	Field     string
+	Increment int64
+}
+
+// HIncrByFloatCommand adds an increment to a floating point field of a hash
+type HIncrByFloatCommand struct {
+	Key       string
+	Field     string
+	Increment float64
+}
+
+func init() {
+	hincr := func(name, summary, since, typ string, parse ParseFunc) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         4,
+			Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyUpdate)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupHash,
+			Complexity:    "O(1)",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("field", ArgString),
+				arg("increment", typ),
+			},
+			Parse: parse,
+		}
+	}
+
+	Register(hincr("hincrby", "Increments the integer value of a field in a hash by a number. Uses 0 as initial value if the field doesn't exist.",
+		"2.0.0", ArgInteger, parseHIncrBy))
+	Register(hincr("hincrbyfloat", "Increments the floating point value of a field by a number. Uses 0 as initial value if the field doesn't exist.",
+		"2.6.0", ArgDouble, parseHIncrByFloat))
+}
+
+func parseHIncrBy(args []string) (Command, error) {
+	increment, ok := store.ParseInt(args[3])
+	if !ok {
+		return nil, errs.ErrNotInteger
+	}
+	return &HIncrByCommand{Key: args[1], Field: args[2], Increment: increment}, nil
+}
+
+func parseHIncrByFloat(args []string) (Command, error) {
+	increment, ok := store.ParseFloat(args[3])
+	if !ok {
+		return nil, errs.ErrNotFloat
+	}
+	return &HIncrByFloatCommand{Key: args[1], Field: args[2], Increment: increment}, nil
+}
+
+// Execute replies with the new value
+func (c *HIncrByCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HIncrBy(c.Key, c.Field, c.Increment)
+}
+
+// Execute replies with the new value, as a bulk string
+func (c *HIncrByFloatCommand) Execute(store store.Store) (interface{}, error) {
+	return store.HIncrByFloat(c.Key, c.Field, c.Increment)
+}
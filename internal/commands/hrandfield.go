@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// HRandFieldCommand returns random fields of a hash
+type HRandFieldCommand struct {
+	Key string
+	// Count is the number of fields, negative if they may repeat. Without
+	// HasCount a single field is returned rather than an array.
+	Count      int64
+	HasCount   bool
+	WithValues bool
+}
+
+// hrandfieldMaxCount bounds negative counts, whose replies are built in
+// full before being written. Redis streams them instead and relies on the
+// client output buffer limits; here a single request must not be able to
+// make the server allocate gigabytes.
+const hrandfieldMaxCount = 1024 * 1024
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "hrandfield",
+		Arity:         -2,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		Tips:          []string{"nondeterministic_output"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns one or more random fields from a hash.",
+		Since:         "6.2.0",
+		Group:         GroupHash,
+		Complexity:    "O(N) where N is the number of fields returned",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			block("options",
+				arg("count", ArgInteger),
+				tokenArg("withvalues", "WITHVALUES").optional(),
+			).optional(),
+		},
+		Parse: parseHRandField,
+	})
+}
+
+func parseHRandField(args []string) (Command, error) {
+	cmd := &HRandFieldCommand{Key: args[1], Count: 1}
+	if len(args) == 2 {
+		return cmd, nil
+	}
+	if len(args) > 4 || (len(args) == 4 && !strings.EqualFold(args[3], "withvalues")) {
+		return nil, errs.ErrSyntax
+	}
+
+	count, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	if count == math.MinInt64 || -count > hrandfieldMaxCount {
+		return nil, errs.Errorf("value is out of range")
+	}
+	cmd.Count = count
+	cmd.HasCount = true
+	cmd.WithValues = len(args) == 4
+	return cmd, nil
+}
+
+// Execute replies with a field, or nil for a missing key, or with a count
+// an array of fields, paired with their values with WITHVALUES
+func (c *HRandFieldCommand) Execute(store store.Store) (interface{}, error) {
+	entries, err := store.HRandField(c.Key, c.Count)
+	if err != nil {
+		return nil, err
+	}
+	if !c.HasCount {
+		if len(entries) == 0 {
+			return nil, nil
+		}
+		return entries[0].Key, nil
+	}
+
+	items := make([]interface{}, 0, len(entries)*2)
+	for _, e := range entries {
+		items = append(items, e.Key)
+		if c.WithValues {
+			items = append(items, e.Value)
+		}
+	}
+	if c.WithValues {
+		return types.Pairs(items), nil
+	}
+	return items, nil
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// HSetCommand implements HSET and its older form HMSET, which differ only in
+// their reply
+type HSetCommand struct {
+	Key string
+	// FieldValues alternates fields and their values
+	FieldValues []string
+	// ReplyOK is set for HMSET
+	ReplyOK bool
+}
+
+// HSetNXCommand sets a field of a hash only if it doesn't exist
+type HSetNXCommand struct {
+	Key   string
+	Field string
+	Value string
+}
+
+func init() {
+	hset := func(name, summary, complexity string) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -4,
+			Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+			Summary:       summary,
+			Since:         "2.0.0",
+			Group:         GroupHash,
+			Complexity:    complexity,
+			Arguments: []Arg{
+				keyArg("key", 0),
+				block("data",
+					arg("field", ArgString),
+					arg("value", ArgString),
+				).multiple(),
+			},
+			Parse: parseHSet,
+		}
+	}
+
+	Register(hset("hset", "Creates or modifies the value of a field in a hash.",
+		"O(1) for each field/value pair added, so O(N) to add N field/value pairs when the command is called with multiple field/value pairs."))
+
+	hmset := hset("hmset", "Sets the values of multiple fields.",
+		"O(N) where N is the number of fields being set.")
+	hmset.DeprecatedSince = "4.0.0"
+	hmset.ReplacedBy = "`HSET` with multiple field-value pairs"
+	Register(hmset)
+
+	Register(&CommandSpec{
+		Name:          "hsetnx",
+		Arity:         4,
+		Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyInsert)},
+		Summary:       "Sets the value of a field in a hash only when the field doesn't exist.",
+		Since:         "2.0.0",
+		Group:         GroupHash,
+		Complexity:    "O(1)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("field", ArgString),
+			arg("value", ArgString),
+		},
+		Parse: parseHSetNX,
+	})
+}
+
+func parseHSet(args []string) (Command, error) {
+	// Fields and values come in pairs
+	if len(args)%2 == 1 {
+		return nil, errWrongArgs(args[0])
+	}
+	return &HSetCommand{
+		Key:         args[1],
+		FieldValues: args[2:],
+		ReplyOK:     strings.EqualFold(args[0], "hmset"),
+	}, nil
+}
+
+func parseHSetNX(args []string) (Command, error) {
+	return &HSetNXCommand{Key: args[1], Field: args[2], Value: args[3]}, nil
+}
+
+// Execute replies with the number of fields added, or OK for HMSET
+func (c *HSetCommand) Execute(store store.Store) (interface{}, error) {
+	added, err := store.HSet(c.Key, c.FieldValues)
+	if err != nil {
+		return nil, err
+	}
+	if c.ReplyOK {
+		return OK, nil
+	}
+	return added, nil
+}
+
+// Execute replies 1 if the field was set and 0 if it already existed
+func (c *HSetNXCommand) Execute(store store.Store) (interface{}, error) {
+	set, err := store.HSetNX(c.Key, c.Field, c.Value)
+	if err != nil {
+		return nil, err
+	}
+	if !set {
+		return 0, nil
+	}
+	return 1, nil
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// HSetExCommand sets fields of a hash and optionally their timeouts
+type HSetExCommand struct {
+	Key string
+	// FieldValues alternates fields and values
+	FieldValues []string
+	Options     *options.HSetExOptions
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "hsetex",
+		Arity:         -6,
+		Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Set the value of one or more fields of a given hash key, and optionally set their expiration.",
+		Since:         "8.0.0",
+		Group:         GroupHash,
+		Complexity:    "O(N) where N is the number of fields being set.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("condition",
+				tokenArg("fnx", "FNX"),
+				tokenArg("fxx", "FXX"),
+			).optional(),
+			oneOf("expiration",
+				arg("seconds", ArgInteger).token("EX"),
+				arg("milliseconds", ArgInteger).token("PX"),
+				arg("unix-time-seconds", ArgUnixTime).token("EXAT"),
+				arg("unix-time-milliseconds", ArgUnixTime).token("PXAT"),
+				tokenArg("keepttl", "KEEPTTL"),
+			).optional(),
+			block("fields",
+				arg("numfields", ArgInteger),
+				block("data",
+					arg("field", ArgString),
+					arg("value", ArgString),
+				).multiple(),
+			).token("FIELDS"),
+		},
+		Parse: parseHSetEx,
+	})
+}
+
+func parseHSetEx(args []string) (Command, error) {
+	opts := options.NewHSetExOptions()
+
+	for i := 2; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "FIELDS":
+			fieldValues, err := parseFields(args, i, 2)
+			if err != nil {
+				return nil, err
+			}
+			return &HSetExCommand{Key: args[1], FieldValues: fieldValues, Options: opts}, nil
+		case "FNX", "FXX":
+			if opts.Condition != "" {
+				return nil, errs.Errorf("Only one of FXX or FNX arguments can be specified")
+			}
+			if err := opts.SetCondition(opt); err != nil {
+				return nil, errs.ErrSyntax
+			}
+			continue
+		case "EX", "PX", "EXAT", "PXAT", "KEEPTTL":
+		default:
+			return nil, errs.ErrSyntax
+		}
+		if opts.ExpiryType != "" {
+			return nil, errs.Errorf("Only one of EX, PX, EXAT, PXAT or KEEPTTL arguments can be specified")
+		}
+
+		var value int64
+		if opt != "KEEPTTL" {
+			if i+1 >= len(args) {
+				return nil, errs.ErrSyntax
+			}
+			i++
+			var err error
+			if value, err = parseExpiryValue(args[0], args[i], opt == "EX" || opt == "EXAT"); err != nil {
+				return nil, err
+			}
+		}
+		if err := opts.SetExpiry(opt, value); err != nil {
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	return nil, errs.Errorf("Mandatory argument FIELDS is missing or not at the right position")
+}
+
+// Execute replies 1 if the fields were set, or 0 when FNX or FXX prevented
+// it
+func (c *HSetExCommand) Execute(store store.Store) (interface{}, error) {
+	now := store.Now()
+	if c.Options.Overflows(now) || (c.Options.ExpiryType != "" && !c.Options.IsKEEPTTL() &&
+		c.Options.ExpiryTime(now).UnixMilli() > hashFieldMaxExpire) {
+		return nil, invalidExpireTime("hsetex")
+	}
+	ok, err := store.HSetEx(c.Key, c.FieldValues, c.Options)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+// HTtlCommand implements HTTL, HPTTL, HEXPIRETIME and HPEXPIRETIME, which
+// report the expiration of hash fields as a time to live or as a Unix
+// timestamp, in seconds or milliseconds
+type HTtlCommand struct {
+	Key          string
+	Fields       []string
+	Milliseconds bool
+	Absolute     bool
+}
+
+// HPersistCommand removes the timeouts of hash fields
+type HPersistCommand struct {
+	Key    string
+	Fields []string
+}
+
+func init() {
+	httl := func(name, summary string, milliseconds, absolute bool) *CommandSpec {
+		spec := &CommandSpec{
+			Name:          name,
+			Arity:         -5,
+			Flags:         []string{FlagReadOnly, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"hash"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+			Summary:       summary,
+			Since:         "7.4.0",
+			Group:         GroupHash,
+			Complexity:    "O(N) where N is the number of specified fields",
+			Arguments:     []Arg{keyArg("key", 0), fieldsArg()},
+			Parse: func(args []string) (Command, error) {
+				fields, err := parseFields(args, 2, 1)
+				if err != nil {
+					return nil, err
+				}
+				return &HTtlCommand{Key: args[1], Fields: fields, Milliseconds: milliseconds, Absolute: absolute}, nil
+			},
+		}
+		// A time to live changes from one call to the next
+		if !absolute {
+			spec.Tips = []string{"nondeterministic_output"}
+		}
+		return spec
+	}
+
+	Register(httl("httl", "Returns the TTL in seconds of a hash field.", false, false))
+	Register(httl("hpttl", "Returns the TTL in milliseconds of a hash field.", true, false))
+	Register(httl("hexpiretime", "Returns the expiration time of a hash field as a Unix timestamp, in seconds.", false, true))
+	Register(httl("hpexpiretime", "Returns the expiration time of a hash field as a Unix timestamp, in msec.", true, true))
+
+	Register(&CommandSpec{
+		Name:          "hpersist",
+		Arity:         -5,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Removes the expiration time for each specified field",
+		Since:         "7.4.0",
+		Group:         GroupHash,
+		Complexity:    "O(N) where N is the number of specified fields",
+		Arguments:     []Arg{keyArg("key", 0), fieldsArg()},
+		Parse: func(args []string) (Command, error) {
+			fields, err := parseFields(args, 2, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &HPersistCommand{Key: args[1], Fields: fields}, nil
+		},
+	})
+}
+
+// Execute replies with an array of one value per field, -2 if the field
+// doesn't exist and -1 if it has no timeout. Unlike TTL, seconds are rounded
+// up, as Redis does for fields.
+func (c *HTtlCommand) Execute(store store.Store) (interface{}, error) {
+	times, err := store.HExpireTime(c.Key, c.Fields)
+	if err != nil {
+		return nil, err
+	}
+	now := store.Now().UnixMilli()
+	for i, ms := range times {
+		if ms < 0 {
+			continue
+		}
+		if !c.Absolute {
+			ms = max(ms-now, 0)
+		}
+		if !c.Milliseconds {
+			ms = (ms + 999) / 1000
+		}
+		times[i] = ms
+	}
+	return intsReply(times), nil
+}
+
+// Execute replies with an array of one code per field: -2 if the field
+// doesn't exist, -1 if it has no timeout and 1 if the timeout was removed
+func (c *HPersistCommand) Execute(store store.Store) (interface{}, error) {
+	results, err := store.HPersist(c.Key, c.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return intsReply(results), nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"math"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// IncrByCommand implements INCR, DECR, INCRBY and DECRBY, which all add an
+// increment to an integer
+type IncrByCommand struct {
+	Key       string
+	Increment int64
+}
+
+// IncrByFloatCommand adds an increment to a floating point number
+type IncrByFloatCommand struct {
+	Key       string
+	Increment float64
+}
+
+func init() {
+	incr := func(name, summary, since string, args []Arg, parse ParseFunc) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         len(args) + 1,
+			Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"string"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyUpdate)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupString,
+			Complexity:    "O(1)",
+			Arguments:     args,
+			Parse:         parse,
+		}
+	}
+
+	Register(incr("incr", "Increments the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+		"1.0.0", []Arg{keyArg("key", 0)}, parseIncr))
+	Register(incr("decr", "Decrements the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+		"1.0.0", []Arg{keyArg("key", 0)}, parseIncr))
+	Register(incr("incrby", "Increments the integer value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+		"1.0.0", []Arg{keyArg("key", 0), arg("increment", ArgInteger)}, parseIncr))
+	Register(incr("decrby", "Decrements a number from the integer value of a key. Uses 0 as initial value if the key doesn't exist.",
+		"1.0.0", []Arg{keyArg("key", 0), arg("decrement", ArgInteger)}, parseIncr))
+	Register(incr("incrbyfloat", "Increment the floating point value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+		"2.6.0", []Arg{keyArg("key", 0), arg("increment", ArgDouble)}, parseIncrByFloat))
+}
+
+func parseIncr(args []string) (Command, error) {
+	name := strings.ToLower(args[0])
+	increment := int64(1)
+	if len(args) == 3 {
+		var ok bool
+		if increment, ok = store.ParseInt(args[2]); !ok {
+			return nil, errs.ErrNotInteger
+		}
+	}
+	if strings.HasPrefix(name, "decr") {
+		// Negating the smallest integer overflows
+		if increment == math.MinInt64 {
+			return nil, errs.Errorf("decrement would overflow")
+		}
+		increment = -increment
+	}
+	return &IncrByCommand{Key: args[1], Increment: increment}, nil
+}
+
+func parseIncrByFloat(args []string) (Command, error) {
+	increment, ok := store.ParseFloat(args[2])
+	if !ok {
+		return nil, errs.ErrNotFloat
+	}
+	return &IncrByFloatCommand{Key: args[1], Increment: increment}, nil
+}
+
+// Execute replies with the new value
+func (c *IncrByCommand) Execute(store store.Store) (interface{}, error) {
+	return store.IncrBy(c.Key, c.Increment)
+}
+
+// Execute replies with the new value, as a bulk string
+func (c *IncrByFloatCommand) Execute(store store.Store) (interface{}, error) {
+	return store.IncrByFloat(c.Key, c.Increment)
+}
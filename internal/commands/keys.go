@@ -6,6 +6,28 @@ type KeysCommand struct {
 	Pattern string
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "keys",
+		Arity:         2,
+		Flags:         []string{FlagReadOnly},
+		ACLCategories: []string{"keyspace", "dangerous"},
+		Tips:          []string{"request_policy:all_shards", "nondeterministic_output_order"},
+		Summary:       "Returns all key names that match a pattern.",
+		Since:         "1.0.0",
+		Group:         GroupGeneric,
+		Complexity:    "O(N) with N being the number of keys in the database, under the assumption that the key names in the database and the given pattern have limited length.",
+		Arguments:     []Arg{arg("pattern", ArgPattern)},
+		Parse:         parseKeys,
+	})
+}
+
+func parseKeys(args []string) (Command, error) {
+	return &KeysCommand{
+		Pattern: args[1],
+	}, nil
+}
+
 func (c *KeysCommand) Execute(store store.Store) (interface{}, error) {
 	return store.Keys(c.Pattern)
 }
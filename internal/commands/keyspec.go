@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Key specification flags, as reported by COMMAND INFO
+const (
+	KeyRO            = "RO"
+	KeyRW            = "RW"
+	KeyOW            = "OW"
+	KeyRM            = "RM"
+	KeyAccess        = "ACCESS"
+	KeyUpdate        = "UPDATE"
+	KeyInsert        = "INSERT"
+	KeyDelete        = "DELETE"
+	KeyIncomplete    = "INCOMPLETE"
+	KeyVariableFlags = "VARIABLE_FLAGS"
+	// KeyNotKey marks arguments that aren't keys but hash to a slot like
+	// them, such as shard channels
+	KeyNotKey = "NOT_KEY"
+)
+
+// KeySpec describes where a command finds its keys. The search starts either
+// at a fixed argument index or after a keyword, and the keys are then either
+// a range of arguments or a count-prefixed list.
+type KeySpec struct {
+	Notes string
+	Flags []string
+
+	// BeginIndex is used unless BeginKeyword is set
+	BeginIndex     int
+	BeginKeyword   string
+	KeywordStartAt int
+
+	// Range search: LastKey is relative to the first key, negative values
+	// count from the end of the arguments
+	LastKey int
+	KeyStep int
+	Limit   int
+
+	// KeyNum search is used instead of the range search when set
+	KeyNum      bool
+	KeyNumIndex int
+	FirstKey    int
+}
+
+// rangeKeys returns a key spec for keys at a fixed position followed by a range
+func rangeKeys(index, lastKey, step int, flags ...string) KeySpec {
+	return KeySpec{Flags: flags, BeginIndex: index, LastKey: lastKey, KeyStep: step}
+}
+
+// numKeys returns a key spec for a numkeys argument followed by that many keys
+func numKeys(index, keyNumIndex, firstKey, step int, flags ...string) KeySpec {
+	return KeySpec{
+		Flags:       flags,
+		BeginIndex:  index,
+		KeyNum:      true,
+		KeyNumIndex: keyNumIndex,
+		FirstKey:    firstKey,
+		KeyStep:     step,
+	}
+}
+
+// reply renders the key spec the way COMMAND INFO does
+func (k KeySpec) reply() types.Map {
+	var spec types.Map
+	if k.Notes != "" {
+		spec = append(spec, types.MapEntry{Key: "notes", Value: k.Notes})
+	}
+
+	flags := make(types.Set, 0, len(k.Flags))
+	for _, f := range k.Flags {
+		flags = append(flags, types.SimpleString(f))
+	}
+	spec = append(spec, types.MapEntry{Key: "flags", Value: flags})
+
+	if k.BeginKeyword != "" {
+		spec = append(spec, types.MapEntry{Key: "begin_search", Value: types.Map{
+			{Key: "type", Value: "keyword"},
+			{Key: "spec", Value: types.Map{
+				{Key: "keyword", Value: k.BeginKeyword},
+				{Key: "startfrom", Value: k.KeywordStartAt},
+			}},
+		}})
+	} else {
+		spec = append(spec, types.MapEntry{Key: "begin_search", Value: types.Map{
+			{Key: "type", Value: "index"},
+			{Key: "spec", Value: types.Map{
+				{Key: "index", Value: k.BeginIndex},
+			}},
+		}})
+	}
+
+	if k.KeyNum {
+		spec = append(spec, types.MapEntry{Key: "find_keys", Value: types.Map{
+			{Key: "type", Value: "keynum"},
+			{Key: "spec", Value: types.Map{
+				{Key: "keynumidx", Value: k.KeyNumIndex},
+				{Key: "firstkey", Value: k.FirstKey},
+				{Key: "keystep", Value: k.KeyStep},
+			}},
+		}})
+	} else {
+		spec = append(spec, types.MapEntry{Key: "find_keys", Value: types.Map{
+			{Key: "type", Value: "range"},
+			{Key: "spec", Value: types.Map{
+				{Key: "lastkey", Value: k.LastKey},
+				{Key: "keystep", Value: k.KeyStep},
+				{Key: "limit", Value: k.Limit},
+			}},
+		}})
+	}
+
+	return spec
+}
+
+// keyPositions returns the argument indexes of the keys this spec matches
+func (k KeySpec) keyPositions(args []string) ([]int, error) {
+	argc := len(args)
+
+	first := k.BeginIndex
+	if k.BeginKeyword != "" {
+		first = -1
+		start, end, step := k.KeywordStartAt, argc, 1
+		if start < 0 {
+			start, end, step = argc+start, 0, -1
+		}
+		for i := start; i != end && i >= 0 && i < argc; i += step {
+			if strings.EqualFold(args[i], k.BeginKeyword) {
+				first = i + 1
+				break
+			}
+		}
+		if first < 0 {
+			return nil, nil
+		}
+	}
+	if first >= argc {
+		return nil, nil
+	}
+
+	var last, step int
+	if k.KeyNum {
+		numIdx := first + k.KeyNumIndex
+		if numIdx >= argc {
+			return nil, fmt.Errorf("Invalid arguments specified for command")
+		}
+		n, err := strconv.Atoi(args[numIdx])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("Invalid arguments specified for command")
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		step = k.KeyStep
+		first += k.FirstKey
+		last = first + (n-1)*step
+	} else {
+		step = k.KeyStep
+		switch {
+		case k.LastKey >= 0:
+			last = first + k.LastKey
+		case k.Limit <= 1:
+			last = argc + k.LastKey
+		default:
+			last = first + ((argc-first)/k.Limit + k.LastKey)
+		}
+	}
+
+	if last >= argc {
+		if !k.hasFlag(KeyIncomplete) {
+			return nil, fmt.Errorf("Invalid arguments specified for command")
+		}
+		last = argc - 1
+	}
+
+	var positions []int
+	for i := first; i <= last; i += step {
+		positions = append(positions, i)
+	}
+	return positions, nil
+}
+
+func (k KeySpec) hasFlag(flag string) bool {
+	for _, f := range k.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// keyReference is a key found in a command's arguments and the flags of the
+// key spec that found it
+type keyReference struct {
+	Key   string
+	Flags []string
+}
+
+// GetKeys extracts the keys of a fully specified command, args[0] being the
+// command name
+func GetKeys(args []string) ([]string, error) {
+	refs, err := getKeyReferences(args)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.Key
+	}
+	return keys, nil
+}
+
+// getKeyReferences resolves the command in args and runs its key specs
+func getKeyReferences(args []string) ([]keyReference, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Invalid command specified")
+	}
+
+	spec, ok := Lookup(args[0])
+	if !ok || strings.Contains(args[0], "|") {
+		return nil, fmt.Errorf("Invalid command specified")
+	}
+	if len(spec.Subcommands) > 0 && len(args) >= 2 {
+		if sub := spec.subcommand(args[1]); sub != nil {
+			spec = sub
+		}
+	}
+	if !spec.checkArity(len(args)) {
+		return nil, fmt.Errorf("Invalid number of arguments specified for command")
+	}
+
+	var refs []keyReference
+	for _, ks := range spec.KeySpecs {
+		positions, err := ks.keyPositions(args)
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range positions {
+			refs = append(refs, keyReference{Key: args[pos], Flags: ks.Flags})
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("The command has no key arguments")
+	}
+	return refs, nil
+}
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// LCSCommand finds the longest common subsequence of two strings
+type LCSCommand struct {
+	Key1 string
+	Key2 string
+	// Len replies with the length only, Idx with the matching ranges
+	Len          bool
+	Idx          bool
+	MinMatchLen  int64
+	WithMatchLen bool
+}
+
+// lcsMaxMemory bounds the table LCS builds, as proto-max-bulk-len does in
+// Redis
+const lcsMaxMemory = store.MaxStringLength
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lcs",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       2,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 1, 1, KeyRO, KeyAccess)},
+		Summary:       "Finds the longest common substring.",
+		Since:         "7.0.0",
+		Group:         GroupString,
+		Complexity:    "O(N*M) where N and M are the lengths of s1 and s2, respectively",
+		Arguments: []Arg{
+			keyArg("key1", 0),
+			keyArg("key2", 0),
+			tokenArg("len", "LEN").optional(),
+			tokenArg("idx", "IDX").optional(),
+			arg("min-match-len", ArgInteger).token("MINMATCHLEN").optional(),
+			tokenArg("withmatchlen", "WITHMATCHLEN").optional(),
+		},
+		Parse: parseLCS,
+	})
+}
+
+func parseLCS(args []string) (Command, error) {
+	cmd := &LCSCommand{Key1: args[1], Key2: args[2]}
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "LEN":
+			cmd.Len = true
+		case "IDX":
+			cmd.Idx = true
+		case "WITHMATCHLEN":
+			cmd.WithMatchLen = true
+		case "MINMATCHLEN":
+			if i+1 >= len(args) {
+				return nil, errs.ErrSyntax
+			}
+			i++
+			n, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil {
+				return nil, errs.ErrNotInteger
+			}
+			cmd.MinMatchLen = max(n, 0)
+		default:
+			return nil, errs.ErrSyntax
+		}
+	}
+	if cmd.Len && cmd.Idx {
+		return nil, errs.Errorf("If you want both the length and indexes, please just use IDX.")
+	}
+	return cmd, nil
+}
+
+// Execute replies with the subsequence, its length with LEN, or with IDX a
+// map of the matching ranges, from the end of the strings, and the length.
+// Missing keys are empty strings.
+func (c *LCSCommand) Execute(store store.Store) (interface{}, error) {
+	a, err := c.get(store, c.Key1)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.get(store, c.Key2)
+	if err != nil {
+		return nil, err
+	}
+
+	// The table of LCS lengths of every pair of prefixes is built in full
+	alen, blen := len(a), len(b)
+	if int64(alen+1)*int64(blen+1)*4 > lcsMaxMemory {
+		return nil, errs.Errorf("Insufficient memory, transient memory for LCS exceeds proto-max-bulk-len")
+	}
+	stride := blen + 1
+	dp := make([]uint32, (alen+1)*stride)
+	lcs := func(i, j int) uint32 { return dp[i*stride+j] }
+	for i := 1; i <= alen; i++ {
+		for j := 1; j <= blen; j++ {
+			if a[i-1] == b[j-1] {
+				dp[i*stride+j] = lcs(i-1, j-1) + 1
+			} else {
+				dp[i*stride+j] = max(lcs(i-1, j), lcs(i, j-1))
+			}
+		}
+	}
+
+	length := int(lcs(alen, blen))
+	if c.Len {
+		return length, nil
+	}
+
+	// Walk the table back from the end, collecting the subsequence and the
+	// ranges that match contiguously in both strings
+	result := make([]byte, length)
+	var matches []interface{}
+	idx, i, j := length, alen, blen
+	aStart, aEnd, bStart, bEnd := alen, 0, 0, 0 // aStart == alen: no range
+	for i > 0 && j > 0 {
+		emit := false
+		if a[i-1] == b[j-1] {
+			result[idx-1] = a[i-1]
+			if aStart == alen {
+				aStart, aEnd, bStart, bEnd = i-1, i-1, j-1, j-1
+			} else if aStart == i && bStart == j {
+				// Extend the range backwards
+				aStart--
+				bStart--
+			} else {
+				emit = true
+			}
+			// A range reaching the start of either string is complete
+			if aStart == 0 || bStart == 0 {
+				emit = true
+			}
+			idx, i, j = idx-1, i-1, j-1
+		} else {
+			if lcs(i-1, j) > lcs(i, j-1) {
+				i--
+			} else {
+				j--
+			}
+			if aStart != alen {
+				emit = true
+			}
+		}
+
+		if emit {
+			matchLen := aEnd - aStart + 1
+			if c.Idx && int64(matchLen) >= c.MinMatchLen {
+				match := []interface{}{
+					[]interface{}{aStart, aEnd},
+					[]interface{}{bStart, bEnd},
+				}
+				if c.WithMatchLen {
+					match = append(match, matchLen)
+				}
+				matches = append(matches, match)
+			}
+			aStart = alen
+		}
+	}
+
+	if c.Idx {
+		if matches == nil {
+			matches = []interface{}{}
+		}
+		return types.Map{
+			{Key: "matches", Value: matches},
+			{Key: "len", Value: length},
+		}, nil
+	}
+	return string(result), nil
+}
+
+// get returns the string at key, or "" if it is missing
+func (c *LCSCommand) get(store store.Store, key string) (string, error) {
+	val, err := store.Get(key)
+	if err == errs.ErrWrongType {
+		return "", errs.Errorf("The specified keys must contain string values")
+	}
+	if err != nil || val == nil {
+		return "", err
+	}
+	return val.(string), nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LIndexCommand returns an element of a list by its index
+type LIndexCommand struct {
+	Key   string
+	Index int64
+}
+
+// LSetCommand replaces an element of a list by its index
+type LSetCommand struct {
+	Key     string
+	Index   int64
+	Element string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lindex",
+		Arity:         3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns an element from a list by its index.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N) where N is the number of elements to traverse to get to the element at index. This makes asking for the first or the last element of the list O(1).",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("index", ArgInteger),
+		},
+		Parse: parseLIndex,
+	})
+
+	Register(&CommandSpec{
+		Name:          "lset",
+		Arity:         4,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Sets the value of an element in a list by its index.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N) where N is the length of the list. Setting either the first or the last element of the list is O(1).",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("index", ArgInteger),
+			arg("element", ArgString),
+		},
+		Parse: parseLSet,
+	})
+}
+
+func parseLIndex(args []string) (Command, error) {
+	index, ok := store.ParseInt(args[2])
+	if !ok {
+		return nil, errs.ErrNotInteger
+	}
+	return &LIndexCommand{Key: args[1], Index: index}, nil
+}
+
+func parseLSet(args []string) (Command, error) {
+	index, ok := store.ParseInt(args[2])
+	if !ok {
+		return nil, errs.ErrNotInteger
+	}
+	return &LSetCommand{Key: args[1], Index: index, Element: args[3]}, nil
+}
+
+// Execute replies with the element, or nil if the index is out of range
+func (c *LIndexCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LIndex(c.Key, c.Index)
+}
+
+// Execute replies OK
+func (c *LSetCommand) Execute(store store.Store) (interface{}, error) {
+	if err := store.LSet(c.Key, c.Index, c.Element); err != nil {
+		return nil, err
+	}
+	return OK, nil
+}
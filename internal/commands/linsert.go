@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LInsertCommand inserts an element before or after another in a list
+type LInsertCommand struct {
+	Key     string
+	Before  bool
+	Pivot   string
+	Element string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "linsert",
+		Arity:         5,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyInsert)},
+		Summary:       "Inserts an element before or after another element in a list.",
+		Since:         "2.2.0",
+		Group:         GroupList,
+		Complexity:    "O(N) where N is the number of elements to traverse before seeing the value pivot. This means that inserting somewhere on the left end on the list (head) can be considered O(1) and inserting somewhere on the right end (tail) is O(N).",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("where",
+				tokenArg("before", "BEFORE"),
+				tokenArg("after", "AFTER"),
+			),
+			arg("pivot", ArgString),
+			arg("element", ArgString),
+		},
+		Parse: parseLInsert,
+	})
+}
+
+func parseLInsert(args []string) (Command, error) {
+	var before bool
+	switch strings.ToUpper(args[2]) {
+	case "BEFORE":
+		before = true
+	case "AFTER":
+	default:
+		return nil, errs.ErrSyntax
+	}
+	return &LInsertCommand{Key: args[1], Before: before, Pivot: args[3], Element: args[4]}, nil
+}
+
+// Execute replies with the length of the list, -1 if the pivot wasn't found
+// and 0 if the list is missing
+func (c *LInsertCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LInsert(c.Key, c.Before, c.Pivot, c.Element)
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LMoveCommand pops an element from a list and pushes it to another
+type LMoveCommand struct {
+	Source, Destination string
+	// SourceFront and DestinationFront pick the head of the lists rather
+	// than the tail
+	SourceFront, DestinationFront bool
+}
+
+// sideArg is the LEFT or RIGHT argument picking an end of a list
+func sideArg(name string) Arg {
+	return oneOf(name,
+		tokenArg("left", "LEFT"),
+		tokenArg("right", "RIGHT"),
+	)
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lmove",
+		Arity:         5,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       2,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs: []KeySpec{
+			rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyDelete),
+			rangeKeys(2, 0, 1, KeyRW, KeyInsert),
+		},
+		Summary:    "Returns an element after popping it from one list and pushing it to another. Deletes the list if the last element was moved.",
+		Since:      "6.2.0",
+		Group:      GroupList,
+		Complexity: "O(1)",
+		Arguments: []Arg{
+			keyArg("source", 0),
+			keyArg("destination", 1),
+			sideArg("wherefrom"),
+			sideArg("whereto"),
+		},
+		Parse: parseLMove,
+	})
+}
+
+// parseSide parses LEFT or RIGHT, reporting whether it is LEFT, the head of
+// the list
+func parseSide(arg string) (bool, error) {
+	switch strings.ToUpper(arg) {
+	case "LEFT":
+		return true, nil
+	case "RIGHT":
+		return false, nil
+	default:
+		return false, errs.ErrSyntax
+	}
+}
+
+func parseLMove(args []string) (Command, error) {
+	from, err := parseSide(args[3])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseSide(args[4])
+	if err != nil {
+		return nil, err
+	}
+	return &LMoveCommand{
+		Source:           args[1],
+		Destination:      args[2],
+		SourceFront:      from,
+		DestinationFront: to,
+	}, nil
+}
+
+// Execute replies with the element moved, or nil if the source is missing
+func (c *LMoveCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LMove(c.Source, c.Destination, c.SourceFront, c.DestinationFront)
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LMPopCommand pops elements from the first non-empty list of several
+type LMPopCommand struct {
+	Keys  []string
+	Front bool
+	Count int
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lmpop",
+		Arity:         -4,
+		Flags:         []string{FlagWrite, FlagMovableKeys},
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{numKeys(1, 0, 1, 1, KeyRW, KeyAccess, KeyDelete)},
+		Summary:       "Returns multiple elements from a list after removing them. Deletes the list if the last element was popped.",
+		Since:         "7.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N+M) where N is the number of provided keys and M is the number of elements returned.",
+		Arguments: []Arg{
+			arg("numkeys", ArgInteger),
+			keyArg("key", 0).multiple(),
+			sideArg("where"),
+			arg("count", ArgInteger).token("COUNT").optional(),
+		},
+		Parse: func(args []string) (Command, error) {
+			keys, front, count, err := parseMPop(args, 1, parseSide)
+			if err != nil {
+				return nil, err
+			}
+			return &LMPopCommand{Keys: keys, Front: front, Count: count}, nil
+		},
+	})
+}
+
+// parseMPop parses the numkeys key... where [COUNT count] arguments of
+// LMPOP and ZMPOP starting at args[at], where being parsed by parseWhere
+func parseMPop(args []string, at int, parseWhere func(string) (bool, error)) ([]string, bool, int, error) {
+	numKeys, err := strconv.ParseInt(args[at], 10, 64)
+	if err != nil || numKeys <= 0 {
+		return nil, false, 0, errs.Errorf("numkeys should be greater than 0")
+	}
+	whereAt := at + 1 + int(min(numKeys, int64(len(args))))
+	if whereAt >= len(args) {
+		return nil, false, 0, errs.ErrSyntax
+	}
+	keys := args[at+1 : whereAt]
+	where, err := parseWhere(args[whereAt])
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	count := -1
+	for i := whereAt + 1; i < len(args); i++ {
+		if count != -1 || !strings.EqualFold(args[i], "COUNT") || i+1 >= len(args) {
+			return nil, false, 0, errs.ErrSyntax
+		}
+		i++
+		n, err := strconv.ParseInt(args[i], 10, 64)
+		if err != nil || n <= 0 {
+			return nil, false, 0, errs.Errorf("count should be greater than 0")
+		}
+		count = int(n)
+	}
+	if count == -1 {
+		count = 1
+	}
+	return keys, where, count, nil
+}
+
+// Execute replies with the key popped from and an array of the elements, or
+// a nil array if all of the lists are missing
+func (c *LMPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.LMPop(c.Keys, c.Front, c.Count)
+	if err != nil {
+		return nil, err
+	}
+	if popped == nil {
+		return []interface{}(nil), nil
+	}
+	return []interface{}{key, popped}, nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// PopCommand implements LPOP and RPOP, which remove elements from either end
+// of a list
+type PopCommand struct {
+	Key   string
+	Front bool
+	// Count is the number of elements to pop. Without HasCount a single
+	// element is returned rather than an array.
+	Count    int
+	HasCount bool
+}
+
+func init() {
+	pop := func(name, summary string, front bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -2,
+			Flags:         []string{FlagWrite, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"list"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyDelete)},
+			Summary:       summary,
+			Since:         "1.0.0",
+			Group:         GroupList,
+			Complexity:    "O(N) where N is the number of elements returned",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("count", ArgInteger).optional().since("6.2.0"),
+			},
+			Parse: func(args []string) (Command, error) {
+				return parsePop(args, front)
+			},
+		}
+	}
+
+	Register(pop("lpop", "Returns the first elements in a list after removing it. Deletes the list if the last element was popped.", true))
+	Register(pop("rpop", "Returns and removes the last elements of the list. Deletes the list if the last element was popped.", false))
+}
+
+func parsePop(args []string, front bool) (Command, error) {
+	if len(args) > 3 {
+		return nil, errWrongArgs(args[0])
+	}
+	cmd := &PopCommand{Key: args[1], Front: front, Count: 1}
+	if len(args) == 3 {
+		count, err := parseListCount(args[2], "value is out of range, must be positive")
+		if err != nil {
+			return nil, err
+		}
+		cmd.Count = count
+		cmd.HasCount = true
+	}
+	return cmd, nil
+}
+
+// parseListCount parses the count of elements to pop, replying msg if it
+// isn't an integer or is negative
+func parseListCount(arg, msg string) (int, error) {
+	count, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || count < 0 {
+		return 0, errs.New(errs.PrefixErr, msg)
+	}
+	return int(count), nil
+}
+
+// Execute replies with the element popped, or nil for a missing list. With
+// a count it replies with an array of the elements instead, or a nil array.
+func (c *PopCommand) Execute(store store.Store) (interface{}, error) {
+	popped, err := store.Pop(c.Key, c.Front, c.Count)
+	if err != nil {
+		return nil, err
+	}
+	if c.HasCount {
+		return popped, nil
+	}
+	if len(popped) == 0 {
+		return nil, nil
+	}
+	return popped[0], nil
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LPosCommand returns the indexes of matching elements of a list
+type LPosCommand struct {
+	Key     string
+	Element string
+	// Rank is the match to start from, counting from the tail if negative
+	Rank int64
+	// Count is the number of matches to return, all of them if 0. Without
+	// HasCount a single index is returned rather than an array.
+	Count    int64
+	HasCount bool
+	// MaxLen is the number of elements to compare, all of them if 0
+	MaxLen int64
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lpos",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO)},
+		Summary:       "Returns the index of matching elements in a list.",
+		Since:         "6.0.6",
+		Group:         GroupList,
+		Complexity:    "O(N) where N is the number of elements in the list, for the average case. When searching for elements near the head or the tail of the list, or when the MAXLEN option is provided, the command may run in constant time.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("element", ArgString),
+			arg("rank", ArgInteger).token("RANK").optional(),
+			arg("num-matches", ArgInteger).token("COUNT").optional(),
+			arg("len", ArgInteger).token("MAXLEN").optional(),
+		},
+		Parse: parseLPos,
+	})
+}
+
+func parseLPos(args []string) (Command, error) {
+	cmd := &LPosCommand{Key: args[1], Element: args[2], Rank: 1, Count: 1}
+
+	for i := 3; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		if i+1 >= len(args) {
+			return nil, errs.ErrSyntax
+		}
+		i++
+		switch opt {
+		case "RANK":
+			rank, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil {
+				return nil, errs.ErrNotInteger
+			}
+			if rank == math.MinInt64 {
+				return nil, errs.Errorf("value is out of range, value must between %d and %d", -math.MaxInt64, math.MaxInt64)
+			}
+			if rank == 0 {
+				return nil, errs.Errorf("RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list")
+			}
+			cmd.Rank = rank
+		case "COUNT":
+			count, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil || count < 0 {
+				return nil, errs.Errorf("COUNT can't be negative")
+			}
+			cmd.Count = count
+			cmd.HasCount = true
+		case "MAXLEN":
+			maxLen, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil || maxLen < 0 {
+				return nil, errs.Errorf("MAXLEN can't be negative")
+			}
+			cmd.MaxLen = maxLen
+		default:
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	return cmd, nil
+}
+
+// Execute replies with the index of the first match, or nil if there is
+// none. With COUNT it replies with an array of the indexes instead.
+func (c *LPosCommand) Execute(store store.Store) (interface{}, error) {
+	matches, err := store.LPos(c.Key, c.Element, c.Rank, c.Count, c.MaxLen)
+	if err != nil {
+		return nil, err
+	}
+	if c.HasCount {
+		return intsReply(matches), nil
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return matches[0], nil
+}
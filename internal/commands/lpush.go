@@ -0,0 +1,52 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+// PushCommand implements LPUSH, RPUSH, LPUSHX and RPUSHX, which add elements
+// at either end of a list
+type PushCommand struct {
+	Key      string
+	Elements []string
+	// Front pushes at the head of the list rather than the tail
+	Front bool
+	// Existing only pushes if the list exists
+	Existing bool
+}
+
+func init() {
+	// multipleSince is the version that accepted several elements
+	push := func(name, summary, since, multipleSince string, front, existing bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -3,
+			Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"list"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyInsert)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupList,
+			Complexity:    "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("element", ArgString).multiple().since(multipleSince),
+			},
+			Parse: func(args []string) (Command, error) {
+				return &PushCommand{Key: args[1], Elements: args[2:], Front: front, Existing: existing}, nil
+			},
+		}
+	}
+
+	Register(push("lpush", "Prepends one or more elements to a list. Creates the key if it doesn't exist.", "1.0.0", "2.4.0", true, false))
+	Register(push("rpush", "Appends one or more elements to a list. Creates the key if it doesn't exist.", "1.0.0", "2.4.0", false, false))
+	Register(push("lpushx", "Prepends one or more elements to a list only when the list exists.", "2.2.0", "4.0.0", true, true))
+	Register(push("rpushx", "Appends an element to a list only when the list exists.", "2.2.0", "4.0.0", false, true))
+}
+
+// Execute replies with the length of the list, 0 if LPUSHX or RPUSHX found
+// it missing
+func (c *PushCommand) Execute(store store.Store) (interface{}, error) {
+	return store.Push(c.Key, c.Elements, c.Front, c.Existing)
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LRangeCommand returns a range of elements of a list
+type LRangeCommand struct {
+	Key         string
+	Start, Stop int64
+}
+
+// LLenCommand returns the length of a list
+type LLenCommand struct {
+	Key string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lrange",
+		Arity:         4,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns a range of elements from a list.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(S+N) where S is the distance of start offset from HEAD for small lists, from nearest end (HEAD or TAIL) for large lists; and N is the number of elements in the specified range.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("start", ArgInteger),
+			arg("stop", ArgInteger),
+		},
+		Parse: parseLRange,
+	})
+
+	Register(&CommandSpec{
+		Name:          "llen",
+		Arity:         2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO)},
+		Summary:       "Returns the length of a list.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse: func(args []string) (Command, error) {
+			return &LLenCommand{Key: args[1]}, nil
+		},
+	})
+}
+
+// parseListRange parses the start and stop indexes of LRANGE and LTRIM
+func parseListRange(args []string) (int64, int64, error) {
+	start, ok := store.ParseInt(args[2])
+	if !ok {
+		return 0, 0, errs.ErrNotInteger
+	}
+	stop, ok := store.ParseInt(args[3])
+	if !ok {
+		return 0, 0, errs.ErrNotInteger
+	}
+	return start, stop, nil
+}
+
+func parseLRange(args []string) (Command, error) {
+	start, stop, err := parseListRange(args)
+	if err != nil {
+		return nil, err
+	}
+	return &LRangeCommand{Key: args[1], Start: start, Stop: stop}, nil
+}
+
+// Execute replies with an array of the elements, empty if the list is
+// missing
+func (c *LRangeCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LRange(c.Key, c.Start, c.Stop)
+}
+
+// Execute replies with the length, 0 if the list is missing
+func (c *LLenCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LLen(c.Key)
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// LRemCommand removes occurrences of an element from a list
+type LRemCommand struct {
+	Key string
+	// Count is how many occurrences to remove, from the tail if negative
+	// and all of them if 0
+	Count   int64
+	Element string
+}
+
+// LTrimCommand keeps only a range of elements of a list
+type LTrimCommand struct {
+	Key         string
+	Start, Stop int64
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "lrem",
+		Arity:         4,
+		Flags:         []string{FlagWrite},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyDelete)},
+		Summary:       "Removes elements from a list. Deletes the list if the last element was removed.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N+M) where N is the length of the list and M is the number of elements removed.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("count", ArgInteger),
+			arg("element", ArgString),
+		},
+		Parse: parseLRem,
+	})
+
+	Register(&CommandSpec{
+		Name:          "ltrim",
+		Arity:         4,
+		Flags:         []string{FlagWrite},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"list"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyDelete)},
+		Summary:       "Removes elements from both ends a list. Deletes the list if all elements were trimmed.",
+		Since:         "1.0.0",
+		Group:         GroupList,
+		Complexity:    "O(N) where N is the number of elements to be removed by the operation.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("start", ArgInteger),
+			arg("stop", ArgInteger),
+		},
+		Parse: parseLTrim,
+	})
+}
+
+func parseLRem(args []string) (Command, error) {
+	count, ok := store.ParseInt(args[2])
+	if !ok {
+		return nil, errs.ErrNotInteger
+	}
+	return &LRemCommand{Key: args[1], Count: count, Element: args[3]}, nil
+}
+
+func parseLTrim(args []string) (Command, error) {
+	start, stop, err := parseListRange(args)
+	if err != nil {
+		return nil, err
+	}
+	return &LTrimCommand{Key: args[1], Start: start, Stop: stop}, nil
+}
+
+// Execute replies with the number of elements removed
+func (c *LRemCommand) Execute(store store.Store) (interface{}, error) {
+	return store.LRem(c.Key, c.Count, c.Element)
+}
+
+// Execute replies OK
+func (c *LTrimCommand) Execute(store store.Store) (interface{}, error) {
+	if err := store.LTrim(c.Key, c.Start, c.Stop); err != nil {
+		return nil, err
+	}
+	return OK, nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+type MGetCommand struct {
+	Keys []string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "mget",
+		Arity:         -2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       -1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		Tips:          []string{"request_policy:multi_shard"},
+		KeySpecs:      []KeySpec{rangeKeys(1, -1, 1, KeyRO, KeyAccess)},
+		Summary:       "Atomically returns the string values of one or more keys.",
+		Since:         "1.0.0",
+		Group:         GroupString,
+		Complexity:    "O(N) where N is the number of keys to retrieve.",
+		Arguments:     []Arg{keyArg("key", 0).multiple()},
+		Parse:         parseMGet,
+	})
+}
+
+func parseMGet(args []string) (Command, error) {
+	return &MGetCommand{
+		Keys: args[1:],
+	}, nil
+}
+
+// Execute replies with the value of each key, nil for keys that don't hold
+// a string
+func (c *MGetCommand) Execute(store store.Store) (interface{}, error) {
+	return store.MGet(c.Keys...), nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// MSetCommand implements MSET and, with NX, MSETNX
+type MSetCommand struct {
+	// KeyValues alternates keys and their values
+	KeyValues []string
+	NX        bool
+}
+
+func init() {
+	mset := func(name, summary string, keyFlag string, tips []string) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -3,
+			Flags:         []string{FlagWrite, FlagDenyOOM},
+			FirstKey:      1,
+			LastKey:       -1,
+			Step:          2,
+			ACLCategories: []string{"string"},
+			Tips:          tips,
+			KeySpecs:      []KeySpec{rangeKeys(1, -1, 2, KeyOW, keyFlag)},
+			Summary:       summary,
+			Since:         "1.0.1",
+			Group:         GroupString,
+			Complexity:    "O(N) where N is the number of keys to set.",
+			Arguments: []Arg{
+				block("data",
+					keyArg("key", 0),
+					arg("value", ArgString),
+				).multiple(),
+			},
+			Parse: parseMSet,
+		}
+	}
+
+	Register(mset("mset", "Atomically creates or modifies the string values of one or more keys.",
+		KeyUpdate, []string{"request_policy:multi_shard", "response_policy:all_succeeded"}))
+	Register(mset("msetnx", "Atomically modifies the string values of one or more keys only when all keys don't exist.",
+		KeyInsert, []string{"request_policy:multi_shard", "response_policy:agg_min"}))
+}
+
+func parseMSet(args []string) (Command, error) {
+	// Keys and values come in pairs
+	if len(args)%2 == 0 {
+		return nil, errWrongArgs(args[0])
+	}
+	return &MSetCommand{
+		KeyValues: args[1:],
+		NX:        strings.EqualFold(args[0], "msetnx"),
+	}, nil
+}
+
+// Execute replies OK for MSET, and 1 or 0 for MSETNX depending on whether
+// the keys were set
+func (c *MSetCommand) Execute(store store.Store) (interface{}, error) {
+	set, err := store.MSet(c.KeyValues, c.NX)
+	if err != nil {
+		return nil, err
+	}
+	if !c.NX {
+		return OK, nil
+	}
+	if !set {
+		return 0, nil
+	}
+	return 1, nil
+}
@@ -0,0 +1,37 @@
+package options
+
+// BitRange is the range of a string BITCOUNT and BITPOS look at. Offsets
+// count bytes, or bits with Bit, and negative ones count from the end.
+type BitRange struct {
+	Start int64
+	End   int64
+	// HasEnd is false when only Start was given, which BITPOS allows
+	HasEnd bool
+	Bit    bool
+}
+
+// BITFIELD subcommands
+const (
+	BitFieldGet = iota
+	BitFieldSet
+	BitFieldIncrBy
+)
+
+// Overflow behaviours of BITFIELD SET and INCRBY
+const (
+	OverflowWrap = iota
+	OverflowSat
+	OverflowFail
+)
+
+// BitFieldOp is one GET, SET or INCRBY of a BITFIELD command
+type BitFieldOp struct {
+	Op     int
+	Signed bool
+	// Bits is the width of the integer, 1 to 64 signed and 1 to 63 unsigned
+	Bits   int
+	Offset int64
+	// Value is the value of SET or the increment of INCRBY
+	Value    int64
+	Overflow int
+}
@@ -13,9 +13,9 @@ func NewExpireOptions() *ExpireOptions {
 
 	// Register EXPIRE command options with their incompatibility rules
 	opts.RegisterOption("NX", "Set expiry only if the key has no expiry", []string{"XX", "GT", "LT"})
-	opts.RegisterOption("XX", "Set expiry only if the key has an existing expiry", []string{"NX", "GT", "LT"})
-	opts.RegisterOption("GT", "Set expiry only if the new expiry is greater than current one", []string{"NX", "XX", "LT"})
-	opts.RegisterOption("LT", "Set expiry only if the new expiry is less than current one", []string{"NX", "XX", "GT"})
+	opts.RegisterOption("XX", "Set expiry only if the key has an existing expiry", []string{"NX"})
+	opts.RegisterOption("GT", "Set expiry only if the new expiry is greater than current one", []string{"NX", "LT"})
+	opts.RegisterOption("LT", "Set expiry only if the new expiry is less than current one", []string{"NX", "GT"})
 
 	return opts
 }
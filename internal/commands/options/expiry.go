@@ -0,0 +1,71 @@
+package options
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Expiry is the timeout option shared by SET, GETEX and HSETEX: one of EX,
+// PX, EXAT or PXAT with its argument, or a keyword such as KEEPTTL or
+// PERSIST on its own
+type Expiry struct {
+	ExpiryType string // "EX", "PX", "EXAT", "PXAT", "KEEPTTL", "PERSIST"
+	// ExpiryValue is the argument of EX, PX, EXAT or PXAT
+	ExpiryValue int64
+}
+
+// IsKEEPTTL returns true if KEEPTTL option is set
+func (o *Expiry) IsKEEPTTL() bool {
+	return o.ExpiryType == "KEEPTTL"
+}
+
+// IsPERSIST returns true if PERSIST option is set
+func (o *Expiry) IsPERSIST() bool {
+	return o.ExpiryType == "PERSIST"
+}
+
+// SetExpiry sets the expiry type and its argument
+func (o *Expiry) SetExpiry(expiryType string, value int64) error {
+	switch expiryType {
+	case "EX", "PX", "EXAT", "PXAT", "KEEPTTL", "PERSIST":
+		o.ExpiryType = expiryType
+		o.ExpiryValue = value
+	default:
+		return fmt.Errorf("invalid expiry type: %s", expiryType)
+	}
+	return nil
+}
+
+// ExpiryTime returns when the timeout ends, EX and PX counting from now. It
+// is only meaningful for those and for EXAT and PXAT. It works in
+// milliseconds, as Redis does, so that timeouts too long for a time.Duration
+// still work.
+func (o *Expiry) ExpiryTime(now time.Time) time.Time {
+	switch o.ExpiryType {
+	case "EX":
+		return time.UnixMilli(now.UnixMilli() + o.ExpiryValue*1000)
+	case "PX":
+		return time.UnixMilli(now.UnixMilli() + o.ExpiryValue)
+	case "EXAT":
+		return time.UnixMilli(o.ExpiryValue * 1000)
+	case "PXAT":
+		return time.UnixMilli(o.ExpiryValue)
+	default:
+		return time.Time{}
+	}
+}
+
+// Overflows reports whether EX or PX counted from now overflows a Unix time
+// in milliseconds. Seconds are expected to have been checked to fit in
+// milliseconds already.
+func (o *Expiry) Overflows(now time.Time) bool {
+	switch o.ExpiryType {
+	case "EX":
+		return o.ExpiryValue*1000 > math.MaxInt64-now.UnixMilli()
+	case "PX":
+		return o.ExpiryValue > math.MaxInt64-now.UnixMilli()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,13 @@
+package options
+
+// GetExOptions represents options for the GETEX command, whose expiry is one
+// of EX, PX, EXAT, PXAT or PERSIST
+type GetExOptions struct {
+	Expiry
+}
+
+// NewGetExOptions creates a new GetExOptions instance, which leaves the
+// timeout alone
+func NewGetExOptions() *GetExOptions {
+	return &GetExOptions{}
+}
@@ -0,0 +1,27 @@
+package options
+
+import "fmt"
+
+// HSetExOptions represents options for the HSETEX command, whose expiry is
+// one of EX, PX, EXAT, PXAT or KEEPTTL
+type HSetExOptions struct {
+	Condition string // "FNX", "FXX", or "" to always set the fields
+	Expiry
+}
+
+// NewHSetExOptions creates a new HSetExOptions instance, which sets the
+// fields unconditionally and removes their timeouts
+func NewHSetExOptions() *HSetExOptions {
+	return &HSetExOptions{}
+}
+
+// SetCondition sets the FNX or FXX condition
+func (o *HSetExOptions) SetCondition(condition string) error {
+	switch condition {
+	case "FNX", "FXX":
+		o.Condition = condition
+	default:
+		return fmt.Errorf("invalid condition: %s", condition)
+	}
+	return nil
+}
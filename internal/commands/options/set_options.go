@@ -1,15 +1,10 @@
 package options
 
-import (
-	"fmt"
-	"time"
-)
-
-// SetOptions represents options for the SET command
+// SetOptions represents options for the SET command, whose expiry is one of
+// EX, PX, EXAT, PXAT or KEEPTTL
 type SetOptions struct {
 	*Options
-	ExpiryTime time.Time
-	ExpiryType string // "EX", "PX", "EXAT", "PXAT", "KEEPTTL"
+	Expiry
 }
 
 // NewSetOptions creates a new SetOptions instance with predefined options
@@ -40,31 +35,3 @@ func (o *SetOptions) IsXX() bool {
 func (o *SetOptions) IsGET() bool {
 	return o.IsSet("GET")
 }
-
-// IsKEEPTTL returns true if KEEPTTL option is set
-func (o *SetOptions) IsKEEPTTL() bool {
-	return o.ExpiryType == "KEEPTTL"
-}
-
-// SetExpiry sets the expiry time and type
-func (o *SetOptions) SetExpiry(expiryType string, value int64) error {
-	switch expiryType {
-	case "EX":
-		o.ExpiryTime = time.Now().Add(time.Duration(value) * time.Second)
-		o.ExpiryType = "EX"
-	case "PX":
-		o.ExpiryTime = time.Now().Add(time.Duration(value) * time.Millisecond)
-		o.ExpiryType = "PX"
-	case "EXAT":
-		o.ExpiryTime = time.Unix(value, 0)
-		o.ExpiryType = "EXAT"
-	case "PXAT":
-		o.ExpiryTime = time.Unix(0, value*int64(time.Millisecond))
-		o.ExpiryType = "PXAT"
-	case "KEEPTTL":
-		o.ExpiryType = "KEEPTTL"
-	default:
-		return fmt.Errorf("invalid expiry type: %s", expiryType)
-	}
-	return nil
-}
@@ -12,12 +12,12 @@ func NewZAddOptions() *ZAddOptions {
 	}
 
 	// Register ZADD command options with their incompatibility rules
-	opts.RegisterOption("NX", "Only add new elements, don't update already existing elements", []string{"XX"})
+	opts.RegisterOption("NX", "Only add new elements, don't update already existing elements", []string{"XX", "GT", "LT"})
 	opts.RegisterOption("XX", "Only update elements that already exist, don't add new elements", []string{"NX"})
-	opts.RegisterOption("GT", "Only update existing elements if the new score is greater than the current score", []string{"LT"})
-	opts.RegisterOption("LT", "Only update existing elements if the new score is less than the current score", []string{"GT"})
+	opts.RegisterOption("GT", "Only update existing elements if the new score is greater than the current score", []string{"LT", "NX"})
+	opts.RegisterOption("LT", "Only update existing elements if the new score is less than the current score", []string{"GT", "NX"})
 	opts.RegisterOption("CH", "Modify the return value to return the number of changed elements instead of new elements", nil)
-	opts.RegisterOption("INCR", "Increment the score of an element instead of setting it", []string{"NX", "XX", "GT", "LT"})
+	opts.RegisterOption("INCR", "Increment the score of an element instead of setting it", nil)
 
 	return opts
 }
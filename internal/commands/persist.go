@@ -0,0 +1,41 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+type PersistCommand struct {
+	Key string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "persist",
+		Arity:         2,
+		Flags:         []string{FlagWrite, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"keyspace"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Removes the expiration time of a key.",
+		Since:         "2.2.0",
+		Group:         GroupGeneric,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse: func(args []string) (Command, error) {
+			return &PersistCommand{Key: args[1]}, nil
+		},
+	})
+}
+
+// Execute replies 1 if the timeout was removed, and 0 if the key doesn't
+// exist or has no timeout
+func (c *PersistCommand) Execute(store store.Store) (interface{}, error) {
+	removed, err := store.Persist(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return 0, nil
+	}
+	return 1, nil
+}
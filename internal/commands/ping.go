@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+type PingCommand struct {
+	Message    string
+	HasMessage bool
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "ping",
+		Arity:         -1,
+		Flags:         []string{FlagFast},
+		ACLCategories: []string{"connection"},
+		Tips:          []string{"request_policy:all_shards", "response_policy:all_succeeded"},
+		Summary:       "Returns the server's liveliness response.",
+		Since:         "1.0.0",
+		Group:         GroupConnection,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{arg("message", ArgString).optional()},
+		Parse:         parsePing,
+	})
+}
+
+func parsePing(args []string) (Command, error) {
+	if len(args) > 2 {
+		return nil, errWrongArgs(args[0])
+	}
+	if len(args) == 2 {
+		return &PingCommand{Message: args[1], HasMessage: true}, nil
+	}
+	return &PingCommand{}, nil
+}
+
+func (c *PingCommand) Execute(store store.Store) (interface{}, error) {
+	if c.HasMessage {
+		return c.Message, nil
+	}
+	return types.SimpleString("PONG"), nil
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Command flags, using the names Redis reports in COMMAND INFO
+const (
+	FlagWrite        = "write"
+	FlagReadOnly     = "readonly"
+	FlagDenyOOM      = "denyoom"
+	FlagAdmin        = "admin"
+	FlagPubSub       = "pubsub"
+	FlagNoScript     = "noscript"
+	FlagBlocking     = "blocking"
+	FlagLoading      = "loading"
+	FlagStale        = "stale"
+	FlagFast         = "fast"
+	FlagNoAuth       = "no_auth"
+	FlagAllowBusy    = "allow_busy"
+	FlagSkipSlowlog  = "skip_slowlog"
+	FlagMayReplicate = "may_replicate"
+	// FlagMovableKeys commands find their keys other than by position, such
+	// as after a numkeys argument
+	FlagMovableKeys = "movablekeys"
+	// FlagProtected commands are refused unless enabled in the configuration
+	FlagProtected = "protected"
+)
+
+// Command groups, as reported by COMMAND DOCS
+const (
+	GroupGeneric      = "generic"
+	GroupString       = "string"
+	GroupBitmap       = "bitmap"
+	GroupList         = "list"
+	GroupHash         = "hash"
+	GroupSortedSet    = "sorted-set"
+	GroupServer       = "server"
+	GroupConnection   = "connection"
+	GroupTransactions = "transactions"
+	GroupPubSub       = "pubsub"
+)
+
+// ParseFunc builds a Command from its arguments. args[0] is the command name
+// as sent by the client; for subcommands args[1] is the subcommand name.
+type ParseFunc func(args []string) (Command, error)
+
+// CommandSpec describes a command: its name, arity, flags, key positions,
+// documentation and the constructor that turns raw arguments into an
+// executable Command
+type CommandSpec struct {
+	// Name is the lower-case command name. Subcommands are registered with
+	// their short name and get "container|name" once registered.
+	Name string
+	// Arity counts the command name. A positive arity is exact, a negative
+	// arity is a minimum, as in Redis.
+	Arity    int
+	Flags    []string
+	FirstKey int
+	LastKey  int
+	Step     int
+	// ACLCategories lists the categories beyond the ones implied by Flags,
+	// without the leading @
+	ACLCategories []string
+	Tips          []string
+	KeySpecs      []KeySpec
+	Subcommands   []*CommandSpec
+
+	Summary    string
+	Since      string
+	Group      string
+	Complexity string
+	Arguments  []Arg
+	// DeprecatedSince and ReplacedBy are set for commands kept only for
+	// compatibility
+	DeprecatedSince string
+	ReplacedBy      string
+
+	// Parse builds the command. Containers whose subcommands do all the work
+	// may leave it nil.
+	Parse ParseFunc
+}
+
+// HasFlag reports whether the command has the given flag
+func (s *CommandSpec) HasFlag(flag string) bool {
+	for _, f := range s.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Categories returns the command's ACL categories, including the ones Redis
+// derives from the command flags
+func (s *CommandSpec) Categories() []string {
+	cats := append([]string(nil), s.ACLCategories...)
+	if s.HasFlag(FlagWrite) {
+		cats = append(cats, "write")
+	}
+	if s.HasFlag(FlagReadOnly) {
+		cats = append(cats, "read")
+	}
+	if s.HasFlag(FlagAdmin) {
+		cats = append(cats, "admin", "dangerous")
+	}
+	if s.HasFlag(FlagPubSub) {
+		cats = append(cats, "pubsub")
+	}
+	if s.HasFlag(FlagFast) {
+		cats = append(cats, "fast")
+	}
+	if s.HasFlag(FlagBlocking) {
+		cats = append(cats, "blocking")
+	}
+	if !s.HasFlag(FlagFast) {
+		cats = append(cats, "slow")
+	}
+	return cats
+}
+
+// subcommand returns the subcommand called name, or nil
+func (s *CommandSpec) subcommand(name string) *CommandSpec {
+	fullName := s.Name + "|" + strings.ToLower(name)
+	for _, sub := range s.Subcommands {
+		if sub.Name == fullName {
+			return sub
+		}
+	}
+	return nil
+}
+
+// checkArity reports whether argc arguments (including the name) satisfy the arity
+func (s *CommandSpec) checkArity(argc int) bool {
+	if s.Arity >= 0 {
+		return argc == s.Arity
+	}
+	return argc >= -s.Arity
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*CommandSpec)
+)
+
+// Register adds a command to the command table. It panics if the spec is
+// incomplete or if a command with the same name is already registered.
+func Register(spec *CommandSpec) {
+	if spec == nil || spec.Name == "" {
+		panic("commands: Register requires a name")
+	}
+
+	spec.Name = strings.ToLower(spec.Name)
+	if err := prepareSpec(spec, ""); err != nil {
+		panic(err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[spec.Name]; exists {
+		panic(fmt.Sprintf("commands: command %q registered twice", spec.Name))
+	}
+	registry[spec.Name] = spec
+}
+
+// prepareSpec validates a spec and qualifies its subcommand names
+func prepareSpec(spec *CommandSpec, parent string) error {
+	if parent != "" {
+		spec.Name = parent + "|" + strings.ToLower(spec.Name)
+	}
+	if spec.Parse == nil && len(spec.Subcommands) == 0 {
+		return fmt.Errorf("commands: command %q has neither a parse function nor subcommands", spec.Name)
+	}
+	for _, sub := range spec.Subcommands {
+		if err := prepareSpec(sub, spec.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the spec registered under name, case-insensitively. A
+// subcommand can be looked up by its full "container|subcommand" name.
+func Lookup(name string) (*CommandSpec, bool) {
+	container, sub, hasSub := strings.Cut(name, "|")
+
+	registryMu.RLock()
+	spec, ok := registry[strings.ToLower(container)]
+	registryMu.RUnlock()
+
+	if !ok || !hasSub {
+		return spec, ok
+	}
+	spec = spec.subcommand(sub)
+	return spec, spec != nil
+}
+
+// All returns every registered top-level command sorted by name
+func All() []*CommandSpec {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	specs := make([]*CommandSpec, 0, len(registry))
+	for _, spec := range registry {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs
+}
+
+// Resolve finds the spec that will handle args, descending into subcommands,
+// and validates the arity
+func Resolve(args []string) (*CommandSpec, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	spec, ok := Lookup(args[0])
+	if !ok || strings.Contains(args[0], "|") {
+		return nil, unknownCommandError(args)
+	}
+
+	if len(spec.Subcommands) > 0 && len(args) >= 2 {
+		sub := spec.subcommand(args[1])
+		if sub == nil {
+			return nil, fmt.Errorf("unknown subcommand '%.128s'. Try %s HELP.", args[1], strings.ToUpper(spec.Name))
+		}
+		spec = sub
+	}
+
+	if !spec.checkArity(len(args)) || spec.Parse == nil {
+		return nil, errWrongArgs(spec.Name)
+	}
+
+	return spec, nil
+}
+
+// Parse looks up the command named by args[0], validates its arity and
+// builds the Command from the remaining arguments
+func Parse(args []string) (Command, error) {
+	spec, err := Resolve(args)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Parse(args)
+}
+
+// errWrongArgs is the error for a command called with the wrong number of
+// arguments
+func errWrongArgs(name string) error {
+	return fmt.Errorf("wrong number of arguments for '%s' command", strings.ToLower(name))
+}
+
+// unknownCommandError formats the error Redis returns for unknown commands
+func unknownCommandError(args []string) error {
+	var b strings.Builder
+	for _, arg := range args[1:] {
+		if b.Len() >= 128 {
+			break
+		}
+		fmt.Fprintf(&b, "'%.*s' ", 128-b.Len(), arg)
+	}
+	return fmt.Errorf("unknown command '%.128s', with args beginning with: %s", args[0], b.String())
+}
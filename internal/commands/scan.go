@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// ScanCommand walks the keyspace a few keys at a time
+type ScanCommand struct {
+	Cursor  uint64
+	Pattern string
+	Count   int
+	// Type only returns keys of the given type, when set
+	Type string
+}
+
+// ZScanCommand walks the members of a sorted set a few at a time
+type ZScanCommand struct {
+	Key     string
+	Cursor  uint64
+	Pattern string
+	Count   int
+}
+
+// HScanCommand walks the fields of a hash a few at a time
+type HScanCommand struct {
+	Key     string
+	Cursor  uint64
+	Pattern string
+	Count   int
+	// NoValues only returns the fields
+	NoValues bool
+}
+
+// scanDefaultCount is the COUNT of a SCAN without one
+const scanDefaultCount = 10
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "scan",
+		Arity:         -2,
+		Flags:         []string{FlagReadOnly},
+		ACLCategories: []string{"keyspace"},
+		Tips:          []string{"nondeterministic_output", "request_policy:special", "response_policy:special"},
+		Summary:       "Iterates over the key names in the database.",
+		Since:         "2.8.0",
+		Group:         GroupGeneric,
+		Complexity:    "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+		Arguments: []Arg{
+			arg("cursor", ArgInteger),
+			arg("pattern", ArgPattern).token("MATCH").optional(),
+			arg("count", ArgInteger).token("COUNT").optional(),
+			arg("type", ArgString).token("TYPE").optional().since("6.0.0"),
+		},
+		Parse: parseScan,
+	})
+
+	Register(&CommandSpec{
+		Name:          "zscan",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"sortedset"},
+		Tips:          []string{"nondeterministic_output"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Iterates over members and scores of a sorted set.",
+		Since:         "2.8.0",
+		Group:         GroupSortedSet,
+		Complexity:    "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("cursor", ArgInteger),
+			arg("pattern", ArgPattern).token("MATCH").optional(),
+			arg("count", ArgInteger).token("COUNT").optional(),
+		},
+		Parse: parseZScan,
+	})
+
+	Register(&CommandSpec{
+		Name:          "hscan",
+		Arity:         -3,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"hash"},
+		Tips:          []string{"nondeterministic_output"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Iterates over fields and values of a hash.",
+		Since:         "2.8.0",
+		Group:         GroupHash,
+		Complexity:    "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("cursor", ArgInteger),
+			arg("pattern", ArgPattern).token("MATCH").optional(),
+			arg("count", ArgInteger).token("COUNT").optional(),
+			tokenArg("novalues", "NOVALUES").optional().since("7.4.0"),
+		},
+		Parse: parseHScan,
+	})
+}
+
+func parseScan(args []string) (Command, error) {
+	cursor, err := parseCursor(args[1])
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseScanOptions(args[2:], true, false)
+	if err != nil {
+		return nil, err
+	}
+	return &ScanCommand{Cursor: cursor, Pattern: opts.Pattern, Count: opts.Count, Type: opts.Type}, nil
+}
+
+func parseZScan(args []string) (Command, error) {
+	cursor, err := parseCursor(args[2])
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseScanOptions(args[3:], false, false)
+	if err != nil {
+		return nil, err
+	}
+	return &ZScanCommand{Key: args[1], Cursor: cursor, Pattern: opts.Pattern, Count: opts.Count}, nil
+}
+
+func parseHScan(args []string) (Command, error) {
+	cursor, err := parseCursor(args[2])
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseScanOptions(args[3:], false, true)
+	if err != nil {
+		return nil, err
+	}
+	return &HScanCommand{
+		Key:      args[1],
+		Cursor:   cursor,
+		Pattern:  opts.Pattern,
+		Count:    opts.Count,
+		NoValues: opts.NoValues,
+	}, nil
+}
+
+func parseCursor(s string) (uint64, error) {
+	cursor, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errs.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// scanOptions are the options following the cursor
+type scanOptions struct {
+	Pattern  string
+	Count    int
+	Type     string
+	NoValues bool
+}
+
+// parseScanOptions parses the options following the cursor. TYPE is only
+// accepted when withType is set, and NOVALUES when withNoValues is.
+func parseScanOptions(args []string, withType, withNoValues bool) (scanOptions, error) {
+	opts := scanOptions{Pattern: "*", Count: scanDefaultCount}
+	for i := 0; i < len(args); i += 2 {
+		opt := strings.ToUpper(args[i])
+		if opt == "NOVALUES" && withNoValues {
+			// Unlike the other options NOVALUES has no argument
+			opts.NoValues = true
+			i--
+			continue
+		}
+		if i+1 >= len(args) {
+			return scanOptions{}, errs.ErrSyntax
+		}
+		switch val := args[i+1]; {
+		case opt == "MATCH":
+			opts.Pattern = val
+		case opt == "COUNT":
+			var err error
+			if opts.Count, err = strconv.Atoi(val); err != nil {
+				return scanOptions{}, errs.ErrNotInteger
+			}
+			if opts.Count < 1 {
+				return scanOptions{}, errs.ErrSyntax
+			}
+		case opt == "TYPE" && withType:
+			opts.Type = strings.ToLower(val)
+			if !slices.Contains(store.TypeNames, opts.Type) {
+				return scanOptions{}, errs.Errorf("unknown type name '%s'", val)
+			}
+		default:
+			return scanOptions{}, errs.ErrSyntax
+		}
+	}
+	return opts, nil
+}
+
+// scanReply is the cursor, as a string, followed by the items found
+func scanReply(cursor uint64, items []interface{}) []interface{} {
+	return []interface{}{strconv.FormatUint(cursor, 10), items}
+}
+
+func (c *ScanCommand) Execute(store store.Store) (interface{}, error) {
+	cursor, keys, err := store.Scan(c.Cursor, c.Count, c.Pattern, c.Type)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]interface{}, len(keys))
+	for i, key := range keys {
+		items[i] = key
+	}
+	return scanReply(cursor, items), nil
+}
+
+// Execute replies with members and their scores in turn. Scores are bulk
+// strings, as in Redis, even with RESP3.
+func (c *ZScanCommand) Execute(store store.Store) (interface{}, error) {
+	cursor, members, err := store.ZScan(c.Key, c.Cursor, c.Count, c.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]interface{}, 0, len(members)*2)
+	for _, sm := range members {
+		items = append(items, sm.Member, resp.FormatFloat(sm.Score))
+	}
+	return scanReply(cursor, items), nil
+}
+
+// Execute replies with fields and their values in turn, or only the fields
+// with NOVALUES
+func (c *HScanCommand) Execute(store store.Store) (interface{}, error) {
+	cursor, entries, err := store.HScan(c.Key, c.Cursor, c.Count, c.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]interface{}, 0, len(entries)*2)
+	for _, e := range entries {
+		items = append(items, e.Key)
+		if !c.NoValues {
+			items = append(items, e.Value)
+		}
+	}
+	return scanReply(cursor, items), nil
+}
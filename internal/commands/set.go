@@ -1,7 +1,12 @@
 package commands
 
 import (
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
 	"github.com/hardikphalet/go-redis/internal/store"
 )
 
@@ -11,6 +16,122 @@ type SetCommand struct {
 	Options *options.SetOptions
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "set",
+		Arity:         -3,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs: []KeySpec{{
+			Notes:      "RW and ACCESS due to the optional `GET` argument",
+			Flags:      []string{KeyRW, KeyAccess, KeyUpdate, KeyVariableFlags},
+			BeginIndex: 1,
+			KeyStep:    1,
+		}},
+		Summary:    "Sets the string value of a key, ignoring its type. The key is created if it doesn't exist.",
+		Since:      "1.0.0",
+		Group:      GroupString,
+		Complexity: "O(1)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("value", ArgString),
+			oneOf("condition",
+				tokenArg("nx", "NX"),
+				tokenArg("xx", "XX"),
+			).optional().since("2.6.12"),
+			tokenArg("get", "GET").optional().since("6.2.0"),
+			oneOf("expiration",
+				arg("seconds", ArgInteger).token("EX").since("2.6.12"),
+				arg("milliseconds", ArgInteger).token("PX").since("2.6.12"),
+				arg("unix-time-seconds", ArgUnixTime).token("EXAT").since("6.2.0"),
+				arg("unix-time-milliseconds", ArgUnixTime).token("PXAT").since("6.2.0"),
+				tokenArg("keepttl", "KEEPTTL").since("6.0.0"),
+			).optional(),
+		},
+		Parse: parseSet,
+	})
+}
+
+func parseSet(args []string) (Command, error) {
+	// Create options
+	opts := options.NewSetOptions()
+
+	// Parse options
+	i := 3
+	for i < len(args) {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "NX", "XX", "GET":
+			if err := opts.Set(opt); err != nil {
+				return nil, errs.ErrSyntax
+			}
+			i++
+		case "EX", "PX", "EXAT", "PXAT", "KEEPTTL":
+			// Only one expiration option may be given
+			if opts.ExpiryType != "" {
+				return nil, errs.ErrSyntax
+			}
+			if opt == "KEEPTTL" {
+				if err := opts.SetExpiry(opt, 0); err != nil {
+					return nil, errs.ErrSyntax
+				}
+				i++
+			} else {
+				if i+1 >= len(args) {
+					return nil, errs.ErrSyntax
+				}
+				value, err := parseExpiryValue(args[0], args[i+1], opt == "EX" || opt == "EXAT")
+				if err != nil {
+					return nil, err
+				}
+				if err := opts.SetExpiry(opt, value); err != nil {
+					return nil, errs.ErrSyntax
+				}
+				i += 2
+			}
+		default:
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	return &SetCommand{
+		Key:     args[1],
+		Value:   args[2],
+		Options: opts,
+	}, nil
+}
+
+// parseExpiryValue parses the argument of an EX, PX, EXAT or PXAT option,
+// which must be positive and, in seconds, fit in milliseconds
+func parseExpiryValue(command, arg string, seconds bool) (int64, error) {
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errs.ErrNotInteger
+	}
+	if value <= 0 || (seconds && value > math.MaxInt64/1000) {
+		return 0, invalidExpireTime(strings.ToLower(command))
+	}
+	return value, nil
+}
+
+// Execute replies OK, or nil when NX or XX prevented the write. With GET the
+// old value is returned instead.
 func (c *SetCommand) Execute(store store.Store) (interface{}, error) {
-	return store.Set(c.Key, c.Value, c.Options)
+	if c.Options.Overflows(store.Now()) {
+		return nil, invalidExpireTime("set")
+	}
+	old, ok, err := store.Set(c.Key, c.Value, c.Options)
+	if err != nil {
+		return nil, err
+	}
+	if c.Options.IsGET() {
+		return old, nil
+	}
+	if !ok {
+		return nil, nil
+	}
+	return OK, nil
 }
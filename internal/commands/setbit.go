@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"math"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// SetBitCommand sets or clears one bit of a string
+type SetBitCommand struct {
+	Key    string
+	Offset int64
+	On     bool
+}
+
+// GetBitCommand reads one bit of a string
+type GetBitCommand struct {
+	Key    string
+	Offset int64
+}
+
+var errBitOffset = errs.Errorf("bit offset is not an integer or out of range")
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "setbit",
+		Arity:         4,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyUpdate)},
+		Summary:       "Sets or clears the bit at offset of the string value. Creates the key if it doesn't exist.",
+		Since:         "2.2.0",
+		Group:         GroupBitmap,
+		Complexity:    "O(1)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("offset", ArgInteger),
+			arg("value", ArgInteger),
+		},
+		Parse: parseSetBit,
+	})
+
+	Register(&CommandSpec{
+		Name:          "getbit",
+		Arity:         3,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"bitmap"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns a bit value by offset.",
+		Since:         "2.2.0",
+		Group:         GroupBitmap,
+		Complexity:    "O(1)",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("offset", ArgInteger),
+		},
+		Parse: parseGetBit,
+	})
+}
+
+// parseBitOffset parses a bit offset, which must address a bit of a string
+// no longer than store.MaxStringLength. With hash, an offset written "#N"
+// counts fields of width bits rather than bits.
+func parseBitOffset(s string, hash bool, bits int) (int64, error) {
+	useHash := hash && len(s) > 0 && s[0] == '#'
+	if useHash {
+		s = s[1:]
+	}
+	offset, ok := store.ParseInt(s)
+	if !ok || offset < 0 {
+		return 0, errBitOffset
+	}
+	if useHash {
+		if offset > math.MaxInt64/int64(bits) {
+			return 0, errBitOffset
+		}
+		offset *= int64(bits)
+	}
+	if offset>>3 >= store.MaxStringLength {
+		return 0, errBitOffset
+	}
+	return offset, nil
+}
+
+func parseSetBit(args []string) (Command, error) {
+	offset, err := parseBitOffset(args[2], false, 0)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := store.ParseInt(args[3])
+	if !ok || value&^1 != 0 {
+		return nil, errs.Errorf("bit is not an integer or out of range")
+	}
+	return &SetBitCommand{Key: args[1], Offset: offset, On: value == 1}, nil
+}
+
+func parseGetBit(args []string) (Command, error) {
+	offset, err := parseBitOffset(args[2], false, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &GetBitCommand{Key: args[1], Offset: offset}, nil
+}
+
+// Execute replies with the previous value of the bit
+func (c *SetBitCommand) Execute(store store.Store) (interface{}, error) {
+	return store.SetBit(c.Key, c.Offset, c.On)
+}
+
+// Execute replies with the bit, 0 past the end of the string
+func (c *GetBitCommand) Execute(store store.Store) (interface{}, error) {
+	return store.GetBit(c.Key, c.Offset)
+}
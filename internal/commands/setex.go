@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+// SetNXCommand sets a key only if it doesn't exist, like SET with NX
+type SetNXCommand struct {
+	Key   string
+	Value string
+}
+
+// SetExCommand sets a key with a timeout, like SET with EX or PX
+type SetExCommand struct {
+	Key     string
+	Value   string
+	Options *options.SetOptions
+	// name is the command name for errors
+	name string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:            "setnx",
+		Arity:           3,
+		Flags:           []string{FlagWrite, FlagDenyOOM, FlagFast},
+		FirstKey:        1,
+		LastKey:         1,
+		Step:            1,
+		ACLCategories:   []string{"string"},
+		KeySpecs:        []KeySpec{rangeKeys(1, 0, 1, KeyOW, KeyInsert)},
+		Summary:         "Set the string value of a key only when the key doesn't exist.",
+		Since:           "1.0.0",
+		Group:           GroupString,
+		Complexity:      "O(1)",
+		DeprecatedSince: "2.6.12",
+		ReplacedBy:      "`SET` with the `NX` argument",
+		Arguments:       []Arg{keyArg("key", 0), arg("value", ArgString)},
+		Parse:           parseSetNX,
+	})
+
+	setex := func(name, summary, since, timeArg, option string) *CommandSpec {
+		return &CommandSpec{
+			Name:            name,
+			Arity:           4,
+			Flags:           []string{FlagWrite, FlagDenyOOM},
+			FirstKey:        1,
+			LastKey:         1,
+			Step:            1,
+			ACLCategories:   []string{"string"},
+			KeySpecs:        []KeySpec{rangeKeys(1, 0, 1, KeyOW, KeyUpdate)},
+			Summary:         summary,
+			Since:           since,
+			Group:           GroupString,
+			Complexity:      "O(1)",
+			DeprecatedSince: "2.6.12",
+			ReplacedBy:      "`SET` with the `" + option + "` argument",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg(timeArg, ArgInteger),
+				arg("value", ArgString),
+			},
+			Parse: func(args []string) (Command, error) {
+				return parseSetEx(args, option)
+			},
+		}
+	}
+
+	Register(setex("setex", "Sets the string value and expiration time of a key. Creates the key if it doesn't exist.",
+		"2.0.0", "seconds", "EX"))
+	Register(setex("psetex", "Sets both string value and expiration time in milliseconds of a key. The key is created if it doesn't exist.",
+		"2.6.0", "milliseconds", "PX"))
+}
+
+func parseSetNX(args []string) (Command, error) {
+	return &SetNXCommand{
+		Key:   args[1],
+		Value: args[2],
+	}, nil
+}
+
+// parseSetEx parses SETEX or PSETEX into the SET option they stand for
+func parseSetEx(args []string, option string) (Command, error) {
+	value, err := parseExpiryValue(args[0], args[2], option == "EX")
+	if err != nil {
+		return nil, err
+	}
+	opts := options.NewSetOptions()
+	if err := opts.SetExpiry(option, value); err != nil {
+		return nil, err
+	}
+	return &SetExCommand{
+		Key:     args[1],
+		Value:   args[3],
+		Options: opts,
+		name:    strings.ToLower(args[0]),
+	}, nil
+}
+
+// Execute replies 1 if the key was set, and 0 if it already existed
+func (c *SetNXCommand) Execute(store store.Store) (interface{}, error) {
+	opts := options.NewSetOptions()
+	_ = opts.Set("NX")
+	_, set, err := store.Set(c.Key, c.Value, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !set {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (c *SetExCommand) Execute(store store.Store) (interface{}, error) {
+	if c.Options.Overflows(store.Now()) {
+		return nil, invalidExpireTime(c.name)
+	}
+	if _, _, err := store.Set(c.Key, c.Value, c.Options); err != nil {
+		return nil, err
+	}
+	return OK, nil
+}
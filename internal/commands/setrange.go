@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+)
+
+type SetRangeCommand struct {
+	Key    string
+	Offset int64
+	Value  string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "setrange",
+		Arity:         4,
+		Flags:         []string{FlagWrite, FlagDenyOOM},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Overwrites a part of a string value with another by an offset. Creates the key if it doesn't exist.",
+		Since:         "2.2.0",
+		Group:         GroupString,
+		Complexity:    "O(1), not counting the time taken to copy the new string in place. Usually, this string is very small so the amortized complexity is O(1). Otherwise, complexity is O(M) with M being the length of the value argument.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("offset", ArgInteger),
+			arg("value", ArgString),
+		},
+		Parse: parseSetRange,
+	})
+}
+
+func parseSetRange(args []string) (Command, error) {
+	offset, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, errs.ErrNotInteger
+	}
+	if offset < 0 {
+		return nil, errs.Errorf("offset is out of range")
+	}
+	return &SetRangeCommand{
+		Key:    args[1],
+		Offset: offset,
+		Value:  args[3],
+	}, nil
+}
+
+// Execute replies with the length of the string after the write
+func (c *SetRangeCommand) Execute(store store.Store) (interface{}, error) {
+	return store.SetRange(c.Key, c.Offset, c.Value)
+}
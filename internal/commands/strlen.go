@@ -0,0 +1,41 @@
+package commands
+
+import "github.com/hardikphalet/go-redis/internal/store"
+
+type StrLenCommand struct {
+	Key string
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "strlen",
+		Arity:         2,
+		Flags:         []string{FlagReadOnly, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"string"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO)},
+		Summary:       "Returns the length of a string value.",
+		Since:         "2.2.0",
+		Group:         GroupString,
+		Complexity:    "O(1)",
+		Arguments:     []Arg{keyArg("key", 0)},
+		Parse:         parseStrLen,
+	})
+}
+
+func parseStrLen(args []string) (Command, error) {
+	return &StrLenCommand{
+		Key: args[1],
+	}, nil
+}
+
+// Execute replies with the length of the string, 0 for a missing key
+func (c *StrLenCommand) Execute(store store.Store) (interface{}, error) {
+	val, err := store.Get(c.Key)
+	if err != nil || val == nil {
+		return 0, err
+	}
+	return len(val.(string)), nil
+}
@@ -2,14 +2,72 @@ package commands
 
 import "github.com/hardikphalet/go-redis/internal/store"
 
+// TtlCommand implements TTL, PTTL, EXPIRETIME and PEXPIRETIME, which report
+// a key's expiration as a time to live or as a Unix timestamp, in seconds or
+// milliseconds
 type TtlCommand struct {
-	Key string
+	Key          string
+	Milliseconds bool
+	Absolute     bool
 }
 
+func init() {
+	ttl := func(name, summary, since string, milliseconds, absolute bool) *CommandSpec {
+		spec := &CommandSpec{
+			Name:          name,
+			Arity:         2,
+			Flags:         []string{FlagReadOnly, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"keyspace"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+			Summary:       summary,
+			Since:         since,
+			Group:         GroupGeneric,
+			Complexity:    "O(1)",
+			Arguments:     []Arg{keyArg("key", 0)},
+			Parse: func(args []string) (Command, error) {
+				return &TtlCommand{Key: args[1], Milliseconds: milliseconds, Absolute: absolute}, nil
+			},
+		}
+		// A time to live changes from one call to the next
+		if !absolute {
+			spec.Tips = []string{"nondeterministic_output"}
+		}
+		return spec
+	}
+
+	Register(ttl("ttl", "Returns the expiration time in seconds of a key.",
+		"1.0.0", false, false))
+	Register(ttl("pttl", "Returns the expiration time in milliseconds of a key.",
+		"2.6.0", true, false))
+	Register(ttl("expiretime", "Returns the expiration time of a key as a Unix timestamp.",
+		"7.0.0", false, true))
+	Register(ttl("pexpiretime", "Returns the expiration time of a key as a Unix milliseconds timestamp.",
+		"7.0.0", true, true))
+}
+
+// Execute replies -2 if the key doesn't exist and -1 if it has no expiry.
+// Seconds are rounded to the nearest one, as Redis does.
 func (c *TtlCommand) Execute(store store.Store) (interface{}, error) {
-	ttl, err := store.TTL(c.Key)
+	at, exists, err := store.ExpireTime(c.Key)
 	if err != nil {
 		return nil, err
 	}
-	return ttl, nil
+	if !exists {
+		return -2, nil
+	}
+	if at.IsZero() {
+		return -1, nil
+	}
+
+	ms := at.UnixMilli()
+	if !c.Absolute {
+		ms = max(ms-store.Now().UnixMilli(), 0)
+	}
+	if c.Milliseconds {
+		return ms, nil
+	}
+	return (ms + 500) / 1000, nil
 }
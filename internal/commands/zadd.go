@@ -1,7 +1,12 @@
 package commands
 
 import (
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
 	"github.com/hardikphalet/go-redis/internal/store"
 	"github.com/hardikphalet/go-redis/internal/types"
 )
@@ -18,6 +23,97 @@ type ZAddCommand struct {
 	Options *options.ZAddOptions
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "zadd",
+		Arity:         -4,
+		Flags:         []string{FlagWrite, FlagDenyOOM, FlagFast},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"sortedset"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyUpdate)},
+		Summary:       "Adds one or more members to a sorted set, or updates their scores. Creates the key if it doesn't exist.",
+		Since:         "1.2.0",
+		Group:         GroupSortedSet,
+		Complexity:    "O(log(N)) for each item added, where N is the number of elements in the sorted set.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			oneOf("condition",
+				tokenArg("nx", "NX"),
+				tokenArg("xx", "XX"),
+			).optional().since("3.0.2"),
+			oneOf("comparison",
+				tokenArg("gt", "GT"),
+				tokenArg("lt", "LT"),
+			).optional().since("6.2.0"),
+			tokenArg("change", "CH").optional().since("3.0.2"),
+			tokenArg("increment", "INCR").optional().since("3.0.2"),
+			block("data",
+				arg("score", ArgDouble),
+				arg("member", ArgString),
+			).multiple(),
+		},
+		Parse: parseZAdd,
+	})
+}
+
+func parseZAdd(args []string) (Command, error) {
+	// Parse options, which follow the key
+	flags := make(map[string]bool)
+	i := 2
+	for ; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "NX", "XX", "GT", "LT", "CH", "INCR":
+			flags[opt] = true
+			continue
+		}
+		break
+	}
+
+	// The rest are score-member pairs
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, errs.ErrSyntax
+	}
+	if flags["INCR"] && len(pairs) > 2 {
+		return nil, errs.Errorf("INCR option supports a single increment-element pair")
+	}
+	if flags["NX"] && flags["XX"] {
+		return nil, errs.Errorf("XX and NX options at the same time are not compatible")
+	}
+	if (flags["GT"] && flags["NX"]) || (flags["LT"] && flags["NX"]) || (flags["GT"] && flags["LT"]) {
+		return nil, errs.Errorf("GT, LT, and/or NX options at the same time are not compatible")
+	}
+
+	opts := options.NewZAddOptions()
+	for opt := range flags {
+		if err := opts.Set(opt); err != nil {
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	// Parse score-member pairs
+	members := make([]types.ScoreMember, 0, len(pairs)/2)
+	for j := 0; j < len(pairs); j += 2 {
+		score, err := strconv.ParseFloat(pairs[j], 64)
+		if err != nil || math.IsNaN(score) {
+			return nil, errs.ErrNotFloat
+		}
+		members = append(members, types.ScoreMember{
+			Score:  score,
+			Member: pairs[j+1],
+		})
+	}
+
+	return &ZAddCommand{
+		Key:     args[1],
+		Members: members,
+		Options: opts,
+	}, nil
+}
+
 func (c *ZAddCommand) Execute(store store.Store) (interface{}, error) {
 	return store.ZAdd(c.Key, c.Members, c.Options)
 }
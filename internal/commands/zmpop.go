@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// ZMPopCommand pops members from the first non-empty sorted set of several
+type ZMPopCommand struct {
+	Keys  []string
+	Max   bool
+	Count int
+}
+
+// minMaxArg is the MIN or MAX argument picking an end of a sorted set
+func minMaxArg() Arg {
+	return oneOf("where",
+		tokenArg("min", "MIN"),
+		tokenArg("max", "MAX"),
+	)
+}
+
+func init() {
+	Register(&CommandSpec{
+		Name:          "zmpop",
+		Arity:         -4,
+		Flags:         []string{FlagWrite, FlagMovableKeys},
+		ACLCategories: []string{"sortedset"},
+		KeySpecs:      []KeySpec{numKeys(1, 0, 1, 1, KeyRW, KeyAccess, KeyDelete)},
+		Summary:       "Returns the highest- or lowest-scoring members from one or more sorted sets after removing them. Deletes the sorted set if the last member was popped.",
+		Since:         "7.0.0",
+		Group:         GroupSortedSet,
+		Complexity:    "O(K) + O(M*log(N)) where K is the number of provided keys, N being the number of elements in the sorted set, and M being the number of elements popped.",
+		Arguments: []Arg{
+			arg("numkeys", ArgInteger),
+			keyArg("key", 0).multiple(),
+			minMaxArg(),
+			arg("count", ArgInteger).token("COUNT").optional(),
+		},
+		Parse: func(args []string) (Command, error) {
+			keys, max, count, err := parseMPop(args, 1, parseMinMax)
+			if err != nil {
+				return nil, err
+			}
+			return &ZMPopCommand{Keys: keys, Max: max, Count: count}, nil
+		},
+	})
+}
+
+// zmpopReply is the reply of ZMPOP and BZMPOP: the key popped from and an
+// array of member and score pairs, or a nil array if nothing was popped
+func zmpopReply(key string, popped []types.ScoreMember) interface{} {
+	if popped == nil {
+		return []interface{}(nil)
+	}
+	pairs := make([]interface{}, len(popped))
+	for i, m := range popped {
+		pairs[i] = []interface{}{m.Member, m.Score}
+	}
+	return []interface{}{key, pairs}
+}
+
+// Execute replies with the key popped from and an array of the members with
+// their scores, or a nil array if all of the sorted sets are missing
+func (c *ZMPopCommand) Execute(store store.Store) (interface{}, error) {
+	key, popped, err := store.ZMPop(c.Keys, c.Max, c.Count)
+	if err != nil {
+		return nil, err
+	}
+	return zmpopReply(key, popped), nil
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// ZPopCommand implements ZPOPMIN and ZPOPMAX, which remove the members with
+// the lowest or highest scores from a sorted set
+type ZPopCommand struct {
+	Key   string
+	Max   bool
+	Count int
+	// HasCount is set if a count was given, which pairs the members with
+	// their scores on RESP3
+	HasCount bool
+}
+
+func init() {
+	zpop := func(name, summary string, max bool) *CommandSpec {
+		return &CommandSpec{
+			Name:          name,
+			Arity:         -2,
+			Flags:         []string{FlagWrite, FlagFast},
+			FirstKey:      1,
+			LastKey:       1,
+			Step:          1,
+			ACLCategories: []string{"sortedset"},
+			KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRW, KeyAccess, KeyDelete)},
+			Summary:       summary,
+			Since:         "5.0.0",
+			Group:         GroupSortedSet,
+			Complexity:    "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Arguments: []Arg{
+				keyArg("key", 0),
+				arg("count", ArgInteger).optional(),
+			},
+			Parse: func(args []string) (Command, error) {
+				if len(args) > 3 {
+					return nil, errWrongArgs(args[0])
+				}
+				cmd := &ZPopCommand{Key: args[1], Max: max, Count: 1}
+				if len(args) == 3 {
+					count, err := parseListCount(args[2], "value is out of range, must be positive")
+					if err != nil {
+						return nil, err
+					}
+					cmd.Count = count
+					cmd.HasCount = true
+				}
+				return cmd, nil
+			},
+		}
+	}
+
+	Register(zpop("zpopmin", "Returns the lowest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.", false))
+	Register(zpop("zpopmax", "Returns the highest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.", true))
+}
+
+// parseMinMax parses MIN or MAX, reporting whether it is MAX
+func parseMinMax(arg string) (bool, error) {
+	switch strings.ToUpper(arg) {
+	case "MIN":
+		return false, nil
+	case "MAX":
+		return true, nil
+	default:
+		return false, errs.ErrSyntax
+	}
+}
+
+// scoreMembersReply is a flat array of members each followed by its score
+func scoreMembersReply(members []types.ScoreMember) []interface{} {
+	items := make([]interface{}, 0, len(members)*2)
+	for _, m := range members {
+		items = append(items, m.Member, m.Score)
+	}
+	return items
+}
+
+// Execute replies with an array of the members popped, each followed by its
+// score, empty if the sorted set is missing. With a count, the members are
+// paired with their scores on RESP3.
+func (c *ZPopCommand) Execute(store store.Store) (interface{}, error) {
+	popped, err := store.ZPop(c.Key, c.Max, c.Count)
+	if err != nil {
+		return nil, err
+	}
+	if c.HasCount {
+		return types.Pairs(scoreMembersReply(popped)), nil
+	}
+	return scoreMembersReply(popped), nil
+}
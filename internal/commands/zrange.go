@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
 	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
 )
 
 type ZRangeCommand struct {
@@ -12,6 +17,136 @@ type ZRangeCommand struct {
 	Options *options.ZRangeOptions
 }
 
+func init() {
+	Register(&CommandSpec{
+		Name:          "zrange",
+		Arity:         -4,
+		Flags:         []string{FlagReadOnly},
+		FirstKey:      1,
+		LastKey:       1,
+		Step:          1,
+		ACLCategories: []string{"sortedset"},
+		KeySpecs:      []KeySpec{rangeKeys(1, 0, 1, KeyRO, KeyAccess)},
+		Summary:       "Returns members in a sorted set within a range of indexes.",
+		Since:         "1.2.0",
+		Group:         GroupSortedSet,
+		Complexity:    "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned.",
+		Arguments: []Arg{
+			keyArg("key", 0),
+			arg("start", ArgString),
+			arg("stop", ArgString),
+			oneOf("sortby",
+				tokenArg("byscore", "BYSCORE"),
+				tokenArg("bylex", "BYLEX"),
+			).optional().since("6.2.0"),
+			tokenArg("rev", "REV").optional().since("6.2.0"),
+			block("limit",
+				arg("offset", ArgInteger),
+				arg("count", ArgInteger),
+			).token("LIMIT").optional().since("6.2.0"),
+			tokenArg("withscores", "WITHSCORES").optional(),
+		},
+		Parse: parseZRange,
+	})
+}
+
+func parseZRange(args []string) (Command, error) {
+	// Create options
+	opts := options.NewZRangeOptions()
+	hasLimit := false
+
+	// Parse options
+	i := 4 // Start after key, start, stop
+	for i < len(args) {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "BYSCORE", "BYLEX":
+			if err := opts.SetRangeType(opt); err != nil {
+				return nil, errs.ErrSyntax
+			}
+			i++
+		case "REV":
+			opts.Rev = true
+			i++
+		case "WITHSCORES":
+			opts.WithScores = true
+			i++
+		case "LIMIT":
+			if i+2 >= len(args) {
+				return nil, errs.ErrSyntax
+			}
+			offset, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return nil, errs.ErrNotInteger
+			}
+			count, err := strconv.Atoi(args[i+2])
+			if err != nil {
+				return nil, errs.ErrNotInteger
+			}
+			// A negative count returns all the remaining elements
+			if count >= 0 {
+				if err := opts.SetLimit(offset, count); err != nil {
+					return nil, errs.ErrSyntax
+				}
+			}
+			hasLimit = true
+			i += 3
+		default:
+			return nil, errs.ErrSyntax
+		}
+	}
+
+	if hasLimit && opts.RangeType == "" {
+		return nil, errs.Errorf("syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	}
+	if opts.WithScores && opts.IsByLex() {
+		return nil, errs.Errorf("syntax error, WITHSCORES not supported in combination with BYLEX")
+	}
+
+	// Parse start and stop based on range type
+	var start, stop interface{}
+	var err error
+
+	if opts.IsByScore() {
+		// For BYSCORE, start and stop are scores
+		start, err = strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return nil, errs.Errorf("min or max is not a float")
+		}
+		stop, err = strconv.ParseFloat(args[3], 64)
+		if err != nil {
+			return nil, errs.Errorf("min or max is not a float")
+		}
+	} else if opts.IsByLex() {
+		// For BYLEX, start and stop are lexicographical strings
+		start = args[2]
+		stop = args[3]
+	} else {
+		// For index-based range, start and stop are integers
+		start, err = strconv.Atoi(args[2])
+		if err != nil {
+			return nil, errs.ErrNotInteger
+		}
+		stop, err = strconv.Atoi(args[3])
+		if err != nil {
+			return nil, errs.ErrNotInteger
+		}
+	}
+
+	return &ZRangeCommand{
+		Key:     args[1],
+		Start:   start,
+		Stop:    stop,
+		Options: opts,
+	}, nil
+}
+
+// Execute replies with the members in range, paired with their scores on
+// RESP3 if WITHSCORES is given
 func (c *ZRangeCommand) Execute(store store.Store) (interface{}, error) {
-	return store.ZRange(c.Key, c.Start, c.Stop, c.Options)
+	result, err := store.ZRange(c.Key, c.Start, c.Stop, c.Options)
+	if err != nil || !c.Options.IsWithScores() {
+		return result, err
+	}
+	return types.Pairs(result), nil
 }
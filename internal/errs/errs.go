@@ -0,0 +1,108 @@
+// Package errs defines the error replies the server sends to clients.
+//
+// Redis errors start with an upper-case word naming the error class, such as
+// ERR or WRONGTYPE, which clients use to tell errors apart. An *Error carries
+// that prefix and is written to the client verbatim; any other error is sent
+// with the generic ERR prefix.
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error prefixes, as sent by Redis
+const (
+	PrefixErr       = "ERR"
+	PrefixWrongType = "WRONGTYPE"
+	PrefixNoAuth    = "NOAUTH"
+	PrefixWrongPass = "WRONGPASS"
+	PrefixNoPerm    = "NOPERM"
+	PrefixNoProto   = "NOPROTO"
+	PrefixNoScript  = "NOSCRIPT"
+	PrefixBusy      = "BUSY"
+	PrefixBusyKey   = "BUSYKEY"
+	PrefixMoved     = "MOVED"
+	PrefixAsk       = "ASK"
+	PrefixExecAbort = "EXECABORT"
+	PrefixOOM       = "OOM"
+	PrefixLoading   = "LOADING"
+	PrefixReadOnly  = "READONLY"
+	PrefixUnblocked = "UNBLOCKED"
+)
+
+// Error is an error reply with an explicit prefix
+type Error struct {
+	Prefix string
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.Msg == "" {
+		return e.Prefix
+	}
+	return e.Prefix + " " + e.Msg
+}
+
+// New returns an error with the given prefix and message
+func New(prefix, msg string) *Error {
+	return &Error{Prefix: prefix, Msg: msg}
+}
+
+// Newf returns an error with the given prefix and a formatted message
+func Newf(prefix, format string, args ...interface{}) *Error {
+	return &Error{Prefix: prefix, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Errorf returns a generic ERR error with a formatted message
+func Errorf(format string, args ...interface{}) *Error {
+	return Newf(PrefixErr, format, args...)
+}
+
+// Parse splits the text of an error reply, such as "WRONGTYPE Operation
+// against...", into its prefix and message
+func Parse(s string) *Error {
+	prefix, msg, _ := strings.Cut(s, " ")
+	return &Error{Prefix: prefix, Msg: msg}
+}
+
+// Prefix returns the prefix the error is sent with
+func Prefix(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.Prefix
+	}
+	return PrefixErr
+}
+
+// Common errors, worded as in Redis
+var (
+	ErrSyntax     = New(PrefixErr, "syntax error")
+	ErrNotInteger = New(PrefixErr, "value is not an integer or out of range")
+	ErrNotFloat   = New(PrefixErr, "value is not a valid float")
+	ErrWrongType  = New(PrefixWrongType, "Operation against a key holding the wrong kind of value")
+	ErrNoSuchKey  = New(PrefixErr, "no such key")
+	ErrNoAuth     = New(PrefixNoAuth, "Authentication required.")
+	ErrWrongPass  = New(PrefixWrongPass, "invalid username-password pair or user is disabled.")
+	ErrNoScript   = New(PrefixNoScript, "No matching script. Please use EVAL.")
+	ErrBusy       = New(PrefixBusy, "Redis is busy running a script. You can only call SCRIPT KILL or SHUTDOWN NOSCRIPT.")
+	ErrExecAbort  = New(PrefixExecAbort, "Transaction discarded because of previous errors.")
+	ErrOOM        = New(PrefixOOM, "command not allowed when used memory > 'maxmemory'.")
+	ErrLoading    = New(PrefixLoading, "Redis is loading the dataset in memory")
+	ErrReadOnly   = New(PrefixReadOnly, "You can't write against a read only replica.")
+)
+
+// Moved redirects a cluster client to the node serving slot
+func Moved(slot int, addr string) *Error {
+	return Newf(PrefixMoved, "%d %s", slot, addr)
+}
+
+// Ask redirects a cluster client to addr for a single command while slot is
+// being migrated
+func Ask(slot int, addr string) *Error {
+	return Newf(PrefixAsk, "%d %s", slot, addr)
+}
+
+// NoPerm reports that user may not run command
+func NoPerm(user, command string) *Error {
+	return Newf(PrefixNoPerm, "User %s has no permissions to run the '%s' command", user, command)
+}
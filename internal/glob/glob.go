@@ -0,0 +1,140 @@
+// Package glob matches strings against the glob-style patterns of KEYS, SCAN
+// and PSUBSCRIBE. It is a port of Redis's stringmatchlen, so patterns behave
+// exactly as they do in Redis: '*' matches any sequence of bytes, including
+// none, '?' any single byte, "[abc]" one of the bytes listed, "[^abc]" a byte
+// not listed and "[a-z]" a byte in the range, whichever way round it is
+// given. A backslash matches the byte after it literally, also inside
+// brackets.
+//
+// An unterminated bracket extends to the end of the pattern.
+package glob
+
+// maxNesting bounds the recursion a pattern may cause. Each '*' recurses
+// once, so this rejects patterns with more stars than any real use has.
+const maxNesting = 1000
+
+// Match reports whether s matches pattern
+func Match(pattern, s string) bool {
+	skipLonger := false
+	return match(pattern, s, false, &skipLonger, 0)
+}
+
+// MatchNoCase is Match ignoring the case of ASCII letters
+func MatchNoCase(pattern, s string) bool {
+	skipLonger := false
+	return match(pattern, s, true, &skipLonger, 0)
+}
+
+// match matches s against pattern. skipLonger is set once the rest of the
+// pattern after a '*' matched nowhere in s: no earlier '*' matching more can
+// help then, which keeps patterns like "*a*a*a*b" from taking exponential
+// time.
+func match(pattern, s string, nocase bool, skipLonger *bool, nesting int) bool {
+	if nesting > maxNesting {
+		return false
+	}
+
+	for len(pattern) > 0 && len(s) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for ; len(s) > 0; s = s[1:] {
+				if match(pattern[1:], s, nocase, skipLonger, nesting+1) {
+					return true
+				}
+				if *skipLonger {
+					return false
+				}
+			}
+			*skipLonger = true
+			return false
+		case '?':
+			pattern, s = pattern[1:], s[1:]
+		case '[':
+			var ok bool
+			if pattern, ok = matchClass(pattern[1:], s[0], nocase); !ok {
+				return false
+			}
+			s = s[1:]
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if !equal(pattern[0], s[0], nocase) {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+
+		// Trailing stars match the empty rest of s
+		if len(s) == 0 {
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+		}
+	}
+	return len(pattern) == 0 && len(s) == 0
+}
+
+// matchClass matches c against the bracket expression at the start of
+// pattern, just after its '[', and returns the pattern following it
+func matchClass(pattern string, c byte, nocase bool) (string, bool) {
+	not := len(pattern) > 0 && pattern[0] == '^'
+	if not {
+		pattern = pattern[1:]
+	}
+
+	matched := false
+	for len(pattern) > 0 {
+		switch {
+		case pattern[0] == '\\' && len(pattern) >= 2:
+			// An escaped byte is compared with case, as in Redis
+			if pattern[1] == c {
+				matched = true
+			}
+			pattern = pattern[2:]
+		case pattern[0] == ']':
+			return pattern[1:], matched != not
+		case len(pattern) >= 3 && pattern[1] == '-':
+			start, end := pattern[0], pattern[2]
+			if start > end {
+				start, end = end, start
+			}
+			b := c
+			if nocase {
+				start, end, b = lower(start), lower(end), lower(b)
+			}
+			if b >= start && b <= end {
+				matched = true
+			}
+			pattern = pattern[3:]
+		default:
+			if equal(pattern[0], c, nocase) {
+				matched = true
+			}
+			pattern = pattern[1:]
+		}
+	}
+	return pattern, matched != not
+}
+
+func equal(a, b byte, nocase bool) bool {
+	if nocase {
+		return lower(a) == lower(b)
+	}
+	return a == b
+}
+
+func lower(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+// Package notify defines the classes of keyspace notifications, which
+// notify-keyspace-events selects with one character per class.
+package notify
+
+import "fmt"
+
+// Class is a set of notify-keyspace-events flags
+type Class int
+
+const (
+	// Keyspace (K) publishes events to __keyspace@<db>__:<key>
+	Keyspace Class = 1 << iota
+	// Keyevent (E) publishes events to __keyevent@<db>__:<event>
+	Keyevent
+	Generic // g: commands not specific to a type, such as DEL and EXPIRE
+	String  // $
+	List    // l
+	Set     // s
+	Hash    // h
+	ZSet    // z
+	Expired // x: keys deleted because their TTL elapsed
+	Evicted // e: keys evicted because of maxmemory
+	Stream  // t
+	KeyMiss // m: reads of keys that don't exist
+	Module  // d
+	New     // n: keys added to the keyspace
+
+	// All (A) is every class but KeyMiss and New, which have to be asked
+	// for explicitly
+	All = Generic | String | List | Set | Hash | ZSet | Expired | Evicted | Stream | Module
+)
+
+// Func is told about every event of the keyspace, whether or not its class
+// is enabled
+type Func func(class Class, event, key string)
+
+// flagChars maps each class to its character
+var flagChars = []struct {
+	class Class
+	char  byte
+}{
+	{Generic, 'g'},
+	{String, '$'},
+	{List, 'l'},
+	{Set, 's'},
+	{Hash, 'h'},
+	{ZSet, 'z'},
+	{Expired, 'x'},
+	{Evicted, 'e'},
+	{Stream, 't'},
+	{Module, 'd'},
+	{Keyspace, 'K'},
+	{Keyevent, 'E'},
+	{KeyMiss, 'm'},
+	{New, 'n'},
+}
+
+// Parse parses a notify-keyspace-events value such as "KEA" or "Ex"
+func Parse(s string) (Class, error) {
+	var classes Class
+next:
+	for i := 0; i < len(s); i++ {
+		if s[i] == 'A' {
+			classes |= All
+			continue
+		}
+		for _, f := range flagChars {
+			if s[i] == f.char {
+				classes |= f.class
+				continue next
+			}
+		}
+		return 0, fmt.Errorf("Invalid event class character. Use 'Ag$lshzxeKEtmdn'.")
+	}
+	return classes, nil
+}
@@ -0,0 +1,245 @@
+// Package pubsub routes published messages to the connections subscribed to
+// their channel, or to a pattern matching it. Shard channels, used by the
+// sharded variants of the commands, are a namespace of their own.
+package pubsub
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hardikphalet/go-redis/internal/glob"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Hub keeps every subscription of the server
+type Hub struct {
+	mu       sync.RWMutex
+	channels map[string]map[*Subscriber]struct{}
+	patterns map[string]map[*Subscriber]struct{}
+	shards   map[string]map[*Subscriber]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		channels: make(map[string]map[*Subscriber]struct{}),
+		patterns: make(map[string]map[*Subscriber]struct{}),
+		shards:   make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Subscribe subscribes sub to channel. It returns false if sub was already
+// subscribed.
+func (h *Hub) Subscribe(sub *Subscriber, channel string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return subscribe(h.channels, sub.channels, sub, channel)
+}
+
+// Unsubscribe unsubscribes sub from channel. It returns false if sub wasn't
+// subscribed.
+func (h *Hub) Unsubscribe(sub *Subscriber, channel string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return unsubscribe(h.channels, sub.channels, sub, channel)
+}
+
+// PSubscribe subscribes sub to the channels matching pattern
+func (h *Hub) PSubscribe(sub *Subscriber, pattern string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return subscribe(h.patterns, sub.patterns, sub, pattern)
+}
+
+// PUnsubscribe unsubscribes sub from pattern
+func (h *Hub) PUnsubscribe(sub *Subscriber, pattern string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return unsubscribe(h.patterns, sub.patterns, sub, pattern)
+}
+
+// SSubscribe subscribes sub to a shard channel
+func (h *Hub) SSubscribe(sub *Subscriber, channel string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return subscribe(h.shards, sub.shards, sub, channel)
+}
+
+// SUnsubscribe unsubscribes sub from a shard channel
+func (h *Hub) SUnsubscribe(sub *Subscriber, channel string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return unsubscribe(h.shards, sub.shards, sub, channel)
+}
+
+// UnsubscribeAll drops every subscription of sub, as when its connection
+// closes
+func (h *Hub) UnsubscribeAll(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for channel := range sub.channels {
+		unsubscribe(h.channels, sub.channels, sub, channel)
+	}
+	for pattern := range sub.patterns {
+		unsubscribe(h.patterns, sub.patterns, sub, pattern)
+	}
+	for channel := range sub.shards {
+		unsubscribe(h.shards, sub.shards, sub, channel)
+	}
+}
+
+func subscribe(index map[string]map[*Subscriber]struct{}, own map[string]struct{}, sub *Subscriber, name string) bool {
+	if _, ok := own[name]; ok {
+		return false
+	}
+	own[name] = struct{}{}
+
+	subs, ok := index[name]
+	if !ok {
+		subs = make(map[*Subscriber]struct{})
+		index[name] = subs
+	}
+	subs[sub] = struct{}{}
+	return true
+}
+
+func unsubscribe(index map[string]map[*Subscriber]struct{}, own map[string]struct{}, sub *Subscriber, name string) bool {
+	if _, ok := own[name]; !ok {
+		return false
+	}
+	delete(own, name)
+
+	delete(index[name], sub)
+	if len(index[name]) == 0 {
+		delete(index, name)
+	}
+	return true
+}
+
+// Publish delivers message to the subscribers of channel and of the patterns
+// matching it, and returns how many received it. It never waits for a
+// subscriber: messages are queued for each connection to write.
+func (h *Hub) Publish(channel, message string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	receivers := 0
+	for sub := range h.channels[channel] {
+		sub.deliver(types.Push{"message", channel, message})
+		receivers++
+	}
+	for pattern, subs := range h.patterns {
+		if !glob.Match(pattern, channel) {
+			continue
+		}
+		for sub := range subs {
+			sub.deliver(types.Push{"pmessage", pattern, channel, message})
+			receivers++
+		}
+	}
+	return receivers
+}
+
+// SPublish delivers message to the subscribers of a shard channel and
+// returns how many received it. Patterns never match shard channels.
+func (h *Hub) SPublish(channel, message string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.shards[channel] {
+		sub.deliver(types.Push{"smessage", channel, message})
+	}
+	return len(h.shards[channel])
+}
+
+// Channels returns the channels with at least one subscriber matching
+// pattern, or all of them for an empty pattern
+func (h *Hub) Channels(pattern string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return matchingKeys(h.channels, pattern)
+}
+
+// ShardChannels returns the shard channels with at least one subscriber
+// matching pattern, or all of them for an empty pattern
+func (h *Hub) ShardChannels(pattern string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return matchingKeys(h.shards, pattern)
+}
+
+func matchingKeys(index map[string]map[*Subscriber]struct{}, pattern string) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		if pattern == "" || glob.Match(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NumSub returns the number of subscribers of channel, not counting pattern
+// subscriptions
+func (h *Hub) NumSub(channel string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.channels[channel])
+}
+
+// ShardNumSub returns the number of subscribers of a shard channel
+func (h *Hub) ShardNumSub(channel string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.shards[channel])
+}
+
+// NumPat returns the number of distinct patterns subscribed to
+func (h *Hub) NumPat() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.patterns)
+}
+
+// Count returns the number of channels and patterns sub is subscribed to
+func (h *Hub) Count(sub *Subscriber) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(sub.channels) + len(sub.patterns)
+}
+
+// ShardCount returns the number of shard channels sub is subscribed to
+func (h *Hub) ShardCount(sub *Subscriber) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(sub.shards)
+}
+
+// ChannelsOf returns the channels sub is subscribed to
+func (h *Hub) ChannelsOf(sub *Subscriber) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return sortedKeys(sub.channels)
+}
+
+// PatternsOf returns the patterns sub is subscribed to
+func (h *Hub) PatternsOf(sub *Subscriber) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return sortedKeys(sub.patterns)
+}
+
+// ShardChannelsOf returns the shard channels sub is subscribed to
+func (h *Hub) ShardChannelsOf(sub *Subscriber) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return sortedKeys(sub.shards)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Subscriber holds a connection's subscriptions and the messages published
+// to them that the connection hasn't written yet. The queue is bounded by a
+// size limit, like Redis's client-output-buffer-limit for pubsub clients: a
+// subscriber that falls too far behind overflows, and stops receiving
+// messages.
+type Subscriber struct {
+	// channels, patterns and shards are guarded by the hub's lock
+	channels map[string]struct{}
+	patterns map[string]struct{}
+	shards   map[string]struct{}
+
+	mu         sync.Mutex
+	queue      []types.Push
+	size       int64
+	limit      int64
+	overflowed bool
+	onOverflow func()
+	ready      chan struct{}
+}
+
+// NewSubscriber returns a subscriber whose queue holds at most limit bytes
+// of messages, or any amount for a limit of 0. onOverflow is called once,
+// by the publisher, when the limit is exceeded; it must not block.
+func NewSubscriber(limit int64, onOverflow func()) *Subscriber {
+	return &Subscriber{
+		channels:   make(map[string]struct{}),
+		patterns:   make(map[string]struct{}),
+		shards:     make(map[string]struct{}),
+		limit:      limit,
+		onOverflow: onOverflow,
+		ready:      make(chan struct{}, 1),
+	}
+}
+
+// Ready receives a value when messages are waiting to be taken
+func (s *Subscriber) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Take empties the queue and returns the messages in it
+func (s *Subscriber) Take() []types.Push {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.queue
+	s.queue, s.size = nil, 0
+	return messages
+}
+
+// deliver queues a message without waiting for the connection
+func (s *Subscriber) deliver(msg types.Push) {
+	s.mu.Lock()
+	if s.overflowed {
+		s.mu.Unlock()
+		return
+	}
+	s.queue = append(s.queue, msg)
+	s.size += messageSize(msg)
+	overflowed := s.limit > 0 && s.size > s.limit
+	if overflowed {
+		s.overflowed = true
+		s.queue = nil
+	}
+	s.mu.Unlock()
+
+	if overflowed {
+		if s.onOverflow != nil {
+			s.onOverflow()
+		}
+		return
+	}
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// messageSize approximates the bytes a message takes on the wire
+func messageSize(msg types.Push) int64 {
+	size := int64(4)
+	for _, el := range msg {
+		if s, ok := el.(string); ok {
+			size += int64(len(s)) + 8
+		}
+	}
+	return size
+}
@@ -0,0 +1,157 @@
+package resp
+
+import (
+	"errors"
+	"strings"
+)
+
+// maxInlineSize is the longest inline request Redis accepts (PROTO_INLINE_MAX_SIZE)
+const maxInlineSize = 64 * 1024
+
+// ErrUnbalancedQuotes is returned by SplitArgs when a quoted argument is not
+// properly terminated
+var ErrUnbalancedQuotes = errors.New("unbalanced quotes")
+
+// parseInline parses an inline request: a single line of whitespace
+// separated arguments, as sent by telnet sessions and simple health checks
+func (p *Parser) parseInline() ([]string, error) {
+	line, err := p.readInlineLine()
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := SplitArgs(line)
+	if err != nil {
+		return nil, &ProtocolError{Msg: "unbalanced quotes in request"}
+	}
+	return args, nil
+}
+
+// readInlineLine reads up to the next newline, refusing lines longer than
+// maxInlineSize, and strips the line terminator
+func (p *Parser) readInlineLine() (string, error) {
+	line, err := p.readBoundedLine("too big inline request")
+	if err != nil {
+		return "", err
+	}
+
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return string(line), nil
+}
+
+// SplitArgs splits a line into arguments following the rules of Redis's
+// sdssplitargs: arguments are separated by whitespace, "double quoted"
+// arguments support \n, \r, \t, \b, \a and \xHH escapes, and 'single quoted'
+// arguments only support \' as an escape. A closing quote must be followed
+// by whitespace or the end of the line.
+func SplitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	i := 0
+	for {
+		// Skip blanks
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+
+		var current strings.Builder
+		inDouble, inSingle, done := false, false, false
+		for !done {
+			if inDouble {
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				switch {
+				case line[i] == '\\' && i+3 < len(line) && line[i+1] == 'x' &&
+					isHexDigit(line[i+2]) && isHexDigit(line[i+3]):
+					current.WriteByte(hexValue(line[i+2])<<4 | hexValue(line[i+3]))
+					i += 3
+				case line[i] == '\\' && i+1 < len(line):
+					i++
+					switch line[i] {
+					case 'n':
+						current.WriteByte('\n')
+					case 'r':
+						current.WriteByte('\r')
+					case 't':
+						current.WriteByte('\t')
+					case 'b':
+						current.WriteByte('\b')
+					case 'a':
+						current.WriteByte('\a')
+					default:
+						current.WriteByte(line[i])
+					}
+				case line[i] == '"':
+					// The closing quote must be followed by a space or end the line
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				default:
+					current.WriteByte(line[i])
+				}
+			} else if inSingle {
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				switch {
+				case line[i] == '\\' && i+1 < len(line) && line[i+1] == '\'':
+					i++
+					current.WriteByte('\'')
+				case line[i] == '\'':
+					// The closing quote must be followed by a space or end the line
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				default:
+					current.WriteByte(line[i])
+				}
+			} else {
+				if i >= len(line) {
+					break
+				}
+				switch line[i] {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inDouble = true
+				case '\'':
+					inSingle = true
+				default:
+					current.WriteByte(line[i])
+				}
+			}
+			if i < len(line) {
+				i++
+			}
+		}
+
+		args = append(args, current.String())
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
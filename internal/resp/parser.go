@@ -2,93 +2,146 @@ package resp
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
-	"fmt"
 	"io"
+	"math"
+	"slices"
 	"strconv"
-	"strings"
-	"time"
-
-	"github.com/hardikphalet/go-redis/internal/commands"
-	"github.com/hardikphalet/go-redis/internal/commands/options"
-	"github.com/hardikphalet/go-redis/internal/types"
 )
 
 var (
 	ErrInvalidSyntax = errors.New("invalid RESP syntax")
+
+	// ErrQueryBufferLimit is returned when a single request grows past the
+	// client query buffer limit. Redis closes such clients without a reply.
+	ErrQueryBufferLimit = errors.New("client reached max query buffer length")
 )
 
+// ProtocolError is a malformed request. Redis replies with the error and
+// closes the connection, since the rest of the stream can't be trusted.
+type ProtocolError struct {
+	Msg string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.Msg
+}
+
+// errExpectedCRLF is returned when a line or bulk string isn't terminated by
+// CRLF
+var errExpectedCRLF = &ProtocolError{Msg: "expected '\\r\\n'"}
+
+// Limits bounds what a single request may ask the server to allocate
+type Limits struct {
+	// MaxBulkLen is the largest accepted bulk string (proto-max-bulk-len)
+	MaxBulkLen int64
+	// MaxUnauthenticatedMultibulkLen is the largest array an unauthenticated
+	// client may send. Authenticated clients are only bounded by MaxInt32.
+	MaxUnauthenticatedMultibulkLen int64
+	// QueryBufferLimit is the largest request, in bytes, a client may send
+	// (client-query-buffer-limit)
+	QueryBufferLimit int64
+}
+
+// DefaultLimits returns the limits Redis ships with
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBulkLen:                     512 * 1024 * 1024,
+		MaxUnauthenticatedMultibulkLen: 1024 * 1024,
+		QueryBufferLimit:               1024 * 1024 * 1024,
+	}
+}
+
+// bulkReadChunk caps how much of a bulk string is allocated ahead of the data
+// actually arriving, so a large announced length alone can't exhaust memory
+const bulkReadChunk = 64 * 1024
+
 type Parser struct {
-	reader *bufio.Reader
+	reader        *bufio.Reader
+	limits        Limits
+	authenticated bool
+
+	// queryBytes counts the bytes of the request being parsed
+	queryBytes int64
 }
 
 func NewParser(reader *bufio.Reader) *Parser {
-	return &Parser{reader: reader}
+	return &Parser{reader: reader, limits: DefaultLimits(), authenticated: true}
 }
 
-// Parse reads the RESP protocol input and returns a Command
-func (p *Parser) Parse() (commands.Command, error) {
-	// Read the first byte to determine the type
-	firstByte, err := p.reader.ReadByte()
-	if err != nil {
-		return nil, err
-	}
+// SetLimits changes the limits enforced on subsequent requests
+func (p *Parser) SetLimits(limits Limits) {
+	p.limits = limits
+}
 
-	switch firstByte {
-	case '*':
-		return p.parseArray()
-	case '$':
-		return nil, fmt.Errorf("bulk string must be part of an array")
-	case '+':
-		return nil, fmt.Errorf("simple string must be part of an array")
-	case ':':
-		return nil, fmt.Errorf("integer must be part of an array")
-	case '-':
-		return nil, fmt.Errorf("error must be part of an array")
-	default:
-		return nil, ErrInvalidSyntax
+// SetAuthenticated tells the parser whether the client has authenticated,
+// which decides the multibulk length it may send
+func (p *Parser) SetAuthenticated(authenticated bool) {
+	p.authenticated = authenticated
+}
+
+// Parse reads the RESP protocol input and returns the request's arguments,
+// starting with the command name. Requests are either RESP arrays of bulk
+// strings or inline commands; empty requests are skipped.
+func (p *Parser) Parse() ([]string, error) {
+	for {
+		// Peek at the first byte to determine the request type
+		firstByte, err := p.reader.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		p.queryBytes = 0
+		var args []string
+		if firstByte[0] == '*' {
+			p.reader.ReadByte()
+			args, err = p.parseArray()
+		} else {
+			args, err = p.parseInline()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) > 0 {
+			return args, nil
+		}
 	}
 }
 
-// parseArray parses a RESP array
-func (p *Parser) parseArray() (commands.Command, error) {
+// parseArray parses a RESP array of bulk strings
+func (p *Parser) parseArray() ([]string, error) {
 	// Read the array length
-	length, err := p.readInteger()
+	line, err := p.readLine("too big mbulk count string")
 	if err != nil {
 		return nil, err
 	}
+	length, err := strconv.ParseInt(line, 10, 64)
+	if err != nil || length > math.MaxInt32 {
+		return nil, &ProtocolError{Msg: "invalid multibulk length"}
+	}
+	if !p.authenticated && length > p.limits.MaxUnauthenticatedMultibulkLen {
+		return nil, &ProtocolError{Msg: "unauthenticated multibulk length"}
+	}
 
+	// Empty and null arrays carry no command and are ignored, as in Redis
 	if length < 1 {
-		return nil, fmt.Errorf("array length must be positive")
+		return nil, nil
 	}
 
-	// Read all array elements
-	elements := make([]string, length)
-	for i := 0; i < length; i++ {
+	// Read all array elements, growing the slice as they arrive rather than
+	// trusting the announced length
+	elements := make([]string, 0, min(length, 1024))
+	for i := int64(0); i < length; i++ {
 		element, err := p.readBulkString()
 		if err != nil {
 			return nil, err
 		}
-		elements[i] = element
-	}
-
-	// Convert array elements to a command
-	return p.createCommand(elements)
-}
-
-// readInteger reads a RESP integer
-func (p *Parser) readInteger() (int, error) {
-	line, err := p.readLine()
-	if err != nil {
-		return 0, err
-	}
-
-	n, err := strconv.Atoi(line)
-	if err != nil {
-		return 0, ErrInvalidSyntax
+		elements = append(elements, element)
 	}
 
-	return n, nil
+	return elements, nil
 }
 
 // readBulkString reads a RESP bulk string
@@ -99,26 +152,34 @@ func (p *Parser) readBulkString() (string, error) {
 		return "", err
 	}
 	if b != '$' {
-		return "", ErrInvalidSyntax
+		return "", &ProtocolError{Msg: "expected '$', got '" + string(b) + "'"}
 	}
 
 	// Read the string length
-	length, err := p.readInteger()
+	line, err := p.readLine("too big bulk count string")
 	if err != nil {
 		return "", err
 	}
-
-	if length < 0 {
-		return "", nil // Null bulk string
+	length, err := strconv.ParseInt(line, 10, 64)
+	if err != nil || length < 0 || length > p.limits.MaxBulkLen {
+		return "", &ProtocolError{Msg: "invalid bulk length"}
 	}
-
-	// Read the string content
-	data := make([]byte, length)
-	_, err = io.ReadFull(p.reader, data)
-	if err != nil {
+	if err := p.account(length + 2); err != nil {
 		return "", err
 	}
 
+	// Read the string content in chunks, so memory is only committed for
+	// data that actually arrived
+	data := make([]byte, 0, min(length, bulkReadChunk))
+	for int64(len(data)) < length {
+		start := len(data)
+		n := int(min(length-int64(start), bulkReadChunk))
+		data = slices.Grow(data, n)[:start+n]
+		if _, err := io.ReadFull(p.reader, data[start:]); err != nil {
+			return "", err
+		}
+	}
+
 	// Read and verify CRLF
 	if err := p.readCRLF(); err != nil {
 		return "", err
@@ -127,18 +188,63 @@ func (p *Parser) readBulkString() (string, error) {
 	return string(data), nil
 }
 
-// readLine reads a line ending in CRLF
-func (p *Parser) readLine() (string, error) {
-	line, err := p.reader.ReadString('\n')
+// account adds n bytes to the current request and enforces the query buffer
+// limit
+func (p *Parser) account(n int64) error {
+	p.queryBytes += n
+	if p.limits.QueryBufferLimit > 0 && p.queryBytes > p.limits.QueryBufferLimit {
+		return ErrQueryBufferLimit
+	}
+	return nil
+}
+
+// readLine reads a line ending in CRLF. Lines longer than maxInlineSize are
+// rejected with a protocol error carrying tooBig.
+func (p *Parser) readLine(tooBig string) (string, error) {
+	line, err := p.readBoundedLine(tooBig)
 	if err != nil {
 		return "", err
 	}
 
 	if len(line) < 2 || line[len(line)-2] != '\r' {
-		return "", ErrInvalidSyntax
+		return "", errExpectedCRLF
 	}
 
-	return line[:len(line)-2], nil
+	return string(line[:len(line)-2]), nil
+}
+
+// readBoundedLine reads up to and including the next newline, refusing lines
+// longer than maxInlineSize. The length is checked against everything
+// received so far before waiting for more, so a client that sends a long
+// line without a newline gets an error rather than a connection that hangs.
+func (p *Parser) readBoundedLine(tooBig string) ([]byte, error) {
+	var line []byte
+	for {
+		// Wait for at least one byte, then take whatever has arrived
+		if _, err := p.reader.Peek(1); err != nil {
+			return nil, err
+		}
+		buf, _ := p.reader.Peek(p.reader.Buffered())
+
+		n := len(buf)
+		i := bytes.IndexByte(buf, '\n')
+		if i >= 0 {
+			n = i + 1
+		}
+		if len(line)+n > maxInlineSize {
+			return nil, &ProtocolError{Msg: tooBig}
+		}
+		line = append(line, buf[:n]...)
+		p.reader.Discard(n)
+		if i >= 0 {
+			break
+		}
+	}
+
+	if err := p.account(int64(len(line))); err != nil {
+		return nil, err
+	}
+	return line, nil
 }
 
 // readCRLF reads and verifies a CRLF sequence
@@ -148,7 +254,7 @@ func (p *Parser) readCRLF() error {
 		return err
 	}
 	if cr != '\r' {
-		return ErrInvalidSyntax
+		return errExpectedCRLF
 	}
 
 	lf, err := p.reader.ReadByte()
@@ -156,262 +262,8 @@ func (p *Parser) readCRLF() error {
 		return err
 	}
 	if lf != '\n' {
-		return ErrInvalidSyntax
+		return errExpectedCRLF
 	}
 
 	return nil
 }
-
-// createCommand converts string array to a specific command
-func (p *Parser) createCommand(args []string) (commands.Command, error) {
-	if len(args) == 0 {
-		return nil, fmt.Errorf("empty command")
-	}
-
-	// Convert command to uppercase for case-insensitive comparison
-	cmd := strings.ToUpper(args[0])
-
-	switch cmd {
-	case "SET":
-		if len(args) < 3 {
-			return nil, fmt.Errorf("SET command requires at least 2 arguments")
-		}
-
-		// Create options
-		opts := options.NewSetOptions()
-
-		// Parse options
-		i := 3
-		for i < len(args) {
-			opt := strings.ToUpper(args[i])
-			switch opt {
-			case "NX", "XX", "GET":
-				if err := opts.Set(opt); err != nil {
-					return nil, fmt.Errorf("invalid option: %s", err)
-				}
-				i++
-			case "EX", "PX", "EXAT", "PXAT", "KEEPTTL":
-				if opt == "KEEPTTL" {
-					if err := opts.SetExpiry(opt, 0); err != nil {
-						return nil, fmt.Errorf("invalid option: %s", err)
-					}
-					i++
-				} else {
-					if i+1 >= len(args) {
-						return nil, fmt.Errorf("missing value for %s option", opt)
-					}
-					value, err := strconv.ParseInt(args[i+1], 10, 64)
-					if err != nil {
-						return nil, fmt.Errorf("invalid value for %s option", opt)
-					}
-					if err := opts.SetExpiry(opt, value); err != nil {
-						return nil, fmt.Errorf("invalid option: %s", err)
-					}
-					i += 2
-				}
-			default:
-				return nil, fmt.Errorf("unknown option: %s", opt)
-			}
-		}
-
-		return &commands.SetCommand{
-			Key:     args[1],
-			Value:   args[2],
-			Options: opts,
-		}, nil
-
-	case "GET":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("GET command requires exactly 1 argument")
-		}
-		return &commands.GetCommand{
-			Key: args[1],
-		}, nil
-
-	case "DEL":
-		if len(args) < 2 {
-			return nil, fmt.Errorf("DEL command requires at least 1 argument")
-		}
-		return &commands.DelCommand{
-			Keys: args[1:],
-		}, nil
-
-	case "EXPIRE":
-		if len(args) < 3 {
-			return nil, fmt.Errorf("EXPIRE command requires at least 2 arguments")
-		}
-		ttl, err := strconv.Atoi(args[2])
-		if err != nil {
-			return nil, fmt.Errorf("invalid TTL value")
-		}
-
-		// Create options
-		opts := options.NewExpireOptions()
-
-		// Parse options
-		for i := 3; i < len(args); i++ {
-			opt := strings.ToUpper(args[i])
-			if err := opts.Set(opt); err != nil {
-				return nil, fmt.Errorf("invalid option: %s", err)
-			}
-		}
-
-		return &commands.ExpireCommand{
-			Key:     args[1],
-			TTL:     time.Duration(ttl) * time.Second,
-			Options: opts,
-		}, nil
-
-	case "TTL":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("TTL command requires exactly 1 argument")
-		}
-		return &commands.TtlCommand{
-			Key: args[1],
-		}, nil
-
-	case "KEYS":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("KEYS command requires exactly 1 argument")
-		}
-		return &commands.KeysCommand{
-			Pattern: args[1],
-		}, nil
-
-	case "ZADD":
-		if len(args) < 4 || (len(args)-2)%2 != 0 {
-			return nil, fmt.Errorf("ZADD command requires at least one score-member pair")
-		}
-
-		// Create options
-		opts := options.NewZAddOptions()
-
-		// Parse options
-		i := 1
-	optionLoop:
-		for i < len(args) {
-			opt := strings.ToUpper(args[i])
-			switch opt {
-			case "NX", "XX", "GT", "LT", "CH", "INCR":
-				if err := opts.Set(opt); err != nil {
-					return nil, fmt.Errorf("invalid option: %s", err)
-				}
-				i++
-			default:
-				// If not an option, it must be the key
-				break optionLoop
-			}
-		}
-
-		// Skip the key
-		i++
-
-		// Parse score-member pairs
-		members := make([]types.ScoreMember, 0, (len(args)-i)/2)
-		for i < len(args) {
-			score, err := strconv.ParseFloat(args[i], 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid score value: %s", args[i])
-			}
-			members = append(members, types.ScoreMember{
-				Score:  score,
-				Member: args[i+1],
-			})
-			i += 2
-		}
-
-		return &commands.ZAddCommand{
-			Key:     args[1],
-			Members: members,
-			Options: opts,
-		}, nil
-
-	case "ZRANGE":
-		if len(args) < 4 {
-			return nil, fmt.Errorf("ZRANGE command requires at least 3 arguments")
-		}
-
-		// Create options
-		opts := options.NewZRangeOptions()
-
-		// Parse options
-		i := 4 // Start after key, start, stop
-		for i < len(args) {
-			opt := strings.ToUpper(args[i])
-			switch opt {
-			case "BYSCORE", "BYLEX":
-				if err := opts.SetRangeType(opt); err != nil {
-					return nil, fmt.Errorf("invalid range type: %s", err)
-				}
-				i++
-			case "REV":
-				opts.Rev = true
-				i++
-			case "WITHSCORES":
-				opts.WithScores = true
-				i++
-			case "LIMIT":
-				if i+2 >= len(args) {
-					return nil, fmt.Errorf("LIMIT option requires offset and count")
-				}
-				offset, err := strconv.Atoi(args[i+1])
-				if err != nil {
-					return nil, fmt.Errorf("invalid LIMIT offset")
-				}
-				count, err := strconv.Atoi(args[i+2])
-				if err != nil {
-					return nil, fmt.Errorf("invalid LIMIT count")
-				}
-				if err := opts.SetLimit(offset, count); err != nil {
-					return nil, fmt.Errorf("invalid LIMIT parameters: %s", err)
-				}
-				i += 3
-			default:
-				return nil, fmt.Errorf("unknown option: %s", opt)
-			}
-		}
-
-		// Parse start and stop based on range type
-		var start, stop interface{}
-		var err error
-
-		if opts.IsByScore() {
-			// For BYSCORE, start and stop are scores
-			start, err = strconv.ParseFloat(args[2], 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid score range start")
-			}
-			stop, err = strconv.ParseFloat(args[3], 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid score range stop")
-			}
-		} else if opts.IsByLex() {
-			// For BYLEX, start and stop are lexicographical strings
-			start = args[2]
-			stop = args[3]
-		} else {
-			// For index-based range, start and stop are integers
-			start, err = strconv.Atoi(args[2])
-			if err != nil {
-				return nil, fmt.Errorf("invalid start index")
-			}
-			stop, err = strconv.Atoi(args[3])
-			if err != nil {
-				return nil, fmt.Errorf("invalid stop index")
-			}
-		}
-
-		return &commands.ZRangeCommand{
-			Key:     args[1],
-			Start:   start,
-			Stop:    stop,
-			Options: opts,
-		}, nil
-
-	case "COMMAND":
-		return &commands.CommandCommand{}, nil
-
-	default:
-		return nil, fmt.Errorf("unknown command: %s", cmd)
-	}
-}
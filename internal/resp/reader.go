@@ -0,0 +1,193 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Reader decodes the replies a server sends, in RESP2 or RESP3. It is the
+// client side counterpart of Writer.
+type Reader struct {
+	reader *bufio.Reader
+}
+
+func NewReader(reader *bufio.Reader) *Reader {
+	return &Reader{reader: reader}
+}
+
+// ReadReply reads the next reply. Replies decode to the types Writer
+// accepts:
+//
+//   - simple strings to types.SimpleString
+//   - errors to *errs.Error
+//   - integers to int64
+//   - bulk strings to string
+//   - nulls, including null arrays, to nil
+//   - arrays to []interface{}
+//   - maps to types.Map, sets to types.Set and pushes to types.Push
+//   - doubles to float64 and booleans to bool
+//   - big numbers to types.BigNumber
+//   - verbatim strings to types.VerbatimString
+//
+// Error replies are returned as values, so errors nested in an array (such as
+// in an EXEC reply) are kept in place. The returned error is only set when
+// the stream itself can't be read. RESP3 attributes are skipped.
+func (r *Reader) ReadReply() (interface{}, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, ErrInvalidSyntax
+	}
+
+	prefix, payload := line[0], line[1:]
+	switch prefix {
+	case '+':
+		return types.SimpleString(payload), nil
+	case '-':
+		return errs.Parse(payload), nil
+	case ':':
+		return r.parseInt(payload)
+	case '$', '!', '=':
+		n, err := r.parseLength(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data, err := r.readBulk(n)
+		if err != nil {
+			return nil, err
+		}
+		switch prefix {
+		case '!':
+			return errs.Parse(data), nil
+		case '=':
+			if len(data) < 4 || data[3] != ':' {
+				return nil, fmt.Errorf("invalid verbatim string %q", data)
+			}
+			return types.VerbatimString{Format: data[:3], Text: data[4:]}, nil
+		}
+		return data, nil
+	case '*', '~', '>':
+		n, err := r.parseLength(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		elements, err := r.readElements(n)
+		if err != nil {
+			return nil, err
+		}
+		switch prefix {
+		case '~':
+			return types.Set(elements), nil
+		case '>':
+			return types.Push(elements), nil
+		}
+		return elements, nil
+	case '%':
+		return r.readMap(payload)
+	case '|':
+		// Attributes describe the reply that follows them; skip them
+		if _, err := r.readMap(payload); err != nil {
+			return nil, err
+		}
+		return r.ReadReply()
+	case '_':
+		return nil, nil
+	case ',':
+		f, err := strconv.ParseFloat(payload, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double %q", payload)
+		}
+		return f, nil
+	case '#':
+		switch payload {
+		case "t":
+			return true, nil
+		case "f":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid boolean %q", payload)
+	case '(':
+		return types.BigNumber(payload), nil
+	default:
+		return nil, fmt.Errorf("unexpected reply type %q", prefix)
+	}
+}
+
+// readElements reads the n replies of an aggregate
+func (r *Reader) readElements(n int64) ([]interface{}, error) {
+	elements := make([]interface{}, 0, min(n, 1024))
+	for i := int64(0); i < n; i++ {
+		v, err := r.ReadReply()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, v)
+	}
+	return elements, nil
+}
+
+// readMap reads the entries of a map whose header carried payload
+func (r *Reader) readMap(payload string) (interface{}, error) {
+	n, err := r.parseLength(payload)
+	if err != nil || n < 0 {
+		return nil, err
+	}
+	elements, err := r.readElements(n * 2)
+	if err != nil {
+		return nil, err
+	}
+	m := make(types.Map, 0, n)
+	for i := 0; i < len(elements); i += 2 {
+		m = append(m, types.MapEntry{Key: elements[i], Value: elements[i+1]})
+	}
+	return m, nil
+}
+
+// readBulk reads n bytes of bulk data followed by CRLF
+func (r *Reader) readBulk(n int64) (string, error) {
+	data := make([]byte, n+2)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return "", err
+	}
+	if data[n] != '\r' || data[n+1] != '\n' {
+		return "", ErrInvalidSyntax
+	}
+	return string(data[:n]), nil
+}
+
+// parseInt parses the payload of an integer reply
+func (r *Reader) parseInt(payload string) (int64, error) {
+	n, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q", payload)
+	}
+	return n, nil
+}
+
+// parseLength parses the length of a bulk string or aggregate; -1 means null
+func (r *Reader) parseLength(payload string) (int64, error) {
+	n, err := r.parseInt(payload)
+	if err != nil || n < -1 {
+		return 0, fmt.Errorf("invalid length %q", payload)
+	}
+	return n, nil
+}
+
+// readLine reads a line ending in CRLF and strips the terminator
+func (r *Reader) readLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", ErrInvalidSyntax
+	}
+	return line[:len(line)-2], nil
+}
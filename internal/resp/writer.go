@@ -3,91 +3,138 @@ package resp
 import (
 	"bufio"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
+	"github.com/hardikphalet/go-redis/internal/errs"
 	"github.com/hardikphalet/go-redis/internal/types"
 )
 
+// Protocol versions a connection can speak
+const (
+	RESP2 = 2
+	RESP3 = 3
+)
+
 // SimpleString represents a RESP Simple String that should be written with a + prefix
 type SimpleString string
 
 type Writer struct {
 	writer *bufio.Writer
+	proto  int
+	// scratch is reused to format numbers without allocating
+	scratch []byte
 }
 
-// NewWriter creates a new RESP Writer
+// NewWriter creates a new RESP Writer speaking RESP2. Replies are buffered;
+// call Flush to send them, which lets pipelined replies go out together.
 func NewWriter(writer *bufio.Writer) *Writer {
-	return &Writer{writer: writer}
+	return &Writer{writer: writer, proto: RESP2}
+}
+
+// SetProtocol switches the writer between RESP2 and RESP3
+func (w *Writer) SetProtocol(proto int) {
+	w.proto = proto
+}
+
+// Protocol returns the protocol version the writer speaks
+func (w *Writer) Protocol() int {
+	return w.proto
+}
+
+// Flush sends all buffered replies to the underlying connection
+func (w *Writer) Flush() error {
+	return w.writer.Flush()
+}
+
+// Buffered returns the number of bytes waiting to be flushed
+func (w *Writer) Buffered() int {
+	return w.writer.Buffered()
+}
+
+// writeHeader writes a type prefix followed by a number and CRLF, the
+// framing shared by integers, lengths and aggregate headers
+func (w *Writer) writeHeader(prefix byte, n int64) error {
+	w.scratch = append(w.scratch[:0], prefix)
+	w.scratch = strconv.AppendInt(w.scratch, n, 10)
+	w.scratch = append(w.scratch, '\r', '\n')
+	_, err := w.writer.Write(w.scratch)
+	return err
+}
+
+// writeLine writes a type prefix followed by s and CRLF
+func (w *Writer) writeLine(prefix byte, s string) error {
+	w.writer.WriteByte(prefix)
+	w.writer.WriteString(s)
+	_, err := w.writer.WriteString("\r\n")
+	return err
 }
 
 // WriteString writes a RESP Simple String ("+OK\r\n")
 func (w *Writer) WriteString(s string) error {
-	_, err := fmt.Fprintf(w.writer, "+%s\r\n", s)
-	if err != nil {
-		return err
-	}
-	return w.writer.Flush()
+	return w.writeLine('+', s)
 }
 
-// WriteError writes a RESP Error ("-Error message\r\n")
+// errorNewlines replaces the characters that can't appear in an error reply
+var errorNewlines = strings.NewReplacer("\r", " ", "\n", " ")
+
+// WriteError writes a RESP Error ("-ERR message\r\n"). An *errs.Error is
+// written verbatim with its own prefix, such as WRONGTYPE; any other error is
+// reported as a generic ERR. Newlines would end the reply early, so they are
+// replaced with spaces as Redis does.
 func (w *Writer) WriteError(err error) error {
-	_, err2 := fmt.Fprintf(w.writer, "-ERR %s\r\n", err.Error())
-	if err2 != nil {
-		return err2
+	msg := err.Error()
+	if _, ok := err.(*errs.Error); !ok {
+		msg = errs.PrefixErr + " " + msg
 	}
-	return w.writer.Flush()
+	return w.writeLine('-', errorNewlines.Replace(msg))
 }
 
 // WriteInteger writes a RESP Integer (":1000\r\n")
 func (w *Writer) WriteInteger(i int64) error {
-	_, err := fmt.Fprintf(w.writer, ":%d\r\n", i)
-	if err != nil {
-		return err
-	}
-	return w.writer.Flush()
+	return w.writeHeader(':', i)
 }
 
 // WriteBulkString writes a RESP Bulk String ("$5\r\nhello\r\n")
 func (w *Writer) WriteBulkString(s string) error {
-	if s == "" {
-		// Empty string is encoded as "$0\r\n\r\n"
-		_, err := fmt.Fprintf(w.writer, "$0\r\n\r\n")
-		if err != nil {
-			return err
-		}
-		return w.writer.Flush()
-	}
-
-	// Write the length prefix
-	_, err := fmt.Fprintf(w.writer, "$%d\r\n%s\r\n", len(s), s)
-	if err != nil {
+	// Write the length prefix; an empty string is encoded as "$0\r\n\r\n"
+	if err := w.writeHeader('$', int64(len(s))); err != nil {
 		return err
 	}
-	return w.writer.Flush()
+	w.writer.WriteString(s)
+	_, err := w.writer.WriteString("\r\n")
+	return err
 }
 
-// WriteNull writes a RESP Null value ("$-1\r\n")
+// WriteNull writes a RESP Null value ("$-1\r\n", or "_\r\n" in RESP3)
 func (w *Writer) WriteNull() error {
-	_, err := fmt.Fprintf(w.writer, "$-1\r\n")
-	if err != nil {
-		return err
+	null := "$-1\r\n"
+	if w.proto == RESP3 {
+		null = "_\r\n"
 	}
-	return w.writer.Flush()
+	_, err := w.writer.WriteString(null)
+	return err
+}
+
+// WriteNullArray writes a RESP Null Array ("*-1\r\n", or "_\r\n" in RESP3)
+func (w *Writer) WriteNullArray() error {
+	if w.proto == RESP3 {
+		return w.WriteNull()
+	}
+	_, err := w.writer.WriteString("*-1\r\n")
+	return err
 }
 
 // WriteArray writes a RESP Array ("*2\r\n$5\r\nhello\r\n$5\r\nworld\r\n")
 func (w *Writer) WriteArray(arr []string) error {
 	if arr == nil {
 		// Null array is encoded as "*-1\r\n"
-		_, err := fmt.Fprintf(w.writer, "*-1\r\n")
-		if err != nil {
-			return err
-		}
-		return w.writer.Flush()
+		return w.WriteNullArray()
 	}
 
 	// Write array length
-	_, err := fmt.Fprintf(w.writer, "*%d\r\n", len(arr))
-	if err != nil {
+	if err := w.writeHeader('*', int64(len(arr))); err != nil {
 		return err
 	}
 
@@ -99,17 +146,52 @@ func (w *Writer) WriteArray(arr []string) error {
 		}
 	}
 
-	return w.writer.Flush()
+	return nil
 }
 
 func (w *Writer) WriteArrayInterface(arr []interface{}) error {
 	if arr == nil {
-		_, err := fmt.Fprintf(w.writer, "*-1\r\n")
+		return w.WriteNullArray()
+	}
+	return w.writeAggregate('*', arr)
+}
+
+// WriteSet writes a RESP3 Set ("~2\r\n..."), or an array in RESP2
+func (w *Writer) WriteSet(arr []interface{}) error {
+	if w.proto != RESP3 {
+		return w.WriteArrayInterface(arr)
+	}
+	return w.writeAggregate('~', arr)
+}
+
+// WritePush writes a RESP3 Push message (">3\r\n..."), or an array in RESP2
+func (w *Writer) WritePush(arr []interface{}) error {
+	if w.proto != RESP3 {
+		return w.WriteArrayInterface(arr)
+	}
+	return w.writeAggregate('>', arr)
+}
+
+// WritePairs writes a flat list of alternating elements and values as an
+// array of [element, value] arrays in RESP3, or as it is in RESP2
+func (w *Writer) WritePairs(arr []interface{}) error {
+	if w.proto != RESP3 {
+		return w.WriteArrayInterface(arr)
+	}
+	if err := w.writeHeader('*', int64(len(arr)/2)); err != nil {
 		return err
 	}
+	for i := 0; i+1 < len(arr); i += 2 {
+		if err := w.writeAggregate('*', arr[i:i+2]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	_, err := fmt.Fprintf(w.writer, "*%d\r\n", len(arr))
-	if err != nil {
+// writeAggregate writes an aggregate type header followed by its elements
+func (w *Writer) writeAggregate(prefix byte, arr []interface{}) error {
+	if err := w.writeHeader(prefix, int64(len(arr))); err != nil {
 		return err
 	}
 
@@ -120,23 +202,94 @@ func (w *Writer) WriteArrayInterface(arr []interface{}) error {
 		}
 	}
 
-	return w.writer.Flush()
+	return nil
 }
 
-// WriteMap writes a RESP Map as an array with alternating keys and values
+// WriteMap writes a RESP3 Map ("%1\r\n..."). RESP2 has no map type, so the
+// map is written as an array with alternating keys and values.
 func (w *Writer) WriteMap(m map[string]interface{}) error {
 	if m == nil {
 		return w.WriteNull()
 	}
 
-	// Maps are encoded as arrays with alternating keys and values
-	arr := make([]interface{}, 0, len(m)*2)
+	entries := make(types.Map, 0, len(m))
 	for k, v := range m {
-		arr = append(arr, k, v)
+		entries = append(entries, types.MapEntry{Key: k, Value: v})
+	}
+	return w.WriteOrderedMap(entries)
+}
+
+// WriteOrderedMap writes a types.Map, preserving the order of its entries
+func (w *Writer) WriteOrderedMap(m types.Map) error {
+	if w.proto == RESP3 {
+		if err := w.writeHeader('%', int64(len(m))); err != nil {
+			return err
+		}
+		for _, e := range m {
+			if err := w.WriteInterface(e.Key); err != nil {
+				return err
+			}
+			if err := w.WriteInterface(e.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	arr := make([]interface{}, 0, len(m)*2)
+	for _, e := range m {
+		arr = append(arr, e.Key, e.Value)
 	}
 	return w.WriteArrayInterface(arr)
 }
 
+// WriteDouble writes a RESP3 Double (",1.5\r\n"), or a bulk string in RESP2
+func (w *Writer) WriteDouble(f float64) error {
+	if w.proto != RESP3 {
+		return w.WriteBulkString(FormatFloat(f))
+	}
+	return w.writeLine(',', FormatFloat(f))
+}
+
+// WriteBoolean writes a RESP3 Boolean ("#t\r\n"), or the integer 1 or 0 in RESP2
+func (w *Writer) WriteBoolean(b bool) error {
+	if w.proto != RESP3 {
+		if b {
+			return w.WriteInteger(1)
+		}
+		return w.WriteInteger(0)
+	}
+
+	if b {
+		return w.writeLine('#', "t")
+	}
+	return w.writeLine('#', "f")
+}
+
+// WriteBigNumber writes a RESP3 Big Number ("(12345\r\n"), or a bulk string in RESP2
+func (w *Writer) WriteBigNumber(n string) error {
+	if w.proto != RESP3 {
+		return w.WriteBulkString(n)
+	}
+	return w.writeLine('(', n)
+}
+
+// WriteVerbatimString writes a RESP3 Verbatim String ("=8\r\ntxt:text\r\n"),
+// or a bulk string in RESP2
+func (w *Writer) WriteVerbatimString(format, s string) error {
+	if w.proto != RESP3 {
+		return w.WriteBulkString(s)
+	}
+	if err := w.writeHeader('=', int64(len(format)+1+len(s))); err != nil {
+		return err
+	}
+	w.writer.WriteString(format)
+	w.writer.WriteByte(':')
+	w.writer.WriteString(s)
+	_, err := w.writer.WriteString("\r\n")
+	return err
+}
+
 // WriteInterface writes any interface{} value in the appropriate RESP format
 func (w *Writer) WriteInterface(v interface{}) error {
 	if v == nil {
@@ -154,14 +307,52 @@ func (w *Writer) WriteInterface(v interface{}) error {
 		return w.WriteInteger(int64(val))
 	case int64:
 		return w.WriteInteger(val)
+	case float64:
+		return w.WriteDouble(val)
+	case bool:
+		return w.WriteBoolean(val)
 	case error:
 		return w.WriteError(val)
 	case []interface{}:
 		return w.WriteArrayInterface(val)
 	case map[string]interface{}:
 		return w.WriteMap(val)
+	case types.Map:
+		return w.WriteOrderedMap(val)
+	case types.Set:
+		if val == nil {
+			val = types.Set{}
+		}
+		return w.WriteSet([]interface{}(val))
+	case types.Pairs:
+		return w.WritePairs([]interface{}(val))
+	case types.Push:
+		return w.WritePush([]interface{}(val))
+	case types.BigNumber:
+		return w.WriteBigNumber(string(val))
+	case types.VerbatimString:
+		return w.WriteVerbatimString(val.Format, val.Text)
 	default:
 		// Convert anything else to string
 		return w.WriteBulkString(fmt.Sprintf("%v", v))
 	}
 }
+
+// FormatFloat formats a float the way Redis replies with doubles: the
+// shortest representation that round-trips, with inf, -inf and nan spelled
+// out
+func FormatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	if abs := math.Abs(f); abs == 0 || (abs >= 1e-6 && abs < 1e21) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
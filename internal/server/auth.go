@@ -0,0 +1,84 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+)
+
+// defaultUser is the only user the server knows about
+const defaultUser = "default"
+
+// AuthCommand authenticates the connection as the default user
+type AuthCommand struct {
+	connOnly
+	Username string
+	Password string
+}
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "auth",
+		Arity: -2,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagNoAuth, commands.FlagAllowBusy,
+		},
+		ACLCategories: []string{"connection"},
+		Summary:       "Authenticates the connection.",
+		Since:         "1.0.0",
+		Group:         commands.GroupConnection,
+		Complexity:    "O(N) where N is the number of passwords defined for the user",
+		Arguments: []commands.Arg{
+			{Name: "username", Type: commands.ArgString, Optional: true, Since: "6.0.0"},
+			{Name: "password", Type: commands.ArgString},
+		},
+		Parse: parseAuth,
+	})
+}
+
+func parseAuth(args []string) (commands.Command, error) {
+	switch len(args) {
+	case 2:
+		return &AuthCommand{Password: args[1]}, nil
+	case 3:
+		return &AuthCommand{Username: args[1], Password: args[2]}, nil
+	default:
+		return nil, errs.ErrSyntax
+	}
+}
+
+func (c *AuthCommand) executeConn(h *Handler) (interface{}, error) {
+	if c.Username == "" && h.server.config.RequirePass == "" {
+		return nil, fmt.Errorf("AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+
+	username := c.Username
+	if username == "" {
+		username = defaultUser
+	}
+	if err := h.authenticate(username, c.Password); err != nil {
+		return nil, err
+	}
+	return commands.OK, nil
+}
+
+// authenticate checks the credentials against the default user and marks
+// the connection as authenticated when they match
+func (h *Handler) authenticate(username, password string) error {
+	if username != defaultUser {
+		return errs.ErrWrongPass
+	}
+
+	// Without requirepass the default user has no password and any password
+	// is accepted, as in Redis
+	if required := h.server.config.RequirePass; required != "" &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(required)) != 1 {
+		return errs.ErrWrongPass
+	}
+
+	h.setAuthenticated(true)
+	return nil
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+)
+
+// blockTimeoutCheck is how often a blocked client's timeout is checked
+// against the server's clock, which DEBUG may have moved or frozen
+const blockTimeoutCheck = 100 * time.Millisecond
+
+// errUnblocked is the reply of a client unblocked by CLIENT UNBLOCK ERROR
+var errUnblocked = errs.New(errs.PrefixUnblocked, "client unblocked via CLIENT UNBLOCK")
+
+// blockedClient is a connection waiting in a blocking command
+type blockedClient struct {
+	h       *Handler
+	command commands.BlockingCommand
+	// result receives the reply once the client is served or unblocked. It
+	// is buffered, so whoever ends the wait never waits on the client.
+	result chan blockResult
+}
+
+type blockResult struct {
+	reply interface{}
+	err   error
+}
+
+// blocking keeps track of the clients blocked on keys. A client that finds
+// nothing to serve joins a queue per key; once a command changes one of
+// those keys, the clients are served in the order they blocked, each by
+// running its command again on its behalf.
+//
+// Locks are taken in the order keyspaceMu, mu, the store's lock, readyMu.
+// The store's notifier runs under the store's lock and so only takes
+// readyMu.
+type blocking struct {
+	mu sync.Mutex
+	// queues maps each key to the clients blocked on it, oldest first
+	queues map[string][]*blockedClient
+	// clients maps the IDs of blocked connections to their blocked client
+	clients map[int64]*blockedClient
+
+	readyMu sync.Mutex
+	// keys counts the clients blocked on each key
+	keys map[string]int
+	// ready lists the keys with blocked clients changed since they were
+	// last served, in the order they changed
+	ready   []string
+	isReady map[string]bool
+}
+
+func newBlocking() *blocking {
+	return &blocking{
+		queues:  make(map[string][]*blockedClient),
+		clients: make(map[int64]*blockedClient),
+		keys:    make(map[string]int),
+		isReady: make(map[string]bool),
+	}
+}
+
+// add queues c on each of its keys. The caller must hold mu.
+func (b *blocking) add(c *blockedClient) {
+	b.clients[c.h.id] = c
+	b.readyMu.Lock()
+	defer b.readyMu.Unlock()
+	for _, key := range c.command.BlockKeys() {
+		if slices.Contains(b.queues[key], c) {
+			continue
+		}
+		b.queues[key] = append(b.queues[key], c)
+		b.keys[key]++
+	}
+}
+
+// remove takes c out of the queues, and reports whether it was still
+// blocked. The caller must hold mu.
+func (b *blocking) remove(c *blockedClient) bool {
+	if b.clients[c.h.id] != c {
+		return false
+	}
+	delete(b.clients, c.h.id)
+	b.readyMu.Lock()
+	defer b.readyMu.Unlock()
+	for _, key := range c.command.BlockKeys() {
+		queue := b.queues[key]
+		i := slices.Index(queue, c)
+		if i < 0 {
+			continue
+		}
+		if len(queue) == 1 {
+			delete(b.queues, key)
+		} else {
+			b.queues[key] = slices.Delete(queue, i, i+1)
+		}
+		if b.keys[key]--; b.keys[key] == 0 {
+			delete(b.keys, key)
+		}
+	}
+	return true
+}
+
+// signalReady marks key as changed if clients are blocked on it. It is
+// called by the store's notifier.
+func (b *blocking) signalReady(key string) {
+	b.readyMu.Lock()
+	defer b.readyMu.Unlock()
+	if b.keys[key] > 0 && !b.isReady[key] {
+		b.isReady[key] = true
+		b.ready = append(b.ready, key)
+	}
+}
+
+// takeReady returns the keys marked as changed and clears them
+func (b *blocking) takeReady() []string {
+	b.readyMu.Lock()
+	defer b.readyMu.Unlock()
+	keys := b.ready
+	b.ready = nil
+	clear(b.isReady)
+	return keys
+}
+
+// serveBlocked serves the clients blocked on the keys changed by the last
+// command, once it is done. Each client's command runs again on its behalf,
+// under the keyspace lock so nothing runs in between, and the client stays
+// blocked, keeping its place, if it still finds nothing to serve. Serving a
+// client can make more keys ready, as BLMOVE pushes to its destination.
+func (s *Server) serveBlocked() {
+	b := s.blocking
+	b.readyMu.Lock()
+	pending := len(b.ready) > 0
+	b.readyMu.Unlock()
+	if !pending {
+		return
+	}
+
+	s.keyspaceMu.RLock()
+	defer s.keyspaceMu.RUnlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for keys := b.takeReady(); len(keys) > 0; keys = b.takeReady() {
+		for _, key := range keys {
+			for _, c := range slices.Clone(b.queues[key]) {
+				reply, err := c.command.Execute(s.store)
+				if reply == nil && err == nil {
+					continue
+				}
+				b.remove(c)
+				c.result <- blockResult{reply: reply, err: err}
+			}
+		}
+	}
+}
+
+// unblock ends the wait of the client with the given ID as if it timed out,
+// or with err if it isn't nil. It reports whether the client was blocked.
+func (s *Server) unblock(id int64, err error) bool {
+	b := s.blocking
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.clients[id]
+	if !ok {
+		return false
+	}
+	b.remove(c)
+	result := blockResult{reply: c.command.TimeoutReply()}
+	if err != nil {
+		result = blockResult{err: err}
+	}
+	c.result <- result
+	return true
+}
+
+// block runs a blocking command, and if it finds nothing to serve blocks the
+// connection on its keys, returning the blockedClient to wait on. The
+// caller must hold the keyspace lock.
+func (h *Handler) block(command commands.BlockingCommand) (interface{}, error) {
+	b := h.server.blocking
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Trying under mu means no change to the keys can be missed between
+	// finding nothing and joining the queues
+	reply, err := command.Execute(h.store)
+	if reply != nil || err != nil {
+		return reply, err
+	}
+	c := &blockedClient{h: h, command: command, result: make(chan blockResult, 1)}
+	b.add(c)
+	return c, nil
+}
+
+// wait waits until the blocked client is served or unblocked, its timeout
+// elapses or the connection is closed, and returns its reply. The timeout
+// runs on the server's clock, as key expiry does, so DEBUG FREEZE-TIME and
+// ADVANCE-TIME apply to it. The clock can be moved at any time, so it is
+// looked at again at least every blockTimeoutCheck.
+func (h *Handler) wait(c *blockedClient) (interface{}, error) {
+	var deadline time.Time
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	if d := c.command.BlockTimeout(); d > 0 {
+		deadline = h.server.clock.Now().Add(d)
+		timer = time.NewTimer(min(d, blockTimeoutCheck))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	closed, stopWatching := h.watchClose()
+	defer stopWatching()
+
+wait:
+	for {
+		select {
+		case r := <-c.result:
+			return r.reply, r.err
+		case <-timeout:
+			if left := deadline.Sub(h.server.clock.Now()); left > 0 {
+				timer.Reset(min(left, blockTimeoutCheck))
+				continue
+			}
+			break wait
+		case <-closed:
+			break wait
+		case <-h.server.quit:
+			break wait
+		}
+	}
+
+	b := h.server.blocking
+	b.mu.Lock()
+	blocked := b.remove(c)
+	b.mu.Unlock()
+	if blocked {
+		return c.command.TimeoutReply(), nil
+	}
+	// Served or unblocked just as the wait ended
+	r := <-c.result
+	return r.reply, r.err
+}
+
+// watchClose reads ahead on the connection while it is blocked, so a client
+// going away is noticed and leaves the queues rather than being served.
+// Commands pipelined after the blocking one end the watch, as they are left
+// in the read buffer. stop ends the read, and must be called before the
+// connection is read from again.
+func (h *Handler) watchClose() (closed <-chan struct{}, stop func()) {
+	gone := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := h.reader.Peek(1); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			close(gone)
+		}
+	}()
+	return gone, func() {
+		// Peek clears the deadline error from the reader when it returns
+		h.conn.SetReadDeadline(time.Now())
+		<-done
+		h.conn.SetReadDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/store"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// ClientHelpCommand describes the CLIENT subcommands
+type ClientHelpCommand struct{ connOnly }
+
+// ClientIDCommand returns the connection's ID
+type ClientIDCommand struct{ connOnly }
+
+// ClientUnblockCommand ends the wait of a connection blocked by a blocking
+// command, as if it timed out or, with Error, with an error
+type ClientUnblockCommand struct {
+	connOnly
+	ID    int64
+	Error bool
+}
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:       "client",
+		Arity:      -2,
+		Summary:    "A container for client connection commands.",
+		Since:      "2.4.0",
+		Group:      commands.GroupConnection,
+		Complexity: "Depends on subcommand.",
+		Subcommands: []*commands.CommandSpec{
+			{
+				Name:          "help",
+				Arity:         2,
+				Flags:         []string{commands.FlagLoading, commands.FlagStale},
+				ACLCategories: []string{"connection"},
+				Summary:       "Returns helpful text about the different subcommands.",
+				Since:         "5.0.0",
+				Group:         commands.GroupConnection,
+				Complexity:    "O(1)",
+				Parse: func(args []string) (commands.Command, error) {
+					return &ClientHelpCommand{}, nil
+				},
+			},
+			{
+				Name:          "id",
+				Arity:         2,
+				Flags:         []string{commands.FlagNoScript, commands.FlagLoading, commands.FlagStale},
+				ACLCategories: []string{"connection"},
+				Summary:       "Returns the unique client ID of the connection.",
+				Since:         "5.0.0",
+				Group:         commands.GroupConnection,
+				Complexity:    "O(1)",
+				Parse: func(args []string) (commands.Command, error) {
+					return &ClientIDCommand{}, nil
+				},
+			},
+			{
+				Name:  "unblock",
+				Arity: -3,
+				Flags: []string{
+					commands.FlagAdmin, commands.FlagNoScript, commands.FlagLoading,
+					commands.FlagStale,
+				},
+				ACLCategories: []string{"connection"},
+				Summary:       "Unblocks a client blocked by a blocking command from a different connection.",
+				Since:         "5.0.0",
+				Group:         commands.GroupConnection,
+				Complexity:    "O(log N) where N is the number of client connections",
+				Arguments: []commands.Arg{
+					{Name: "client-id", Type: commands.ArgInteger},
+					{Name: "unblock-type", Type: commands.ArgOneOf, Optional: true, Args: []commands.Arg{
+						{Name: "timeout", Type: commands.ArgPureToken, Token: "TIMEOUT"},
+						{Name: "error", Type: commands.ArgPureToken, Token: "ERROR"},
+					}},
+				},
+				Parse: parseClientUnblock,
+			},
+		},
+	})
+}
+
+func parseClientUnblock(args []string) (commands.Command, error) {
+	if len(args) > 4 {
+		return nil, errs.ErrSyntax
+	}
+	id, ok := store.ParseInt(args[2])
+	if !ok {
+		return nil, errs.ErrNotInteger
+	}
+	cmd := &ClientUnblockCommand{ID: id}
+	if len(args) == 4 {
+		switch strings.ToUpper(args[3]) {
+		case "TIMEOUT":
+		case "ERROR":
+			cmd.Error = true
+		default:
+			return nil, fmt.Errorf("CLIENT UNBLOCK reason should be TIMEOUT or ERROR")
+		}
+	}
+	return cmd, nil
+}
+
+func (c *ClientHelpCommand) executeConn(h *Handler) (interface{}, error) {
+	lines := []string{
+		"CLIENT <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+		"ID",
+		"    Return the ID of the current connection.",
+		"UNBLOCK <clientid> [TIMEOUT|ERROR]",
+		"    Unblock the specified blocked client.",
+		"HELP",
+		"    Print this help.",
+	}
+
+	result := make([]interface{}, len(lines))
+	for i, line := range lines {
+		result[i] = types.SimpleString(line)
+	}
+	return result, nil
+}
+
+func (c *ClientIDCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.id, nil
+}
+
+// executeConn replies with 1 if the client was blocked, and 0 otherwise
+func (c *ClientUnblockCommand) executeConn(h *Handler) (interface{}, error) {
+	var err error
+	if c.Error {
+		err = errUnblocked
+	}
+	if h.server.unblock(c.ID, err) {
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/clock"
+	"github.com/hardikphalet/go-redis/internal/notify"
+	"github.com/hardikphalet/go-redis/internal/resp"
+)
+
+// Config holds the server settings
+type Config struct {
+	// Addr is the TCP address to listen on
+	Addr string
+	// RequirePass is the password of the default user. When empty, clients
+	// are authenticated as soon as they connect.
+	RequirePass string
+	// ProtoMaxBulkLen is the largest bulk string a request may contain
+	// (proto-max-bulk-len)
+	ProtoMaxBulkLen int64
+	// ClientQueryBufferLimit is the largest request a client may send before
+	// it is disconnected (client-query-buffer-limit)
+	ClientQueryBufferLimit int64
+	// PubSubBufferLimit is how many bytes of messages may wait for a
+	// subscriber before it is disconnected, the hard limit of
+	// client-output-buffer-limit pubsub. 0 means no limit.
+	PubSubBufferLimit int64
+	// NotifyKeyspaceEvents selects the keyspace notifications published to
+	// pub/sub (notify-keyspace-events). None are by default.
+	NotifyKeyspaceEvents notify.Class
+	// Hz is how many times a second background tasks such as the active
+	// expire cycle run, between 1 and 500
+	Hz int
+	// EnableDebugCommand allows DEBUG: "no", "yes", or "local" for
+	// connections from the loopback interface only
+	EnableDebugCommand string
+	// Clock is the clock keys expire and blocking commands time out by.
+	// DEBUG can freeze and advance the server's view of it.
+	Clock clock.Clock
+}
+
+// DefaultConfig returns the configuration Redis ships with
+func DefaultConfig() Config {
+	limits := resp.DefaultLimits()
+	return Config{
+		Addr:                   ":6379",
+		ProtoMaxBulkLen:        limits.MaxBulkLen,
+		ClientQueryBufferLimit: limits.QueryBufferLimit,
+		PubSubBufferLimit:      32 * 1024 * 1024,
+		Hz:                     10,
+		EnableDebugCommand:     "no",
+		Clock:                  clock.System,
+	}
+}
+
+// limits returns the protocol limits derived from the configuration
+func (c Config) limits() resp.Limits {
+	limits := resp.DefaultLimits()
+	limits.MaxBulkLen = c.ProtoMaxBulkLen
+	limits.QueryBufferLimit = c.ClientQueryBufferLimit
+	return limits
+}
+
+// ParseMemory parses a memory amount the way Redis config files do: a plain
+// number of bytes, or a number followed by k, kb, m, mb, g or gb, where the
+// "b" suffixes are powers of 1024 and the bare letters powers of 1000
+func ParseMemory(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		mul    int64
+	}{
+		{"gb", 1024 * 1024 * 1024},
+		{"mb", 1024 * 1024},
+		{"kb", 1024},
+		{"g", 1000 * 1000 * 1000},
+		{"m", 1000 * 1000},
+		{"k", 1000},
+		{"b", 1},
+	}
+
+	lower := strings.ToLower(s)
+	mul := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(lower, u.suffix) {
+			lower = strings.TrimSuffix(lower, u.suffix)
+			mul = u.mul
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(lower, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid memory amount %q", s)
+	}
+	return n * mul, nil
+}
@@ -0,0 +1,33 @@
+package server
+
+import "time"
+
+const (
+	minHz = 1
+	maxHz = 500
+
+	// activeExpireCyclePerc is the share of each cron tick, in percent, the
+	// active expire cycle may take
+	activeExpireCyclePerc = 25
+)
+
+// cron runs the background tasks hz times a second until the server stops
+func (s *Server) cron() {
+	defer s.wg.Done()
+
+	hz := min(max(s.config.Hz, minHz), maxHz)
+	ticker := time.NewTicker(time.Second / time.Duration(hz))
+	defer ticker.Stop()
+
+	expireTimeLimit := time.Second * activeExpireCyclePerc / 100 / time.Duration(hz)
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			if s.activeExpire.Load() {
+				s.store.ActiveExpireCycle(expireTimeLimit)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// DebugSetActiveExpireCommand turns the active expire cycle on or off
+type DebugSetActiveExpireCommand struct {
+	connOnly
+	Enabled bool
+}
+
+// DebugFreezeTimeCommand stops the server clock
+type DebugFreezeTimeCommand struct{ connOnly }
+
+// DebugUnfreezeTimeCommand lets the server clock run again
+type DebugUnfreezeTimeCommand struct{ connOnly }
+
+// DebugAdvanceTimeCommand moves the server clock
+type DebugAdvanceTimeCommand struct {
+	connOnly
+	By time.Duration
+}
+
+// DebugHelpCommand describes the DEBUG subcommands
+type DebugHelpCommand struct{ connOnly }
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "debug",
+		Arity: -2,
+		Flags: []string{
+			commands.FlagAdmin, commands.FlagNoScript, commands.FlagLoading,
+			commands.FlagStale, commands.FlagProtected,
+		},
+		Summary:    "A container for debugging commands.",
+		Since:      "1.0.0",
+		Group:      commands.GroupServer,
+		Complexity: "Depends on subcommand.",
+		Parse:      parseDebug,
+	})
+}
+
+// parseDebug parses the DEBUG subcommands. Unlike other containers, DEBUG
+// doesn't list them in COMMAND, as in Redis.
+func parseDebug(args []string) (commands.Command, error) {
+	switch sub := strings.ToLower(args[1]); {
+	case sub == "help" && len(args) == 2:
+		return &DebugHelpCommand{}, nil
+	case sub == "set-active-expire" && len(args) == 3:
+		n, err := strconv.Atoi(args[2])
+		if err != nil {
+			return nil, errs.ErrNotInteger
+		}
+		return &DebugSetActiveExpireCommand{Enabled: n != 0}, nil
+	case sub == "freeze-time" && len(args) == 2:
+		return &DebugFreezeTimeCommand{}, nil
+	case sub == "unfreeze-time" && len(args) == 2:
+		return &DebugUnfreezeTimeCommand{}, nil
+	case sub == "advance-time" && len(args) == 3:
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return nil, errs.ErrNotInteger
+		}
+		return &DebugAdvanceTimeCommand{By: time.Duration(ms) * time.Millisecond}, nil
+	default:
+		return nil, fmt.Errorf("unknown subcommand or wrong number of arguments for '%.128s'. Try DEBUG HELP.", args[1])
+	}
+}
+
+// protectedAllowed reports whether enable-debug-command lets the connection
+// run protected commands
+func (h *Handler) protectedAllowed() bool {
+	switch h.server.config.EnableDebugCommand {
+	case "yes":
+		return true
+	case "local":
+		addr, ok := h.conn.RemoteAddr().(*net.TCPAddr)
+		return !ok || addr.IP.IsLoopback()
+	default:
+		return false
+	}
+}
+
+func (c *DebugSetActiveExpireCommand) executeConn(h *Handler) (interface{}, error) {
+	h.server.activeExpire.Store(c.Enabled)
+	return commands.OK, nil
+}
+
+func (c *DebugFreezeTimeCommand) executeConn(h *Handler) (interface{}, error) {
+	h.server.clock.Freeze()
+	return commands.OK, nil
+}
+
+func (c *DebugUnfreezeTimeCommand) executeConn(h *Handler) (interface{}, error) {
+	h.server.clock.Unfreeze()
+	return commands.OK, nil
+}
+
+func (c *DebugAdvanceTimeCommand) executeConn(h *Handler) (interface{}, error) {
+	h.server.clock.Advance(c.By)
+	return commands.OK, nil
+}
+
+func (c *DebugHelpCommand) executeConn(h *Handler) (interface{}, error) {
+	lines := []string{
+		"DEBUG <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+		"ADVANCE-TIME <milliseconds>",
+		"    Move the clock keys expire by forward, or back for a negative amount.",
+		"FREEZE-TIME",
+		"    Stop the clock keys expire by, until it is advanced or unfrozen.",
+		"SET-ACTIVE-EXPIRE <0|1>",
+		"    Setting it to 0 disables expiring keys in background when they are not",
+		"    accessed (otherwise the Redis behavior). Setting it to 1 reenables back the",
+		"    default.",
+		"UNFREEZE-TIME",
+		"    Let the clock keys expire by run again from where it was frozen.",
+		"HELP",
+		"    Print this help.",
+	}
+
+	result := make([]interface{}, len(lines))
+	for i, line := range lines {
+		result[i] = types.SimpleString(line)
+	}
+	return result, nil
+}
@@ -2,64 +2,271 @@ package server
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"net"
+	"sync"
 
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/pubsub"
 	"github.com/hardikphalet/go-redis/internal/resp"
 	"github.com/hardikphalet/go-redis/internal/store"
 )
 
+// connCommand is implemented by commands that act on the connection itself
+// rather than only on the keyspace, such as HELLO and AUTH
+type connCommand interface {
+	executeConn(h *Handler) (interface{}, error)
+}
+
+// connOnly satisfies commands.Command for connection commands. The handler
+// never calls it, since connection commands are dispatched to executeConn.
+type connOnly struct{}
+
+func (connOnly) Execute(store store.Store) (interface{}, error) {
+	return nil, fmt.Errorf("command can only be executed by a client connection")
+}
+
+// multiReply is several replies sent one after the other, as SUBSCRIBE sends
+// one per channel
+type multiReply []interface{}
+
+// ioBufferSize is the size of the connection read and write buffers, matching
+// Redis's PROTO_IOBUF_LEN
+const ioBufferSize = 16 * 1024
+
 type Handler struct {
 	conn       net.Conn
 	reader     *bufio.Reader
 	writer     *bufio.Writer
+	server     *Server
 	store      store.Store
 	parser     *resp.Parser
 	respWriter *resp.Writer
+
+	id            int64
+	name          string
+	authenticated bool
+
+	// tx holds the commands queued since MULTI, nil outside a transaction
+	tx *transaction
+	// watcher holds the keys watched for the next transaction
+	watcher *store.Watcher
+	// sub holds the connection's subscriptions, nil until it subscribes
+	sub *pubsub.Subscriber
+	// quit closes the connection once the current reply is sent
+	quit bool
+
+	// outMu serializes writes to the connection between the handler and
+	// the goroutine pushing published messages
+	outMu sync.Mutex
+	// done is closed when the handler returns
+	done chan struct{}
+}
+
+// flushingReader flushes pending replies before blocking on the connection
+// for more input. Replies to a pipelined burst are therefore sent together,
+// and never held back while the client is still sending a partial request.
+type flushingReader struct {
+	conn   net.Conn
+	writer *bufio.Writer
+	mu     *sync.Mutex
+}
+
+func (r *flushingReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	var err error
+	if r.writer.Buffered() > 0 {
+		err = r.writer.Flush()
+	}
+	r.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return r.conn.Read(p)
 }
 
-func NewHandler(conn net.Conn, store store.Store) *Handler {
-	reader := bufio.NewReader(conn)
-	writer := bufio.NewWriter(conn)
-	return &Handler{
-		conn:       conn,
-		reader:     reader,
-		writer:     writer,
-		store:      store,
-		parser:     resp.NewParser(reader),
-		respWriter: resp.NewWriter(writer),
+func NewHandler(conn net.Conn, server *Server) *Handler {
+	h := &Handler{
+		conn:    conn,
+		writer:  bufio.NewWriterSize(conn, ioBufferSize),
+		server:  server,
+		store:   server.store,
+		id:      server.nextClientID.Add(1),
+		watcher: store.NewWatcher(),
+		done:    make(chan struct{}),
 	}
+	h.reader = bufio.NewReaderSize(&flushingReader{conn: conn, writer: h.writer, mu: &h.outMu}, ioBufferSize)
+	h.parser = resp.NewParser(h.reader)
+	h.respWriter = resp.NewWriter(h.writer)
+	h.parser.SetLimits(server.config.limits())
+	h.setAuthenticated(server.config.RequirePass == "")
+	return h
+}
+
+// setAuthenticated records the authentication state, which also relaxes the
+// protocol limits applied to the client
+func (h *Handler) setAuthenticated(authenticated bool) {
+	h.authenticated = authenticated
+	h.parser.SetAuthenticated(authenticated)
 }
 
 func (h *Handler) Handle() error {
+	defer h.close()
+
 	for {
-		// Parse the incoming command using RESP protocol
-		command, err := h.parser.Parse()
+		// Read the next request frame using RESP protocol
+		args, err := h.parser.Parse()
 		if err != nil {
 			if err.Error() == "EOF" {
 				// Client closed connection - this is normal
 				return nil
 			}
+			h.outMu.Lock()
+			var protoErr *resp.ProtocolError
+			if errors.As(err, &protoErr) {
+				// Tell the client what went wrong before closing the connection
+				h.writeError(protoErr)
+			}
+			h.respWriter.Flush()
+			h.outMu.Unlock()
 			return fmt.Errorf("error parsing command: %w", err)
 		}
 
-		// Execute the command
-		response, err := command.Execute(h.store)
-		if err != nil {
-			if err := h.writeError(err); err != nil {
-				return fmt.Errorf("error writing error response: %w", err)
-			}
-			continue
+		if err := h.process(args); err != nil {
+			return err
+		}
+		if h.quit {
+			h.outMu.Lock()
+			defer h.outMu.Unlock()
+			return h.respWriter.Flush()
 		}
 
-		// Write the response using RESP protocol
-		if err := h.writeResponse(response); err != nil {
-			return fmt.Errorf("error writing response: %w", err)
+		// Replies are buffered while the client has more pipelined commands
+		// waiting in the read buffer; the reader flushes them once it needs
+		// more input
+	}
+}
+
+// process executes a command and writes its reply. Published messages wait
+// meanwhile, so they can't slip in between a command's effect and its reply,
+// such as a message on a channel just subscribed to and the SUBSCRIBE reply.
+// They are let through while the connection is blocked, as in Redis.
+func (h *Handler) process(args []string) error {
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+
+	// Execute the command
+	response, err := h.dispatch(args)
+	h.server.serveBlocked()
+	if c, ok := response.(*blockedClient); ok {
+		h.outMu.Unlock()
+		response, err = h.wait(c)
+		h.outMu.Lock()
+	}
+	if err != nil {
+		if err := h.writeError(err); err != nil {
+			return fmt.Errorf("error writing error response: %w", err)
 		}
+	} else if err := h.writeResponse(response); err != nil {
+		// Write the response using RESP protocol
+		return fmt.Errorf("error writing response: %w", err)
+	}
+	return nil
+}
+
+// close releases what the connection holds in the server once it ends
+func (h *Handler) close() {
+	close(h.done)
+	h.store.Unwatch(h.watcher)
+	if h.sub != nil {
+		h.server.pubsub.UnsubscribeAll(h.sub)
+	}
+}
+
+// dispatch looks the command up in the command table, checks that the
+// connection may run it, and executes it or queues it inside MULTI
+func (h *Handler) dispatch(args []string) (interface{}, error) {
+	spec, err := commands.Resolve(args)
+	if err != nil {
+		h.abortTransaction()
+		return nil, err
+	}
+
+	if !h.authenticated && !spec.HasFlag(commands.FlagNoAuth) {
+		h.abortTransaction()
+		return nil, errs.ErrNoAuth
+	}
+
+	if spec.HasFlag(commands.FlagProtected) && !h.protectedAllowed() {
+		h.abortTransaction()
+		return nil, fmt.Errorf("DEBUG command not allowed. If the enable-debug-command option is set to \"local\", you can run it from a local connection, otherwise you need to set this option in the configuration file, and then restart the server.")
+	}
+
+	if h.subscribedRESP2() && !allowedWhileSubscribed(spec) {
+		h.abortTransaction()
+		return nil, fmt.Errorf("Can't execute '%s': only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT / RESET are allowed in this context", spec.Name)
+	}
+
+	if h.tx != nil && queueable(spec) {
+		h.tx.commands = append(h.tx.commands, queuedCommand{spec: spec, args: args})
+		return queued, nil
+	}
+
+	command, err := spec.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// A RESP2 subscriber can't tell a bulk reply from a message, so PING
+	// replies with an array
+	if ping, ok := command.(*commands.PingCommand); ok && h.subscribedRESP2() {
+		return []interface{}{"pong", ping.Message}, nil
+	}
+
+	// Keyspace commands share the keyspace lock, which EXEC takes
+	// exclusively to run a transaction without interleaving
+	if _, ok := command.(connCommand); !ok {
+		h.server.keyspaceMu.RLock()
+		defer h.server.keyspaceMu.RUnlock()
+	}
+	if bc, ok := command.(commands.BlockingCommand); ok {
+		return h.block(bc)
+	}
+	return h.run(command)
+}
+
+// run executes a parsed command. A blocking command doesn't block, as
+// inside MULTI: with nothing to serve it times out right away.
+func (h *Handler) run(command commands.Command) (interface{}, error) {
+	if cc, ok := command.(connCommand); ok {
+		return cc.executeConn(h)
+	}
+	reply, err := command.Execute(h.store)
+	if bc, ok := command.(commands.BlockingCommand); ok && reply == nil && err == nil {
+		return bc.TimeoutReply(), nil
+	}
+	return reply, err
+}
+
+// abortTransaction makes EXEC fail after a command was rejected while
+// queueing
+func (h *Handler) abortTransaction() {
+	if h.tx != nil {
+		h.tx.aborted = true
 	}
 }
 
 func (h *Handler) writeResponse(response interface{}) error {
+	if replies, ok := response.(multiReply); ok {
+		for _, reply := range replies {
+			if err := h.respWriter.WriteInterface(reply); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	return h.respWriter.WriteInterface(response)
 }
 
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// HelloCommand negotiates the protocol version, optionally authenticating
+// the connection and naming it at the same time
+type HelloCommand struct {
+	connOnly
+	ProtoVer   int // 0 keeps the current protocol
+	Auth       bool
+	Username   string
+	Password   string
+	ClientName string
+	SetName    bool
+}
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "hello",
+		Arity: -1,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagNoAuth, commands.FlagAllowBusy,
+		},
+		ACLCategories: []string{"connection"},
+		Summary:       "Handshakes with the Redis server.",
+		Since:         "6.0.0",
+		Group:         commands.GroupConnection,
+		Complexity:    "O(1)",
+		Arguments: []commands.Arg{{
+			Name:     "arguments",
+			Type:     commands.ArgBlock,
+			Optional: true,
+			Args: []commands.Arg{
+				{Name: "protover", Type: commands.ArgInteger},
+				{Name: "auth", Type: commands.ArgBlock, Token: "AUTH", Optional: true, Args: []commands.Arg{
+					{Name: "username", Type: commands.ArgString},
+					{Name: "password", Type: commands.ArgString},
+				}},
+				{Name: "clientname", Type: commands.ArgString, Token: "SETNAME", Optional: true},
+			},
+		}},
+		Parse: parseHello,
+	})
+}
+
+func parseHello(args []string) (commands.Command, error) {
+	cmd := &HelloCommand{}
+	if len(args) < 2 {
+		return cmd, nil
+	}
+
+	ver, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Protocol version is not an integer or out of range")
+	}
+	if ver < resp.RESP2 || ver > resp.RESP3 {
+		return nil, errs.New(errs.PrefixNoProto, "unsupported protocol version")
+	}
+	cmd.ProtoVer = int(ver)
+
+	for i := 2; i < len(args); i++ {
+		moreArgs := len(args) - 1 - i
+		switch {
+		case strings.EqualFold(args[i], "AUTH") && moreArgs >= 2:
+			cmd.Auth = true
+			cmd.Username = args[i+1]
+			cmd.Password = args[i+2]
+			i += 2
+		case strings.EqualFold(args[i], "SETNAME") && moreArgs >= 1:
+			cmd.SetName = true
+			cmd.ClientName = args[i+1]
+			i++
+		default:
+			return nil, fmt.Errorf("Syntax error in HELLO option '%s'", args[i])
+		}
+	}
+
+	return cmd, nil
+}
+
+func (c *HelloCommand) executeConn(h *Handler) (interface{}, error) {
+	if !h.authenticated && !c.Auth {
+		return nil, errs.New(errs.PrefixNoAuth, "HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+	}
+
+	if c.Auth {
+		if err := h.authenticate(c.Username, c.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.SetName {
+		if err := validateClientName(c.ClientName); err != nil {
+			return nil, err
+		}
+		h.name = c.ClientName
+	}
+
+	if c.ProtoVer != 0 {
+		h.respWriter.SetProtocol(c.ProtoVer)
+	}
+
+	return types.Map{
+		{Key: "server", Value: "redis"},
+		{Key: "version", Value: Version},
+		{Key: "proto", Value: h.respWriter.Protocol()},
+		{Key: "id", Value: h.id},
+		{Key: "mode", Value: "standalone"},
+		{Key: "role", Value: "master"},
+		{Key: "modules", Value: []interface{}{}},
+	}, nil
+}
+
+// validateClientName rejects names Redis would refuse in CLIENT SETNAME
+func validateClientName(name string) error {
+	for i := 0; i < len(name); i++ {
+		if name[i] < '!' || name[i] > '~' {
+			return fmt.Errorf("Client names cannot contain spaces, newlines or special characters.")
+		}
+	}
+	return nil
+}
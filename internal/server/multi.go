@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// queued is the reply to a command queued inside MULTI
+const queued = types.SimpleString("QUEUED")
+
+// transaction holds the commands a connection queued since MULTI
+type transaction struct {
+	commands []queuedCommand
+	// aborted is set when a command was rejected while queueing, which
+	// makes EXEC fail with EXECABORT
+	aborted bool
+}
+
+type queuedCommand struct {
+	spec *commands.CommandSpec
+	args []string
+}
+
+// MultiCommand starts a transaction
+type MultiCommand struct{ connOnly }
+
+// ExecCommand runs the queued commands atomically
+type ExecCommand struct{ connOnly }
+
+// DiscardCommand drops the queued commands
+type DiscardCommand struct{ connOnly }
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "multi",
+		Arity: 1,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagAllowBusy,
+		},
+		ACLCategories: []string{"transaction"},
+		Summary:       "Starts a transaction.",
+		Since:         "1.2.0",
+		Group:         commands.GroupTransactions,
+		Complexity:    "O(1)",
+		Parse: func(args []string) (commands.Command, error) {
+			return &MultiCommand{}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:  "exec",
+		Arity: 1,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagSkipSlowlog,
+		},
+		ACLCategories: []string{"transaction"},
+		Summary:       "Executes all commands in a transaction.",
+		Since:         "1.2.0",
+		Group:         commands.GroupTransactions,
+		Complexity:    "Depends on commands in the transaction",
+		Parse: func(args []string) (commands.Command, error) {
+			return &ExecCommand{}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:  "discard",
+		Arity: 1,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagAllowBusy,
+		},
+		ACLCategories: []string{"transaction"},
+		Summary:       "Discards a transaction.",
+		Since:         "2.0.0",
+		Group:         commands.GroupTransactions,
+		Complexity:    "O(N), when N is the number of queued commands",
+		Parse: func(args []string) (commands.Command, error) {
+			return &DiscardCommand{}, nil
+		},
+	})
+}
+
+// queueable reports whether a command is queued inside MULTI rather than
+// run right away. Only the commands controlling the transaction run.
+func queueable(spec *commands.CommandSpec) bool {
+	switch spec.Name {
+	case "multi", "exec", "discard", "watch", "quit", "reset":
+		return false
+	}
+	return true
+}
+
+func (c *MultiCommand) executeConn(h *Handler) (interface{}, error) {
+	if h.tx != nil {
+		return nil, fmt.Errorf("MULTI calls can not be nested")
+	}
+	h.tx = &transaction{}
+	return commands.OK, nil
+}
+
+// executeConn runs the queued commands while holding the keyspace lock
+// exclusively, so no other connection's command runs in between. Errors of
+// individual commands are part of the reply rather than aborting the rest,
+// as in Redis.
+func (c *ExecCommand) executeConn(h *Handler) (interface{}, error) {
+	tx := h.tx
+	if tx == nil {
+		return nil, fmt.Errorf("EXEC without MULTI")
+	}
+	h.tx = nil
+	if tx.aborted {
+		h.store.Unwatch(h.watcher)
+		return nil, errs.ErrExecAbort
+	}
+
+	h.server.keyspaceMu.Lock()
+	defer h.server.keyspaceMu.Unlock()
+
+	// A watched key changed since WATCH: the transaction doesn't run and
+	// the reply is a null array
+	modified := h.store.Modified(h.watcher)
+	h.store.Unwatch(h.watcher)
+	if modified {
+		return []interface{}(nil), nil
+	}
+
+	replies := make([]interface{}, len(tx.commands))
+	for i, qc := range tx.commands {
+		// Argument errors surface when the command runs rather than when
+		// it is queued, so they only fail that command, as in Redis
+		command, err := qc.spec.Parse(qc.args)
+		var reply interface{}
+		if err == nil {
+			reply, err = h.run(command)
+		}
+		if err != nil {
+			replies[i] = err
+			continue
+		}
+		// Several replies can't be spread in between the others, so they
+		// are nested instead
+		if r, ok := reply.(multiReply); ok {
+			reply = []interface{}(r)
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+func (c *DiscardCommand) executeConn(h *Handler) (interface{}, error) {
+	if h.tx == nil {
+		return nil, fmt.Errorf("DISCARD without MULTI")
+	}
+	h.tx = nil
+	h.store.Unwatch(h.watcher)
+	return commands.OK, nil
+}
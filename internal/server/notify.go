@@ -0,0 +1,29 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/hardikphalet/go-redis/internal/notify"
+)
+
+// notifyKeyspaceEvent wakes the clients blocked on lists and sorted sets,
+// and publishes a keyspace event if its class is enabled by
+// notify-keyspace-events: the event name to __keyspace@<db>__:<key> and the
+// key to __keyevent@<db>__:<event>. There is only database 0.
+func (s *Server) notifyKeyspaceEvent(class notify.Class, event, key string) {
+	// Clients blocked on the key are served once the command is done
+	if class == notify.List || class == notify.ZSet {
+		s.blocking.signalReady(key)
+	}
+
+	enabled := s.config.NotifyKeyspaceEvents
+	if enabled&class == 0 {
+		return
+	}
+	if enabled&notify.Keyspace != 0 {
+		s.pubsub.Publish(fmt.Sprintf("__keyspace@%d__:%s", 0, key), event)
+	}
+	if enabled&notify.Keyevent != 0 {
+		s.pubsub.Publish(fmt.Sprintf("__keyevent@%d__:%s", 0, event), key)
+	}
+}
@@ -0,0 +1,580 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/pubsub"
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// SubscribeCommand subscribes the connection to channels
+type SubscribeCommand struct {
+	connOnly
+	Channels []string
+}
+
+// UnsubscribeCommand unsubscribes the connection from channels, or from all
+// of them when Channels is empty
+type UnsubscribeCommand struct {
+	connOnly
+	Channels []string
+}
+
+// PSubscribeCommand subscribes the connection to channel patterns
+type PSubscribeCommand struct {
+	connOnly
+	Patterns []string
+}
+
+// PUnsubscribeCommand unsubscribes the connection from patterns, or from all
+// of them when Patterns is empty
+type PUnsubscribeCommand struct {
+	connOnly
+	Patterns []string
+}
+
+// PublishCommand posts a message to a channel
+type PublishCommand struct {
+	connOnly
+	Channel string
+	Message string
+}
+
+// SSubscribeCommand subscribes the connection to shard channels
+type SSubscribeCommand struct {
+	connOnly
+	Channels []string
+}
+
+// SUnsubscribeCommand unsubscribes the connection from shard channels, or
+// from all of them when Channels is empty
+type SUnsubscribeCommand struct {
+	connOnly
+	Channels []string
+}
+
+// SPublishCommand posts a message to a shard channel
+type SPublishCommand struct {
+	connOnly
+	Channel string
+	Message string
+}
+
+// PubSubChannelsCommand lists the active channels matching Pattern
+type PubSubChannelsCommand struct {
+	connOnly
+	Pattern string
+}
+
+// PubSubNumSubCommand counts the subscribers of each channel
+type PubSubNumSubCommand struct {
+	connOnly
+	Channels []string
+}
+
+// PubSubShardChannelsCommand lists the active shard channels matching
+// Pattern
+type PubSubShardChannelsCommand struct {
+	connOnly
+	Pattern string
+}
+
+// PubSubShardNumSubCommand counts the subscribers of each shard channel
+type PubSubShardNumSubCommand struct {
+	connOnly
+	Channels []string
+}
+
+// PubSubNumPatCommand counts the patterns subscribed to
+type PubSubNumPatCommand struct{ connOnly }
+
+// PubSubHelpCommand describes the PUBSUB subcommands
+type PubSubHelpCommand struct{ connOnly }
+
+func init() {
+	subscribeFlags := []string{
+		commands.FlagPubSub, commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+	}
+	introspectionFlags := []string{commands.FlagPubSub, commands.FlagLoading, commands.FlagStale}
+
+	commands.Register(&commands.CommandSpec{
+		Name:       "subscribe",
+		Arity:      -2,
+		Flags:      subscribeFlags,
+		Summary:    "Listens for messages published to channels.",
+		Since:      "2.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of channels to subscribe to.",
+		Arguments: []commands.Arg{
+			{Name: "channel", Type: commands.ArgString, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &SubscribeCommand{Channels: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:       "unsubscribe",
+		Arity:      -1,
+		Flags:      subscribeFlags,
+		Summary:    "Stops listening to messages posted to channels.",
+		Since:      "2.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of channels to unsubscribe.",
+		Arguments: []commands.Arg{
+			{Name: "channel", Type: commands.ArgString, Optional: true, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &UnsubscribeCommand{Channels: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:       "psubscribe",
+		Arity:      -2,
+		Flags:      subscribeFlags,
+		Summary:    "Listens for messages published to channels that match one or more patterns.",
+		Since:      "2.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of patterns to subscribe to.",
+		Arguments: []commands.Arg{
+			{Name: "pattern", Type: commands.ArgPattern, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &PSubscribeCommand{Patterns: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:       "punsubscribe",
+		Arity:      -1,
+		Flags:      subscribeFlags,
+		Summary:    "Stops listening to messages published to channels that match one or more patterns.",
+		Since:      "2.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of patterns to unsubscribe.",
+		Arguments: []commands.Arg{
+			{Name: "pattern", Type: commands.ArgPattern, Optional: true, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &PUnsubscribeCommand{Patterns: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:       "publish",
+		Arity:      3,
+		Flags:      []string{commands.FlagPubSub, commands.FlagLoading, commands.FlagStale, commands.FlagFast},
+		Summary:    "Posts a message to a channel.",
+		Since:      "2.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N+M) where N is the number of clients subscribed to the receiving channel and M is the total number of subscribed patterns (by any client).",
+		Arguments: []commands.Arg{
+			{Name: "channel", Type: commands.ArgString},
+			{Name: "message", Type: commands.ArgString},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &PublishCommand{Channel: args[1], Message: args[2]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:     "ssubscribe",
+		Arity:    -2,
+		Flags:    subscribeFlags,
+		FirstKey: 1,
+		LastKey:  -1,
+		Step:     1,
+		KeySpecs: []commands.KeySpec{
+			{Flags: []string{commands.KeyNotKey}, BeginIndex: 1, LastKey: -1, KeyStep: 1},
+		},
+		Summary:    "Listens for messages published to shard channels.",
+		Since:      "7.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of shard channels to subscribe to.",
+		Arguments: []commands.Arg{
+			{Name: "shardchannel", Type: commands.ArgKey, KeySpecIndex: 0, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &SSubscribeCommand{Channels: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:     "sunsubscribe",
+		Arity:    -1,
+		Flags:    subscribeFlags,
+		FirstKey: 1,
+		LastKey:  -1,
+		Step:     1,
+		KeySpecs: []commands.KeySpec{
+			{Flags: []string{commands.KeyNotKey}, BeginIndex: 1, LastKey: -1, KeyStep: 1},
+		},
+		Summary:    "Stops listening to messages posted to shard channels.",
+		Since:      "7.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of shard channels to unsubscribe.",
+		Arguments: []commands.Arg{
+			{Name: "shardchannel", Type: commands.ArgKey, KeySpecIndex: 0, Optional: true, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &SUnsubscribeCommand{Channels: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:  "spublish",
+		Arity: 3,
+		Flags: []string{
+			commands.FlagPubSub, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagMayReplicate,
+		},
+		FirstKey: 1,
+		LastKey:  1,
+		Step:     1,
+		KeySpecs: []commands.KeySpec{
+			{Flags: []string{commands.KeyNotKey}, BeginIndex: 1, KeyStep: 1},
+		},
+		Summary:    "Post a message to a shard channel",
+		Since:      "7.0.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "O(N) where N is the number of clients subscribed to the receiving shard channel.",
+		Arguments: []commands.Arg{
+			{Name: "shardchannel", Type: commands.ArgKey, KeySpecIndex: 0},
+			{Name: "message", Type: commands.ArgString},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &SPublishCommand{Channel: args[1], Message: args[2]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:       "pubsub",
+		Arity:      -2,
+		Summary:    "A container for Pub/Sub commands.",
+		Since:      "2.8.0",
+		Group:      commands.GroupPubSub,
+		Complexity: "Depends on subcommand.",
+		Subcommands: []*commands.CommandSpec{
+			{
+				Name:       "channels",
+				Arity:      -2,
+				Flags:      introspectionFlags,
+				Summary:    "Returns the active channels.",
+				Since:      "2.8.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(N) where N is the number of active channels, and assuming constant time pattern matching (relatively short channels and patterns)",
+				Arguments: []commands.Arg{
+					{Name: "pattern", Type: commands.ArgPattern, Optional: true},
+				},
+				Parse: parsePubSubChannels,
+			},
+			{
+				Name:       "help",
+				Arity:      2,
+				Flags:      []string{commands.FlagLoading, commands.FlagStale},
+				Summary:    "Returns helpful text about the different subcommands.",
+				Since:      "6.2.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(1)",
+				Parse: func(args []string) (commands.Command, error) {
+					return &PubSubHelpCommand{}, nil
+				},
+			},
+			{
+				Name:       "numpat",
+				Arity:      2,
+				Flags:      introspectionFlags,
+				Summary:    "Returns a count of unique pattern subscriptions.",
+				Since:      "2.8.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(1)",
+				Parse: func(args []string) (commands.Command, error) {
+					return &PubSubNumPatCommand{}, nil
+				},
+			},
+			{
+				Name:       "numsub",
+				Arity:      -2,
+				Flags:      introspectionFlags,
+				Summary:    "Returns a count of subscribers to channels.",
+				Since:      "2.8.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(N) for the NUMSUB subcommand, where N is the number of requested channels",
+				Arguments: []commands.Arg{
+					{Name: "channel", Type: commands.ArgString, Optional: true, Multiple: true},
+				},
+				Parse: func(args []string) (commands.Command, error) {
+					return &PubSubNumSubCommand{Channels: args[2:]}, nil
+				},
+			},
+			{
+				Name:       "shardchannels",
+				Arity:      -2,
+				Flags:      introspectionFlags,
+				Summary:    "Returns the active shard channels.",
+				Since:      "7.0.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(N) where N is the number of active shard channels, and assuming constant time pattern matching (relatively short shard channels).",
+				Arguments: []commands.Arg{
+					{Name: "pattern", Type: commands.ArgPattern, Optional: true},
+				},
+				Parse: parsePubSubShardChannels,
+			},
+			{
+				Name:       "shardnumsub",
+				Arity:      -2,
+				Flags:      introspectionFlags,
+				Summary:    "Returns the count of subscribers of shard channels.",
+				Since:      "7.0.0",
+				Group:      commands.GroupPubSub,
+				Complexity: "O(N) for the SHARDNUMSUB subcommand, where N is the number of requested shard channels",
+				Arguments: []commands.Arg{
+					{Name: "shardchannel", Type: commands.ArgString, Optional: true, Multiple: true},
+				},
+				Parse: func(args []string) (commands.Command, error) {
+					return &PubSubShardNumSubCommand{Channels: args[2:]}, nil
+				},
+			},
+		},
+	})
+}
+
+func parsePubSubChannels(args []string) (commands.Command, error) {
+	pattern, err := pubSubPattern(args)
+	if err != nil {
+		return nil, err
+	}
+	return &PubSubChannelsCommand{Pattern: pattern}, nil
+}
+
+func parsePubSubShardChannels(args []string) (commands.Command, error) {
+	pattern, err := pubSubPattern(args)
+	if err != nil {
+		return nil, err
+	}
+	return &PubSubShardChannelsCommand{Pattern: pattern}, nil
+}
+
+// pubSubPattern returns the optional pattern of PUBSUB CHANNELS and
+// SHARDCHANNELS
+func pubSubPattern(args []string) (string, error) {
+	switch len(args) {
+	case 2:
+		return "", nil
+	case 3:
+		return args[2], nil
+	default:
+		return "", fmt.Errorf("unknown subcommand or wrong number of arguments for '%.128s'. Try PUBSUB HELP.", args[1])
+	}
+}
+
+// allowedWhileSubscribed reports whether a RESP2 connection with
+// subscriptions may run a command. Such a connection only receives messages,
+// so anything else would interleave replies with them.
+func allowedWhileSubscribed(spec *commands.CommandSpec) bool {
+	switch spec.Name {
+	case "subscribe", "unsubscribe", "psubscribe", "punsubscribe",
+		"ssubscribe", "sunsubscribe", "ping", "quit", "reset":
+		return true
+	}
+	return false
+}
+
+// subscribedRESP2 reports whether the connection is in the RESP2 subscribed
+// mode, where only a few commands are allowed
+func (h *Handler) subscribedRESP2() bool {
+	return h.respWriter.Protocol() == resp.RESP2 &&
+		h.subscriptionCount()+h.shardSubscriptionCount() > 0
+}
+
+// subscriber returns the connection's subscriber, creating it on first use
+// along with the goroutine writing the messages it receives
+func (h *Handler) subscriber() *pubsub.Subscriber {
+	if h.sub == nil {
+		h.sub = pubsub.NewSubscriber(h.server.config.PubSubBufferLimit, func() {
+			// Closing the connection also unblocks a pending write
+			log.Printf("Client %s closed for overcoming of output buffer limits.", h.conn.RemoteAddr())
+			h.conn.Close()
+		})
+		go h.pushMessages(h.sub)
+	}
+	return h.sub
+}
+
+// pushMessages writes the messages published to the connection's
+// subscriptions as they arrive, until the connection closes. The publisher
+// only queues them, so a slow subscriber never holds it up; one falling too
+// far behind is disconnected instead.
+func (h *Handler) pushMessages(sub *pubsub.Subscriber) {
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-sub.Ready():
+		}
+
+		messages := sub.Take()
+		h.outMu.Lock()
+		var err error
+		for _, msg := range messages {
+			if err = h.respWriter.WriteInterface(msg); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = h.writer.Flush()
+		}
+		h.outMu.Unlock()
+		if err != nil {
+			h.conn.Close()
+			return
+		}
+	}
+}
+
+func (c *SubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	hub, sub := h.server.pubsub, h.subscriber()
+	replies := make(multiReply, len(c.Channels))
+	for i, channel := range c.Channels {
+		hub.Subscribe(sub, channel)
+		replies[i] = types.Push{"subscribe", channel, hub.Count(sub)}
+	}
+	return replies, nil
+}
+
+func (c *UnsubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.unsubscribe("unsubscribe", c.Channels, h.server.pubsub.ChannelsOf, h.server.pubsub.Unsubscribe), nil
+}
+
+func (c *PSubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	hub, sub := h.server.pubsub, h.subscriber()
+	replies := make(multiReply, len(c.Patterns))
+	for i, pattern := range c.Patterns {
+		hub.PSubscribe(sub, pattern)
+		replies[i] = types.Push{"psubscribe", pattern, hub.Count(sub)}
+	}
+	return replies, nil
+}
+
+func (c *PUnsubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.unsubscribe("punsubscribe", c.Patterns, h.server.pubsub.PatternsOf, h.server.pubsub.PUnsubscribe), nil
+}
+
+func (c *SSubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	hub, sub := h.server.pubsub, h.subscriber()
+	replies := make(multiReply, len(c.Channels))
+	for i, channel := range c.Channels {
+		hub.SSubscribe(sub, channel)
+		replies[i] = types.Push{"ssubscribe", channel, hub.ShardCount(sub)}
+	}
+	return replies, nil
+}
+
+func (c *SUnsubscribeCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.unsubscribe("sunsubscribe", c.Channels, h.server.pubsub.ShardChannelsOf, h.server.pubsub.SUnsubscribe), nil
+}
+
+// unsubscribe drops the named subscriptions, or all of those listed by all
+// when names is empty, with one reply per subscription. Without any there is
+// a single reply with a nil name. Shard subscriptions are counted apart from
+// the others in the replies.
+func (h *Handler) unsubscribe(kind string, names []string,
+	all func(*pubsub.Subscriber) []string,
+	drop func(*pubsub.Subscriber, string) bool,
+) multiReply {
+	sub := h.sub
+	if len(names) == 0 && sub != nil {
+		names = all(sub)
+	}
+	count := h.subscriptionCount
+	if kind == "sunsubscribe" {
+		count = h.shardSubscriptionCount
+	}
+	if len(names) == 0 {
+		return multiReply{types.Push{kind, nil, count()}}
+	}
+
+	replies := make(multiReply, len(names))
+	for i, name := range names {
+		if sub != nil {
+			drop(sub, name)
+		}
+		replies[i] = types.Push{kind, name, count()}
+	}
+	return replies
+}
+
+// subscriptionCount returns the number of channels and patterns the
+// connection is subscribed to
+func (h *Handler) subscriptionCount() int {
+	if h.sub == nil {
+		return 0
+	}
+	return h.server.pubsub.Count(h.sub)
+}
+
+// shardSubscriptionCount returns the number of shard channels the connection
+// is subscribed to
+func (h *Handler) shardSubscriptionCount() int {
+	if h.sub == nil {
+		return 0
+	}
+	return h.server.pubsub.ShardCount(h.sub)
+}
+
+func (c *PublishCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.server.pubsub.Publish(c.Channel, c.Message), nil
+}
+
+func (c *PubSubChannelsCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.server.pubsub.Channels(c.Pattern), nil
+}
+
+func (c *PubSubNumSubCommand) executeConn(h *Handler) (interface{}, error) {
+	reply := make([]interface{}, 0, len(c.Channels)*2)
+	for _, channel := range c.Channels {
+		reply = append(reply, channel, h.server.pubsub.NumSub(channel))
+	}
+	return reply, nil
+}
+
+func (c *SPublishCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.server.pubsub.SPublish(c.Channel, c.Message), nil
+}
+
+func (c *PubSubShardChannelsCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.server.pubsub.ShardChannels(c.Pattern), nil
+}
+
+func (c *PubSubShardNumSubCommand) executeConn(h *Handler) (interface{}, error) {
+	reply := make([]interface{}, 0, len(c.Channels)*2)
+	for _, channel := range c.Channels {
+		reply = append(reply, channel, h.server.pubsub.ShardNumSub(channel))
+	}
+	return reply, nil
+}
+
+func (c *PubSubNumPatCommand) executeConn(h *Handler) (interface{}, error) {
+	return h.server.pubsub.NumPat(), nil
+}
+
+func (c *PubSubHelpCommand) executeConn(h *Handler) (interface{}, error) {
+	lines := []string{
+		"PUBSUB <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+		"CHANNELS [<pattern>]",
+		"    Return the currently active channels matching a <pattern> (default: '*').",
+		"NUMPAT",
+		"    Return number of subscriptions to patterns.",
+		"NUMSUB [<channel> ...]",
+		"    Return the number of subscribers for the specified channels, excluding",
+		"    pattern subscriptions(default: no channels).",
+		"SHARDCHANNELS [<pattern>]",
+		"    Return the currently active shard level channels matching a <pattern> (default: '*').",
+		"SHARDNUMSUB [<shardchannel> ...]",
+		"    Return the number of subscribers for the specified shard level channel(s)",
+		"HELP",
+		"    Print this help.",
+	}
+
+	result := make([]interface{}, len(lines))
+	for i, line := range lines {
+		result[i] = types.SimpleString(line)
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"github.com/hardikphalet/go-redis/internal/commands"
+)
+
+// QuitCommand closes the connection once the reply is sent
+type QuitCommand struct{ connOnly }
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "quit",
+		Arity: -1,
+		Flags: []string{
+			commands.FlagAllowBusy, commands.FlagNoScript, commands.FlagLoading,
+			commands.FlagStale, commands.FlagFast, commands.FlagNoAuth,
+		},
+		ACLCategories: []string{"connection"},
+		Summary:       "Closes the connection.",
+		Since:         "1.0.0",
+		Group:         commands.GroupConnection,
+		Complexity:    "O(1)",
+		Parse: func(args []string) (commands.Command, error) {
+			return &QuitCommand{}, nil
+		},
+	})
+}
+
+func (c *QuitCommand) executeConn(h *Handler) (interface{}, error) {
+	h.quit = true
+	return commands.OK, nil
+}
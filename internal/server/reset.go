@@ -0,0 +1,44 @@
+package server
+
+import (
+	"github.com/hardikphalet/go-redis/internal/commands"
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// ResetCommand returns the connection to the state of a new one
+type ResetCommand struct{ connOnly }
+
+func init() {
+	commands.Register(&commands.CommandSpec{
+		Name:  "reset",
+		Arity: 1,
+		Flags: []string{
+			commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+			commands.FlagFast, commands.FlagNoAuth, commands.FlagAllowBusy,
+		},
+		ACLCategories: []string{"connection"},
+		Summary:       "Resets the connection.",
+		Since:         "6.2.0",
+		Group:         commands.GroupConnection,
+		Complexity:    "O(1)",
+		Parse: func(args []string) (commands.Command, error) {
+			return &ResetCommand{}, nil
+		},
+	})
+}
+
+// executeConn discards the transaction and the watched keys, drops every
+// subscription, switches back to RESP2, clears the name and authenticates
+// as the default user, which needs AUTH again when it has a password
+func (c *ResetCommand) executeConn(h *Handler) (interface{}, error) {
+	h.tx = nil
+	h.store.Unwatch(h.watcher)
+	if h.sub != nil {
+		h.server.pubsub.UnsubscribeAll(h.sub)
+	}
+	h.respWriter.SetProtocol(resp.RESP2)
+	h.name = ""
+	h.setAuthenticated(h.server.config.RequirePass == "")
+	return types.SimpleString("RESET"), nil
+}
@@ -5,25 +5,58 @@ import (
 	"log"
 	"net"
 	"sync"
+	"sync/atomic"
 
+	"github.com/hardikphalet/go-redis/internal/clock"
+	"github.com/hardikphalet/go-redis/internal/pubsub"
 	"github.com/hardikphalet/go-redis/internal/store"
 )
 
+// Version is the Redis version the server reports to clients
+const Version = "7.4.0"
+
 type Server struct {
 	listener net.Listener
 	store    store.Store
-	port     string
-	wg       sync.WaitGroup
-	quit     chan struct{}
+	pubsub   *pubsub.Hub
+	config   Config
+	// clock is the configured clock, as adjusted by DEBUG
+	clock *clock.Adjustable
+	port  string
+	wg    sync.WaitGroup
+	quit  chan struct{}
+
+	// nextClientID hands out connection IDs, starting at 1
+	nextClientID atomic.Int64
+
+	// activeExpire is cleared by DEBUG SET-ACTIVE-EXPIRE 0 to leave
+	// expired keys until they are accessed
+	activeExpire atomic.Bool
+
+	// keyspaceMu is held shared by every keyspace command and exclusively
+	// by EXEC, which makes transactions atomic
+	keyspaceMu sync.RWMutex
+
+	// blocking holds the clients blocked on keys by commands such as BLPOP
+	blocking *blocking
 }
 
 // New creates a new Redis server instance
-func New(address string) *Server {
-	return &Server{
-		port:  address,
-		store: store.NewMemoryStore(),
-		quit:  make(chan struct{}),
+func New(config Config) *Server {
+	clk := clock.NewAdjustable(config.Clock)
+	st := store.NewMemoryStore(clk)
+	s := &Server{
+		port:     config.Addr,
+		config:   config,
+		clock:    clk,
+		store:    st,
+		pubsub:   pubsub.NewHub(),
+		quit:     make(chan struct{}),
+		blocking: newBlocking(),
 	}
+	s.activeExpire.Store(true)
+	st.SetNotifier(s.notifyKeyspaceEvent)
+	return s
 }
 
 // Start initializes the server and starts listening for connections
@@ -37,8 +70,9 @@ func (s *Server) Start() error {
 	log.Printf("Server listening on %s", s.port)
 
 	// Accept connections in a separate goroutine
-	s.wg.Add(1)
+	s.wg.Add(2)
 	go s.acceptConnections()
+	go s.cron()
 
 	return nil
 }
@@ -96,7 +130,7 @@ func (s *Server) handleConnection(conn net.Conn) {
 	remoteAddr := conn.RemoteAddr().String()
 	log.Printf("New client connection from %s", remoteAddr)
 
-	handler := NewHandler(conn, s.store)
+	handler := NewHandler(conn, s)
 	if err := handler.Handle(); err != nil {
 		log.Printf("Error handling connection from %s: %v", remoteAddr, err)
 	} else {
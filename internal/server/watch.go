@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/hardikphalet/go-redis/internal/commands"
+)
+
+// WatchCommand watches keys, making the next EXEC fail if any of them is
+// modified before it runs
+type WatchCommand struct {
+	connOnly
+	Keys []string
+}
+
+// UnwatchCommand forgets the watched keys
+type UnwatchCommand struct{ connOnly }
+
+func init() {
+	flags := []string{
+		commands.FlagNoScript, commands.FlagLoading, commands.FlagStale,
+		commands.FlagFast, commands.FlagAllowBusy,
+	}
+
+	commands.Register(&commands.CommandSpec{
+		Name:          "watch",
+		Arity:         -2,
+		Flags:         flags,
+		FirstKey:      1,
+		LastKey:       -1,
+		Step:          1,
+		ACLCategories: []string{"transaction"},
+		KeySpecs: []commands.KeySpec{
+			{Flags: []string{commands.KeyRO}, BeginIndex: 1, LastKey: -1, KeyStep: 1},
+		},
+		Summary:    "Monitors changes to keys to determine the execution of a transaction.",
+		Since:      "2.2.0",
+		Group:      commands.GroupTransactions,
+		Complexity: "O(1) for every key.",
+		Arguments: []commands.Arg{
+			{Name: "key", Type: commands.ArgKey, KeySpecIndex: 0, Multiple: true},
+		},
+		Parse: func(args []string) (commands.Command, error) {
+			return &WatchCommand{Keys: args[1:]}, nil
+		},
+	})
+	commands.Register(&commands.CommandSpec{
+		Name:          "unwatch",
+		Arity:         1,
+		Flags:         flags,
+		ACLCategories: []string{"transaction"},
+		Summary:       "Forgets about watched keys of a transaction.",
+		Since:         "2.2.0",
+		Group:         commands.GroupTransactions,
+		Complexity:    "O(1)",
+		Parse: func(args []string) (commands.Command, error) {
+			return &UnwatchCommand{}, nil
+		},
+	})
+}
+
+// executeConn watches the keys. Inside MULTI, WATCH is rejected like any
+// command refused while queueing, which makes EXEC fail.
+func (c *WatchCommand) executeConn(h *Handler) (interface{}, error) {
+	if h.tx != nil {
+		h.abortTransaction()
+		return nil, fmt.Errorf("WATCH inside MULTI is not allowed")
+	}
+	h.store.Watch(h.watcher, c.Keys...)
+	return commands.OK, nil
+}
+
+func (c *UnwatchCommand) executeConn(h *Handler) (interface{}, error) {
+	h.store.Unwatch(h.watcher)
+	return commands.OK, nil
+}
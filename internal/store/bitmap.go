@@ -0,0 +1,431 @@
+package store
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/notify"
+)
+
+// Bitmaps are plain strings addressed by bit, bit 0 being the most
+// significant bit of the first byte, as in Redis
+
+// SetBit sets or clears the bit at offset of the string at key, growing it
+// with zero bytes as needed, and returns the bit's previous value
+func (s *MemoryStore) SetBit(key string, offset int64, on bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	oldLen := len(cur)
+	cur = grow(cur, int(offset>>3)+1)
+	old := getBit(cur, offset)
+	if exists && len(cur) == oldLen && (old == 1) == on {
+		return old, nil
+	}
+
+	mask := byte(0x80) >> (offset & 7)
+	if on {
+		cur[offset>>3] |= mask
+	} else {
+		cur[offset>>3] &^= mask
+	}
+	s.setString(key, cur)
+	s.touch(key)
+	s.notify(notify.String, "setbit", key)
+	return old, nil
+}
+
+// GetBit returns the bit at offset of the string at key, bits past its end
+// and of a missing key being 0
+func (s *MemoryStore) GetBit(key string, offset int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+	}
+	return getBit(cur, offset), nil
+}
+
+// BitCount returns the number of set bits of the string at key, in r or in
+// all of it if r is nil
+func (s *MemoryStore) BitCount(key string, r *options.BitRange) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		return 0, nil
+	}
+	// Unlike BITPOS, BITCOUNT doesn't clamp a range entirely before the
+	// start of the string to its first byte
+	if r != nil && r.Start < 0 && r.End < 0 && r.Start > r.End {
+		return 0, nil
+	}
+	first, last, ok := bitRange(len(cur), r)
+	if !ok {
+		return 0, nil
+	}
+	return countBits(cur, first, last), nil
+}
+
+// BitPos returns the offset of the first bit set to bit in r of the string
+// at key, or -1. Without an end the string counts as followed by clear bits,
+// so looking for 0 in a string of set bits finds the bit past its end.
+func (s *MemoryStore) BitPos(key string, bit int, r *options.BitRange) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		if bit == 1 {
+			return -1, nil
+		}
+		return 0, nil
+	}
+	first, last, ok := bitRange(len(cur), r)
+	if !ok {
+		return -1, nil
+	}
+	if pos := findBit(cur, bit, first, last); pos >= 0 || bit == 1 || (r != nil && r.HasEnd) {
+		return pos, nil
+	}
+	return last + 1, nil
+}
+
+// BitOp stores at dest the result of the bitwise operation op, one of AND,
+// OR, XOR, NOT and DIFF, over the strings at keys, and returns its length.
+// Shorter and missing strings are padded with zero bytes. DIFF is the bits
+// of the first string set in none of the others. An empty result deletes
+// dest.
+func (s *MemoryStore) BitOp(op, dest string, keys []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	srcs := make([][]byte, len(keys))
+	maxLen := 0
+	for i, key := range keys {
+		cur, _, err := s.lookupString(key)
+		if err != nil {
+			return 0, err
+		}
+		srcs[i] = cur
+		maxLen = max(maxLen, len(cur))
+	}
+
+	if maxLen == 0 {
+		if _, exists := s.lookup(dest); exists {
+			s.data.delete(dest)
+			delete(s.expires, dest)
+			s.touch(dest)
+			s.notify(notify.Generic, "del", dest)
+		}
+		return 0, nil
+	}
+
+	res := make([]byte, maxLen)
+	for i := range res {
+		b := byteAt(srcs[0], i)
+		switch op {
+		case "NOT":
+			b = ^b
+		case "AND":
+			for _, src := range srcs[1:] {
+				b &= byteAt(src, i)
+			}
+		case "OR":
+			for _, src := range srcs[1:] {
+				b |= byteAt(src, i)
+			}
+		case "XOR":
+			for _, src := range srcs[1:] {
+				b ^= byteAt(src, i)
+			}
+		case "DIFF":
+			for _, src := range srcs[1:] {
+				b &^= byteAt(src, i)
+			}
+		}
+		res[i] = b
+	}
+
+	if s.data.set(dest, res) {
+		s.notify(notify.New, "new", dest)
+	}
+	delete(s.expires, dest)
+	s.touch(dest)
+	s.notify(notify.String, "set", dest)
+	return maxLen, nil
+}
+
+// BitField runs ops against the string at key and returns their results, nil
+// for a write that overflowed with OVERFLOW FAIL. If any op writes, the
+// string is created or grown to hold all the fields written.
+func (s *MemoryStore) BitField(key string, ops []options.BitFieldOp) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	for _, op := range ops {
+		if op.Op != options.BitFieldGet {
+			size = max(size, (op.Offset+int64(op.Bits)-1)>>3+1)
+		}
+	}
+	// A write to a new or grown string counts as a change even if the bits
+	// written were already 0
+	grown := false
+	if size > 0 {
+		grown = !exists || int64(len(cur)) < size
+		cur = grow(cur, int(size))
+		s.setString(key, cur)
+	} else if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+	}
+
+	results := make([]interface{}, len(ops))
+	changes := 0
+	for i, op := range ops {
+		old := getField(cur, op.Offset, op.Bits)
+		if op.Op == options.BitFieldGet {
+			results[i] = signExtend(old, op.Bits, op.Signed)
+			continue
+		}
+
+		var value uint64
+		var overflow bool
+		if op.Signed {
+			oldVal := signExtend(old, op.Bits, true)
+			var v int64
+			if op.Op == options.BitFieldIncrBy {
+				v, overflow = signedFieldAdd(oldVal, op.Value, op.Bits, op.Overflow)
+				results[i] = v
+			} else {
+				v, overflow = signedFieldAdd(op.Value, 0, op.Bits, op.Overflow)
+				results[i] = oldVal
+			}
+			value = uint64(v)
+		} else {
+			if op.Op == options.BitFieldIncrBy {
+				value, overflow = unsignedFieldAdd(old, op.Value, op.Bits, op.Overflow)
+				results[i] = int64(value)
+			} else {
+				value, overflow = unsignedFieldAdd(uint64(op.Value), 0, op.Bits, op.Overflow)
+				results[i] = int64(old)
+			}
+		}
+
+		if overflow && op.Overflow == options.OverflowFail {
+			results[i] = nil
+			continue
+		}
+		setField(cur, op.Offset, op.Bits, value)
+		if grown || old != value&fieldMask(op.Bits) {
+			changes++
+		}
+	}
+
+	if changes > 0 {
+		s.touch(key)
+		s.notify(notify.String, "setbit", key)
+	}
+	return results, nil
+}
+
+// bitRange returns the first and last bit offsets r selects of a string of
+// n bytes, the whole string if r is nil, and false if the range is empty.
+// Negative offsets count from the end and the range is clamped to the
+// string.
+func bitRange(n int, r *options.BitRange) (int64, int64, bool) {
+	if r == nil {
+		return 0, int64(n)*8 - 1, n > 0
+	}
+	total := int64(n)
+	if r.Bit {
+		total *= 8
+	}
+	start, end := r.Start, r.End
+	if !r.HasEnd {
+		end = total - 1
+	}
+	if start < 0 {
+		start += total
+	}
+	if end < 0 {
+		end += total
+	}
+	start = max(start, 0)
+	end = min(max(end, 0), total-1)
+	if start > end {
+		return 0, 0, false
+	}
+	if !r.Bit {
+		start, end = start*8, end*8+7
+	}
+	return start, end, true
+}
+
+// getBit returns the bit at offset of b, 0 past its end
+func getBit(b []byte, offset int64) int {
+	if offset>>3 >= int64(len(b)) {
+		return 0
+	}
+	return int(b[offset>>3]>>(7-offset&7)) & 1
+}
+
+// byteAt returns the byte at i of b, 0 past its end
+func byteAt(b []byte, i int) byte {
+	if i >= len(b) {
+		return 0
+	}
+	return b[i]
+}
+
+// countBits returns the number of set bits of b from first to last
+func countBits(b []byte, first, last int64) int64 {
+	fb, lb := first>>3, last>>3
+	head := byte(0xFF) >> (first & 7)
+	tail := byte(0xFF) << (7 - last&7)
+	if fb == lb {
+		return int64(bits.OnesCount8(b[fb] & head & tail))
+	}
+
+	n := bits.OnesCount8(b[fb]&head) + bits.OnesCount8(b[lb]&tail)
+	mid := b[fb+1 : lb]
+	for len(mid) >= 8 {
+		n += bits.OnesCount64(binary.BigEndian.Uint64(mid))
+		mid = mid[8:]
+	}
+	for _, c := range mid {
+		n += bits.OnesCount8(c)
+	}
+	return int64(n)
+}
+
+// findBit returns the offset of the first bit of b set to bit from first to
+// last, or -1
+func findBit(b []byte, bit int, first, last int64) int64 {
+	// Whole bytes holding only the other bit are skipped at once
+	skip := byte(0)
+	if bit == 0 {
+		skip = 0xFF
+	}
+	for i := first; i <= last; {
+		if i&7 == 0 && i+7 <= last && b[i>>3] == skip {
+			i += 8
+			continue
+		}
+		if getBit(b, i) == bit {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// fieldMask returns a mask of the low n bits
+func fieldMask(n int) uint64 {
+	if n == 64 {
+		return math.MaxUint64
+	}
+	return 1<<n - 1
+}
+
+// getField returns the n bits of b from offset as an unsigned integer, bits
+// past the end of b being 0
+func getField(b []byte, offset int64, n int) uint64 {
+	var v uint64
+	for i := int64(0); i < int64(n); i++ {
+		v = v<<1 | uint64(getBit(b, offset+i))
+	}
+	return v
+}
+
+// setField stores the low n bits of v in b from offset, which b must hold
+func setField(b []byte, offset int64, n int, v uint64) {
+	for i := int64(0); i < int64(n); i++ {
+		mask := byte(0x80) >> ((offset + i) & 7)
+		if v>>(int64(n)-1-i)&1 == 1 {
+			b[(offset+i)>>3] |= mask
+		} else {
+			b[(offset+i)>>3] &^= mask
+		}
+	}
+}
+
+// signExtend returns the n bit field v as an int64, which is two's
+// complement if signed
+func signExtend(v uint64, n int, signed bool) int64 {
+	if signed && n < 64 && v&(1<<(n-1)) != 0 {
+		v |= math.MaxUint64 << n
+	}
+	return int64(v)
+}
+
+// unsignedFieldAdd returns value+incr for an n bit unsigned field, and
+// whether it overflowed, in which case the result wraps or saturates as
+// overflow says
+func unsignedFieldAdd(value uint64, incr int64, n int, overflow int) (uint64, bool) {
+	maxValue := fieldMask(n)
+	maxIncr := int64(maxValue - value)
+	minIncr := -int64(value)
+
+	var limit uint64
+	switch {
+	case value > maxValue || (incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case incr < 0 && incr < minIncr:
+		limit = 0
+	default:
+		return value + uint64(incr), false
+	}
+	if overflow == options.OverflowWrap {
+		return (value + uint64(incr)) & maxValue, true
+	}
+	return limit, true
+}
+
+// signedFieldAdd is unsignedFieldAdd for an n bit signed field
+func signedFieldAdd(value, incr int64, n int, overflow int) (int64, bool) {
+	maxValue := int64(fieldMask(n - 1))
+	minValue := -maxValue - 1
+	// These may overflow, but are only used once value is known to be in
+	// range, when they don't
+	maxIncr := int64(uint64(maxValue) - uint64(value))
+	minIncr := minValue - value
+
+	var limit int64
+	switch {
+	case value > maxValue || (n != 64 && incr > maxIncr) || (value >= 0 && incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case value < minValue || (n != 64 && incr < minIncr) || (value < 0 && incr < 0 && incr < minIncr):
+		limit = minValue
+	default:
+		return value + incr, false
+	}
+	if overflow == options.OverflowWrap {
+		return signExtend(uint64(value+incr)&fieldMask(n), n, true), true
+	}
+	return limit, true
+}
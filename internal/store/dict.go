@@ -0,0 +1,300 @@
+package store
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"math/rand/v2"
+)
+
+const (
+	// dictInitialSize is the number of buckets of a new table
+	dictInitialSize = 4
+	// dictMinFill is the inverse of the fill ratio below which a table
+	// shrinks
+	dictMinFill = 8
+	// dictRehashEmptyVisits bounds the empty buckets one rehash step skips
+	dictRehashEmptyVisits = 10
+)
+
+// dict is a chained hash table modelled on Redis's. Unlike a Go map it can
+// be walked with a cursor, as SCAN does: the cursor visits buckets in
+// reverse binary order, which keeps its place when the table grows or
+// shrinks between calls. Tables are resized incrementally, a bucket per
+// write, so that no single operation pays for rehashing everything. Reads
+// don't modify the dict, so they may run concurrently.
+type dict[V any] struct {
+	seed maphash.Seed
+	// ht[1] is only used while rehashing from ht[0] into it
+	ht   [2][]*dictEntry[V]
+	used [2]int
+	// rehashIdx is the next bucket of ht[0] to move, or -1 when not
+	// rehashing
+	rehashIdx int
+}
+
+type dictEntry[V any] struct {
+	key  string
+	val  V
+	next *dictEntry[V]
+}
+
+func newDict[V any]() *dict[V] {
+	return &dict[V]{seed: maphash.MakeSeed(), rehashIdx: -1}
+}
+
+func (d *dict[V]) len() int {
+	return d.used[0] + d.used[1]
+}
+
+func (d *dict[V]) hash(key string) uint64 {
+	return maphash.String(d.seed, key)
+}
+
+func (d *dict[V]) rehashing() bool {
+	return d.rehashIdx >= 0
+}
+
+// find returns the entry of key, or nil
+func (d *dict[V]) find(key string) *dictEntry[V] {
+	if d.len() == 0 {
+		return nil
+	}
+	h := d.hash(key)
+	for t := 0; t < 2; t++ {
+		table := d.ht[t]
+		if len(table) == 0 {
+			continue
+		}
+		for e := table[h&uint64(len(table)-1)]; e != nil; e = e.next {
+			if e.key == key {
+				return e
+			}
+		}
+		if !d.rehashing() {
+			break
+		}
+	}
+	return nil
+}
+
+func (d *dict[V]) get(key string) (V, bool) {
+	if e := d.find(key); e != nil {
+		return e.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// set stores val at key and reports whether the key is new
+func (d *dict[V]) set(key string, val V) bool {
+	d.rehashStep()
+	if e := d.find(key); e != nil {
+		e.val = val
+		return false
+	}
+	d.expandIfNeeded()
+
+	// New entries go to the new table while rehashing
+	t := 0
+	if d.rehashing() {
+		t = 1
+	}
+	table := d.ht[t]
+	i := d.hash(key) & uint64(len(table)-1)
+	table[i] = &dictEntry[V]{key: key, val: val, next: table[i]}
+	d.used[t]++
+	return true
+}
+
+// delete removes key and reports whether it was there
+func (d *dict[V]) delete(key string) bool {
+	if d.len() == 0 {
+		return false
+	}
+	d.rehashStep()
+	h := d.hash(key)
+	for t := 0; t < 2; t++ {
+		table := d.ht[t]
+		if len(table) == 0 {
+			continue
+		}
+		i := h & uint64(len(table)-1)
+		for prev, e := (*dictEntry[V])(nil), table[i]; e != nil; prev, e = e, e.next {
+			if e.key != key {
+				continue
+			}
+			if prev == nil {
+				table[i] = e.next
+			} else {
+				prev.next = e.next
+			}
+			d.used[t]--
+			d.shrinkIfNeeded()
+			return true
+		}
+		if !d.rehashing() {
+			break
+		}
+	}
+	return false
+}
+
+// each calls fn for every entry until it returns false. The dict must not
+// be modified meanwhile.
+func (d *dict[V]) each(fn func(key string, val V) bool) {
+	for t := 0; t < 2; t++ {
+		for _, e := range d.ht[t] {
+			for ; e != nil; e = e.next {
+				if !fn(e.key, e.val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// random returns a random entry, or nil if the dict is empty. Like Redis's
+// dictGetRandomKey it picks a bucket and then an entry of its chain, so
+// entries sharing a bucket are a little less likely to be picked.
+func (d *dict[V]) random() *dictEntry[V] {
+	if d.len() == 0 {
+		return nil
+	}
+	var e *dictEntry[V]
+	for e == nil {
+		// Buckets of ht[0] below rehashIdx are empty
+		n0 := len(d.ht[0])
+		i := rand.IntN(n0 + len(d.ht[1]))
+		if i < n0 {
+			e = d.ht[0][i]
+		} else {
+			e = d.ht[1][i-n0]
+		}
+	}
+	n := 0
+	for c := e; c != nil; c = c.next {
+		n++
+	}
+	for i := rand.IntN(n); i > 0; i-- {
+		e = e.next
+	}
+	return e
+}
+
+func (d *dict[V]) expandIfNeeded() {
+	if d.rehashing() {
+		return
+	}
+	if len(d.ht[0]) == 0 {
+		d.ht[0] = make([]*dictEntry[V], dictInitialSize)
+		return
+	}
+	if d.used[0] >= len(d.ht[0]) {
+		d.resize(d.used[0] + 1)
+	}
+}
+
+func (d *dict[V]) shrinkIfNeeded() {
+	if d.rehashing() || len(d.ht[0]) <= dictInitialSize {
+		return
+	}
+	if d.used[0]*dictMinFill <= len(d.ht[0]) {
+		d.resize(d.used[0])
+	}
+}
+
+// resize starts rehashing into a table of the smallest power of two holding
+// n entries
+func (d *dict[V]) resize(n int) {
+	size := dictInitialSize
+	for size < n {
+		size *= 2
+	}
+	if size == len(d.ht[0]) {
+		return
+	}
+	d.ht[1] = make([]*dictEntry[V], size)
+	d.rehashIdx = 0
+}
+
+// rehashStep moves one bucket of ht[0] to ht[1], and makes ht[1] the table
+// once ht[0] is empty
+func (d *dict[V]) rehashStep() {
+	if !d.rehashing() {
+		return
+	}
+	emptyVisits := dictRehashEmptyVisits
+	for d.used[0] > 0 && d.ht[0][d.rehashIdx] == nil {
+		d.rehashIdx++
+		if emptyVisits--; emptyVisits == 0 {
+			return
+		}
+	}
+
+	if d.used[0] > 0 {
+		mask := uint64(len(d.ht[1]) - 1)
+		for e := d.ht[0][d.rehashIdx]; e != nil; {
+			next := e.next
+			i := d.hash(e.key) & mask
+			e.next = d.ht[1][i]
+			d.ht[1][i] = e
+			d.used[0]--
+			d.used[1]++
+			e = next
+		}
+		d.ht[0][d.rehashIdx] = nil
+		d.rehashIdx++
+	}
+
+	if d.used[0] == 0 {
+		d.ht[0], d.ht[1] = d.ht[1], nil
+		d.used[0], d.used[1] = d.used[1], 0
+		d.rehashIdx = -1
+	}
+}
+
+// scan calls fn for the entries of the buckets at cursor and returns the
+// next cursor, 0 once the walk is complete. Starting from 0, a walk returns
+// every entry present for its whole duration at least once, and may return
+// some twice.
+func (d *dict[V]) scan(cursor uint64, fn func(key string, val V)) uint64 {
+	if d.len() == 0 {
+		return 0
+	}
+	emit := func(e *dictEntry[V]) {
+		for ; e != nil; e = e.next {
+			fn(e.key, e.val)
+		}
+	}
+
+	if !d.rehashing() {
+		mask := uint64(len(d.ht[0]) - 1)
+		emit(d.ht[0][cursor&mask])
+		return nextCursor(cursor, mask)
+	}
+
+	// Walk the bucket of the smaller table, then the buckets of the larger
+	// one that it expands to
+	small, large := d.ht[0], d.ht[1]
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	m0, m1 := uint64(len(small)-1), uint64(len(large)-1)
+	emit(small[cursor&m0])
+	for {
+		emit(large[cursor&m1])
+		cursor = nextCursor(cursor, m1)
+		if cursor&(m0^m1) == 0 {
+			return cursor
+		}
+	}
+}
+
+// nextCursor increments the bits of cursor covered by mask, starting from
+// the most significant one
+func nextCursor(cursor, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	return bits.Reverse64(cursor)
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/notify"
+)
+
+const (
+	// activeExpireKeysPerLoop is how many keys with a TTL each step of the
+	// active expire cycle samples
+	activeExpireKeysPerLoop = 20
+	// activeExpireAcceptableStale is the percentage of expired keys in a
+	// sample below which the cycle stops: the rest can wait for the next one
+	activeExpireAcceptableStale = 10
+)
+
+// ActiveExpireCycle deletes keys whose TTL elapsed, and hash fields whose
+// timeout did, so that keys nobody reads again don't stay in memory forever.
+// Like Redis, it samples a few keys with a TTL at a time and keeps going only
+// while the samples show that many of them expired, or until timeLimit of
+// real time runs out. The lock is only held for one sample at a time. It
+// returns the number of keys deleted or that had fields deleted.
+func (s *MemoryStore) ActiveExpireCycle(timeLimit time.Duration) int {
+	start := time.Now()
+	deleted := 0
+	for {
+		sampled, expired := s.activeExpireSample(activeExpireKeysPerLoop)
+		deleted += expired
+		if sampled == 0 || expired*100/sampled <= activeExpireAcceptableStale {
+			return deleted
+		}
+		if time.Since(start) > timeLimit {
+			return deleted
+		}
+	}
+}
+
+// activeExpireSample checks up to n keys with a TTL and up to n hashes with
+// field timeouts, and deletes the expired keys and up to fieldsExpiredPerCall
+// expired fields of each hash. Map iteration starts at a random position,
+// which makes the keys checked a random sample. A hash counts as expired if
+// any of its fields did.
+func (s *MemoryStore) activeExpireSample(n int) (sampled, expired int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	keys := 0
+	for key, expiry := range s.expires {
+		if keys == n {
+			break
+		}
+		keys++
+		if now.After(expiry) {
+			s.expireKey(key)
+			expired++
+		}
+	}
+
+	hashes := 0
+	for key := range s.fieldExpires {
+		if hashes == n {
+			break
+		}
+		h, _ := s.lookupHash(key)
+		if h == nil || h.expires.len() == 0 {
+			delete(s.fieldExpires, key)
+			continue
+		}
+		hashes++
+		if before := h.fields.len(); s.expireFields(key, h, nil) == nil || h.fields.len() < before {
+			expired++
+		}
+	}
+	return keys + hashes, expired
+}
+
+// expireKey deletes a key whose TTL elapsed. The caller must hold the write
+// lock.
+func (s *MemoryStore) expireKey(key string) {
+	s.data.delete(key)
+	delete(s.expires, key)
+	s.touchExpired(key)
+	s.notify(notify.Expired, "expired", key)
+}
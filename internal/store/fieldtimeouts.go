@@ -0,0 +1,102 @@
+package store
+
+import (
+	"container/heap"
+	"time"
+)
+
+// fieldTimeouts holds the timeouts of a hash's fields, indexed by field and
+// by time: a min-heap orders them, so the fields due first are found in
+// O(log N) instead of by scanning every field with a timeout. Redis keeps
+// them in time buckets for the same reason.
+type fieldTimeouts struct {
+	byField map[string]*fieldTimeout
+	queue   timeoutQueue
+}
+
+// fieldTimeout is the timeout of a field and its position in the heap
+type fieldTimeout struct {
+	field string
+	at    time.Time
+	index int
+}
+
+func newFieldTimeouts() *fieldTimeouts {
+	return &fieldTimeouts{byField: make(map[string]*fieldTimeout)}
+}
+
+// len returns the number of fields with a timeout
+func (t *fieldTimeouts) len() int {
+	return len(t.queue)
+}
+
+// get returns the timeout of field, if it has one
+func (t *fieldTimeouts) get(field string) (time.Time, bool) {
+	if ft, ok := t.byField[field]; ok {
+		return ft.at, true
+	}
+	return time.Time{}, false
+}
+
+// set makes field expire at at, replacing any timeout it had
+func (t *fieldTimeouts) set(field string, at time.Time) {
+	if ft, ok := t.byField[field]; ok {
+		ft.at = at
+		heap.Fix(&t.queue, ft.index)
+		return
+	}
+	ft := &fieldTimeout{field: field, at: at}
+	t.byField[field] = ft
+	heap.Push(&t.queue, ft)
+}
+
+// delete removes the timeout of field, and reports whether it had one
+func (t *fieldTimeouts) delete(field string) bool {
+	ft, ok := t.byField[field]
+	if !ok {
+		return false
+	}
+	delete(t.byField, field)
+	heap.Remove(&t.queue, ft.index)
+	return true
+}
+
+// due returns the field whose timeout ends first, if it elapsed by now
+func (t *fieldTimeouts) due(now time.Time) (string, bool) {
+	if len(t.queue) == 0 || !now.After(t.queue[0].at) {
+		return "", false
+	}
+	return t.queue[0].field, true
+}
+
+// expired reports whether field has a timeout that elapsed by now
+func (t *fieldTimeouts) expired(field string, now time.Time) bool {
+	at, ok := t.get(field)
+	return ok && now.After(at)
+}
+
+// timeoutQueue is a min-heap of field timeouts, for container/heap
+type timeoutQueue []*fieldTimeout
+
+func (q timeoutQueue) Len() int           { return len(q) }
+func (q timeoutQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q timeoutQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timeoutQueue) Push(x interface{}) {
+	ft := x.(*fieldTimeout)
+	ft.index = len(*q)
+	*q = append(*q, ft)
+}
+
+func (q *timeoutQueue) Pop() interface{} {
+	old := *q
+	ft := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return ft
+}
@@ -0,0 +1,668 @@
+package store
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/notify"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// fieldsExpiredPerCall bounds how many fields whose timeout elapsed one
+// look at a whole hash deletes, so that a hash with many fields due doesn't
+// hold the store's lock for long. The rest are deleted by later calls, and
+// skipped by the commands reading the whole hash until then.
+const fieldsExpiredPerCall = 100
+
+// HashFieldMaxExpire is the latest Unix time in milliseconds a hash field
+// may expire at. Redis keeps field timeouts in 48 bits.
+const HashFieldMaxExpire = 1<<48 - 1
+
+// Replies of the hash field expiration commands for each field
+const (
+	// HashFieldMissing is for a field, or key, that doesn't exist
+	HashFieldMissing = -2
+	// HashFieldNoTTL is for a field without a timeout
+	HashFieldNoTTL = -1
+	// HashFieldConditionNotMet is for a timeout NX, XX, GT or LT prevented
+	HashFieldConditionNotMet = 0
+	// HashFieldUpdated is for a timeout set or removed
+	HashFieldUpdated = 1
+	// HashFieldDeleted is for a field deleted by a timeout already past
+	HashFieldDeleted = 2
+)
+
+// Hash is a Redis hash. Its fields may have timeouts of their own, which
+// are enforced like key timeouts: when the field is looked up, and by the
+// active expire cycle.
+type Hash struct {
+	fields *dict[string]
+	// expires holds the timeouts of the fields that have one
+	expires *fieldTimeouts
+}
+
+func newHash() *Hash {
+	return &Hash{fields: newDict[string](), expires: newFieldTimeouts()}
+}
+
+// len returns the number of fields
+func (h *Hash) len() int {
+	return h.fields.len()
+}
+
+// delete removes field and its timeout, and reports whether it existed
+func (h *Hash) delete(field string) bool {
+	h.expires.delete(field)
+	return h.fields.delete(field)
+}
+
+// lookupHash returns the hash at key, or nil if it is missing. The caller
+// must hold the write lock.
+func (s *MemoryStore) lookupHash(key string) (*Hash, error) {
+	val, exists := s.lookup(key)
+	if !exists {
+		return nil, nil
+	}
+	h, ok := val.(*Hash)
+	if !ok {
+		return nil, errs.ErrWrongType
+	}
+	return h, nil
+}
+
+// readHash is lookupHash for commands reading fields, or the whole hash if
+// fields is nil. Those fields are deleted first if their timeout elapsed, as
+// expireFields does.
+func (s *MemoryStore) readHash(key string, fields []string) (*Hash, error) {
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		return nil, nil
+	}
+	return s.expireFields(key, h, fields), nil
+}
+
+// writeHash is readHash for commands writing fields, which creates the hash
+// if it is missing
+func (s *MemoryStore) writeHash(key string, fields []string) (*Hash, error) {
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		h = s.expireFields(key, h, fields)
+	}
+	if h == nil {
+		h = newHash()
+		s.data.set(key, h)
+		s.notify(notify.New, "new", key)
+	}
+	return h, nil
+}
+
+// expireFields deletes the fields of the hash at key whose timeout elapsed:
+// those of fields, or if it is nil up to fieldsExpiredPerCall of the ones due
+// first. It returns the hash, or nil if no field is left and the key was
+// deleted.
+func (s *MemoryStore) expireFields(key string, h *Hash, fields []string) *Hash {
+	if h.expires.len() == 0 {
+		return h
+	}
+	now := s.clock.Now()
+	expired := 0
+	if fields == nil {
+		for expired < fieldsExpiredPerCall {
+			field, ok := h.expires.due(now)
+			if !ok {
+				break
+			}
+			h.delete(field)
+			expired++
+		}
+	} else {
+		for _, field := range fields {
+			if h.expires.expired(field, now) {
+				h.delete(field)
+				expired++
+			}
+		}
+	}
+	if expired == 0 {
+		return h
+	}
+
+	s.touch(key)
+	s.notify(notify.Hash, "hexpired", key)
+	if s.deleteIfEmpty(key, h) {
+		return nil
+	}
+	return h
+}
+
+// setFieldExpire makes field of the hash at key expire at at
+func (s *MemoryStore) setFieldExpire(key string, h *Hash, field string, at time.Time) {
+	h.expires.set(field, at)
+	s.fieldExpires[key] = struct{}{}
+}
+
+// HSet sets the fields of the hash at key, fieldValues alternating fields
+// and values, and removes their timeouts. It returns the number of fields
+// added.
+func (s *MemoryStore) HSet(key string, fieldValues []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.writeHash(key, everyOther(fieldValues))
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for i := 0; i < len(fieldValues); i += 2 {
+		if h.fields.set(fieldValues[i], fieldValues[i+1]) {
+			added++
+		}
+		h.expires.delete(fieldValues[i])
+	}
+	s.touch(key)
+	s.notify(notify.Hash, "hset", key)
+	return added, nil
+}
+
+// HSetNX sets field of the hash at key only if it doesn't exist, and reports
+// whether it did
+func (s *MemoryStore) HSetNX(key, field, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.writeHash(key, []string{field})
+	if err != nil {
+		return false, err
+	}
+	if _, exists := h.fields.get(field); exists {
+		return false, nil
+	}
+	h.fields.set(field, value)
+	s.touch(key)
+	s.notify(notify.Hash, "hset", key)
+	return true, nil
+}
+
+// HGet returns the value of field of the hash at key, or nil
+func (s *MemoryStore) HGet(key, field string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, []string{field})
+	if err != nil || h == nil {
+		return nil, err
+	}
+	if val, ok := h.fields.get(field); ok {
+		return val, nil
+	}
+	return nil, nil
+}
+
+// HMGet returns the values of fields of the hash at key, nil for the ones
+// missing
+func (s *MemoryStore) HMGet(key string, fields []string) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, fields)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(fields))
+	if h == nil {
+		return values, nil
+	}
+	for i, field := range fields {
+		if val, ok := h.fields.get(field); ok {
+			values[i] = val
+		}
+	}
+	return values, nil
+}
+
+// HDel deletes fields of the hash at key, and the key if none is left. It
+// returns the number of fields deleted.
+func (s *MemoryStore) HDel(key string, fields []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.lookupHash(key)
+	if err != nil || h == nil {
+		return 0, err
+	}
+	if h = s.expireFields(key, h, fields); h == nil {
+		return 0, nil
+	}
+	deleted := 0
+	for _, field := range fields {
+		if h.delete(field) {
+			deleted++
+		}
+	}
+	if deleted > 0 {
+		s.touch(key)
+		s.notify(notify.Hash, "hdel", key)
+		s.deleteIfEmpty(key, h)
+	}
+	return deleted, nil
+}
+
+// HLen returns the number of fields of the hash at key. Like Redis's, it
+// counts fields whose timeout elapsed that weren't deleted yet, if there are
+// more than fieldsExpiredPerCall of them.
+func (s *MemoryStore) HLen(key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, nil)
+	if err != nil || h == nil {
+		return 0, err
+	}
+	return h.fields.len(), nil
+}
+
+// HGetAll returns the fields of the hash at key with their values
+func (s *MemoryStore) HGetAll(key string) (types.Map, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return types.Map{}, nil
+	}
+	now := s.clock.Now()
+	entries := make(types.Map, 0, h.fields.len())
+	h.fields.each(func(field, val string) bool {
+		if !h.expires.expired(field, now) {
+			entries = append(entries, types.MapEntry{Key: field, Value: val})
+		}
+		return true
+	})
+	return entries, nil
+}
+
+// HIncrBy adds delta to the integer in field of the hash at key, a missing
+// field counting as 0, and returns the result. The field keeps its timeout.
+func (s *MemoryStore) HIncrBy(key, field string, delta int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.writeHash(key, []string{field})
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if cur, exists := h.fields.get(field); exists {
+		var ok bool
+		if n, ok = ParseInt(cur); !ok {
+			return 0, errs.Errorf("hash value is not an integer")
+		}
+	}
+	if (delta < 0 && n < 0 && delta < math.MinInt64-n) ||
+		(delta > 0 && n > 0 && delta > math.MaxInt64-n) {
+		return 0, errs.Errorf("increment or decrement would overflow")
+	}
+
+	n += delta
+	h.fields.set(field, strconv.FormatInt(n, 10))
+	s.touch(key)
+	s.notify(notify.Hash, "hincrby", key)
+	return n, nil
+}
+
+// HIncrByFloat is HIncrBy for floating point numbers. It returns the result
+// as it is stored.
+func (s *MemoryStore) HIncrByFloat(key, field string, delta float64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// An infinite or NaN increment always gives an infinite or NaN result,
+	// so it is refused before a missing hash is created. A finite one only
+	// overflows when added to a field that exists.
+	if math.IsNaN(delta) || math.IsInf(delta, 0) {
+		return "", errs.Errorf("increment would produce NaN or Infinity")
+	}
+	h, err := s.writeHash(key, []string{field})
+	if err != nil {
+		return "", err
+	}
+	var f float64
+	if cur, exists := h.fields.get(field); exists {
+		var ok bool
+		if f, ok = ParseFloat(cur); !ok {
+			return "", errs.Errorf("hash value is not a float")
+		}
+	}
+	f += delta
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errs.Errorf("increment would produce NaN or Infinity")
+	}
+
+	result := strconv.FormatFloat(f, 'f', -1, 64)
+	h.fields.set(field, result)
+	s.touch(key)
+	s.notify(notify.Hash, "hincrbyfloat", key)
+	return result, nil
+}
+
+// randFieldChunk is the most entries HRandField allocates ahead of picking
+// them, so that memory grows with the reply actually built rather than with
+// the count asked for
+const randFieldChunk = 1024
+
+// HRandField returns random fields of the hash at key with their values:
+// count distinct ones, or all of them if there are fewer, or with a negative
+// count -count that may repeat
+func (s *MemoryStore) HRandField(key string, count int64) ([]types.MapEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, nil)
+	if err != nil || h == nil || count == 0 {
+		return nil, err
+	}
+
+	// Fields left due by readHash are skipped, which takes collecting the
+	// others first
+	now := s.clock.Now()
+	_, stale := h.expires.due(now)
+	if count < 0 && !stale {
+		entries := make([]types.MapEntry, 0, min(-count, randFieldChunk))
+		for range -count {
+			e := h.fields.random()
+			entries = append(entries, types.MapEntry{Key: e.key, Value: e.val})
+		}
+		return entries, nil
+	}
+
+	entries := make([]types.MapEntry, 0, h.fields.len())
+	h.fields.each(func(field, val string) bool {
+		if !stale || !h.expires.expired(field, now) {
+			entries = append(entries, types.MapEntry{Key: field, Value: val})
+		}
+		return true
+	})
+	if count < 0 {
+		if len(entries) == 0 {
+			return nil, nil
+		}
+		picked := make([]types.MapEntry, 0, min(-count, randFieldChunk))
+		for range -count {
+			picked = append(picked, entries[rand.IntN(len(entries))])
+		}
+		return picked, nil
+	}
+	if count >= int64(len(entries)) {
+		return entries, nil
+	}
+	// A partial Fisher-Yates shuffle picks the first count
+	for i := range int(count) {
+		j := i + rand.IntN(len(entries)-i)
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries[:count], nil
+}
+
+// HExpire sets the timeout of fields of the hash at key, if the condition
+// in opts is met, and returns a HashField code for each. A time already past
+// deletes the fields.
+func (s *MemoryStore) HExpire(key string, at time.Time, opts *options.ExpireOptions, fields []string) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]int, len(fields))
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		h = s.expireFields(key, h, fields)
+	}
+	if h == nil {
+		for i := range results {
+			results[i] = HashFieldMissing
+		}
+		return results, nil
+	}
+
+	past := !at.After(s.clock.Now())
+	updated, deleted := 0, 0
+	for i, field := range fields {
+		if _, ok := h.fields.get(field); !ok {
+			results[i] = HashFieldMissing
+			continue
+		}
+		// A field without a timeout counts as never expiring
+		cur, hasTTL := h.expires.get(field)
+		if opts != nil && ((opts.IsNX() && hasTTL) ||
+			(opts.IsXX() && !hasTTL) ||
+			(opts.IsGT() && (!hasTTL || !at.After(cur))) ||
+			(opts.IsLT() && hasTTL && !at.Before(cur))) {
+			results[i] = HashFieldConditionNotMet
+			continue
+		}
+		if past {
+			h.delete(field)
+			deleted++
+			results[i] = HashFieldDeleted
+			continue
+		}
+		s.setFieldExpire(key, h, field, at)
+		updated++
+		results[i] = HashFieldUpdated
+	}
+
+	if updated+deleted > 0 {
+		s.touch(key)
+	}
+	if updated > 0 {
+		s.notify(notify.Hash, "hexpire", key)
+	}
+	if deleted > 0 {
+		s.notify(notify.Hash, "hdel", key)
+		s.deleteIfEmpty(key, h)
+	}
+	return results, nil
+}
+
+// HExpireTime returns when each of fields of the hash at key expires, as a
+// Unix time in milliseconds, or HashFieldMissing or HashFieldNoTTL
+func (s *MemoryStore) HExpireTime(key string, fields []string) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, fields)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(fields))
+	for i, field := range fields {
+		if h == nil {
+			results[i] = HashFieldMissing
+		} else if _, ok := h.fields.get(field); !ok {
+			results[i] = HashFieldMissing
+		} else if at, ok := h.expires.get(field); ok {
+			results[i] = at.UnixMilli()
+		} else {
+			results[i] = HashFieldNoTTL
+		}
+	}
+	return results, nil
+}
+
+// HPersist removes the timeout of fields of the hash at key and returns
+// HashFieldUpdated for each that had one, or HashFieldMissing or
+// HashFieldNoTTL
+func (s *MemoryStore) HPersist(key string, fields []string) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		h = s.expireFields(key, h, fields)
+	}
+	results := make([]int, len(fields))
+	persisted := 0
+	for i, field := range fields {
+		if h == nil {
+			results[i] = HashFieldMissing
+		} else if _, ok := h.fields.get(field); !ok {
+			results[i] = HashFieldMissing
+		} else if !h.expires.delete(field) {
+			results[i] = HashFieldNoTTL
+		} else {
+			persisted++
+			results[i] = HashFieldUpdated
+		}
+	}
+	if persisted > 0 {
+		s.touch(key)
+		s.notify(notify.Hash, "hpersist", key)
+	}
+	return results, nil
+}
+
+// HGetEx returns the values of fields of the hash at key, nil for the ones
+// missing, and sets or removes the timeouts of those that exist as opts
+// says. A time already past deletes them.
+func (s *MemoryStore) HGetEx(key string, fields []string, opts *options.GetExOptions) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.readHash(key, fields)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(fields))
+	if h == nil {
+		return values, nil
+	}
+	var found []string
+	for i, field := range fields {
+		if val, ok := h.fields.get(field); ok {
+			values[i] = val
+			found = append(found, field)
+		}
+	}
+	if len(found) == 0 || opts == nil || opts.ExpiryType == "" {
+		return values, nil
+	}
+
+	if opts.IsPERSIST() {
+		persisted := 0
+		for _, field := range found {
+			if h.expires.delete(field) {
+				persisted++
+			}
+		}
+		if persisted > 0 {
+			s.touch(key)
+			s.notify(notify.Hash, "hpersist", key)
+		}
+		return values, nil
+	}
+
+	s.touch(key)
+	now := s.clock.Now()
+	if at := opts.ExpiryTime(now); at.After(now) {
+		for _, field := range found {
+			s.setFieldExpire(key, h, field, at)
+		}
+		s.notify(notify.Hash, "hexpire", key)
+		return values, nil
+	}
+	for _, field := range found {
+		h.delete(field)
+	}
+	s.notify(notify.Hash, "hdel", key)
+	s.deleteIfEmpty(key, h)
+	return values, nil
+}
+
+// HSetEx sets the fields of the hash at key, fieldValues alternating fields
+// and values, and sets or removes their timeouts as opts says. With FNX
+// nothing is set if any of the fields exists, with FXX unless all of them
+// do. It reports whether the fields were set.
+func (s *MemoryStore) HSetEx(key string, fieldValues []string, opts *options.HSetExOptions) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := everyOther(fieldValues)
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return false, err
+	}
+	if h != nil {
+		h = s.expireFields(key, h, fields)
+	}
+	if opts.Condition != "" {
+		for _, field := range fields {
+			exists := false
+			if h != nil {
+				_, exists = h.fields.get(field)
+			}
+			if (opts.Condition == "FNX" && exists) || (opts.Condition == "FXX" && !exists) {
+				return false, nil
+			}
+		}
+	}
+	if h == nil {
+		h = newHash()
+		s.data.set(key, h)
+		s.notify(notify.New, "new", key)
+	}
+
+	for i := 0; i < len(fieldValues); i += 2 {
+		h.fields.set(fieldValues[i], fieldValues[i+1])
+		if !opts.IsKEEPTTL() {
+			h.expires.delete(fieldValues[i])
+		}
+	}
+	s.touch(key)
+	s.notify(notify.Hash, "hset", key)
+	if opts.ExpiryType == "" || opts.IsKEEPTTL() {
+		return true, nil
+	}
+
+	now := s.clock.Now()
+	if at := opts.ExpiryTime(now); at.After(now) {
+		for _, field := range fields {
+			s.setFieldExpire(key, h, field, at)
+		}
+		s.notify(notify.Hash, "hexpire", key)
+		return true, nil
+	}
+	for _, field := range fields {
+		h.delete(field)
+	}
+	s.notify(notify.Hash, "hdel", key)
+	s.deleteIfEmpty(key, h)
+	return true, nil
+}
+
+// everyOther returns the elements of s at even indexes, the fields of
+// alternating fields and values
+func everyOther(s []string) []string {
+	out := make([]string, 0, (len(s)+1)/2)
+	for i := 0; i < len(s); i += 2 {
+		out = append(out, s[i])
+	}
+	return out
+}
@@ -0,0 +1,376 @@
+package store
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/notify"
+)
+
+// lookupList returns the list at key, or nil if it is missing. The caller
+// must hold the write lock.
+func (s *MemoryStore) lookupList(key string) (*List, error) {
+	val, exists := s.lookup(key)
+	if !exists {
+		return nil, nil
+	}
+	l, ok := val.(*List)
+	if !ok {
+		return nil, errs.ErrWrongType
+	}
+	return l, nil
+}
+
+// readList is lookupList for commands only reading the list
+func (s *MemoryStore) readList(key string) (*List, error) {
+	l, err := s.lookupList(key)
+	if err == nil && l == nil {
+		s.notify(notify.KeyMiss, "keymiss", key)
+	}
+	return l, err
+}
+
+// pushEvent and popEvent name the keyspace events of pushes and pops at
+// either end
+func pushEvent(front bool) string {
+	if front {
+		return "lpush"
+	}
+	return "rpush"
+}
+
+func popEvent(front bool) string {
+	if front {
+		return "lpop"
+	}
+	return "rpop"
+}
+
+// listIndex turns index, negative counting from the end, into an index into
+// a list of length n, and reports whether it is in range
+func listIndex(index int64, n int) (int, bool) {
+	if index < 0 {
+		index += int64(n)
+	}
+	if index < 0 || index >= int64(n) {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// listRange clamps start and stop, negative counting from the end, to a list
+// of length n, and reports whether the range has any elements
+func listRange(start, stop int64, n int) (int, int, bool) {
+	length := int64(n)
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	start = max(start, 0)
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+	stop = min(stop, length-1)
+	return int(start), int(stop), true
+}
+
+// Push adds elements one by one at the front of the list at key, or unless
+// front at the back, so that the last one pushed at the front ends up first.
+// The list is created if it is missing, unless existing. It returns the
+// length of the list, 0 if it is missing.
+func (s *MemoryStore) Push(key string, elements []string, front, existing bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil {
+		return 0, err
+	}
+	if l == nil {
+		if existing {
+			return 0, nil
+		}
+		l = newList()
+		s.data.set(key, l)
+		s.notify(notify.New, "new", key)
+	}
+	for _, e := range elements {
+		l.push(e, front)
+	}
+	s.touch(key)
+	s.notify(notify.List, pushEvent(front), key)
+	return l.len(), nil
+}
+
+// Pop removes up to count elements from the front of the list at key, or
+// unless front from the back, and the key if none is left. It returns nil if
+// the list is missing.
+func (s *MemoryStore) Pop(key string, front bool, count int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	return s.popList(key, l, front, count), nil
+}
+
+// popList pops up to count elements from the list at key, notifying the
+// pop and the deletion of the key if it is left empty
+func (s *MemoryStore) popList(key string, l *List, front bool, count int) []string {
+	count = min(count, l.len())
+	popped := make([]string, count)
+	for i := range popped {
+		popped[i] = l.pop(front)
+	}
+	if count > 0 {
+		s.touch(key)
+		s.notify(notify.List, popEvent(front), key)
+		s.deleteIfEmpty(key, l)
+	}
+	return popped
+}
+
+// LMPop pops up to count elements from the first list of keys that isn't
+// empty, at the front or unless front the back. It returns the key popped
+// from and the elements, or nil elements if all of the lists are missing.
+func (s *MemoryStore) LMPop(keys []string, front bool, count int) (string, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		l, err := s.lookupList(key)
+		if err != nil {
+			return "", nil, err
+		}
+		if l != nil {
+			return key, s.popList(key, l, front, count), nil
+		}
+	}
+	return "", nil, nil
+}
+
+// LMove pops an element from the front of the list at src, or unless
+// srcFront the back, and pushes it at the front of the list at dst, or
+// unless dstFront the back. It returns the element, or nil if src is
+// missing.
+func (s *MemoryStore) LMove(src, dst string, srcFront, dstFront bool) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from, err := s.lookupList(src)
+	if err != nil || from == nil {
+		return nil, err
+	}
+	// dst is checked before anything is popped
+	to, err := s.lookupList(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pop is notified before the push. src is only deleted once the
+	// element is pushed, as it may be dst too.
+	e := from.pop(srcFront)
+	s.touch(src)
+	s.notify(notify.List, popEvent(srcFront), src)
+
+	if to == nil {
+		to = newList()
+		s.data.set(dst, to)
+		s.notify(notify.New, "new", dst)
+	}
+	to.push(e, dstFront)
+	s.touch(dst)
+	s.notify(notify.List, pushEvent(dstFront), dst)
+	s.deleteIfEmpty(src, from)
+	return e, nil
+}
+
+// LLen returns the length of the list at key, 0 if it is missing
+func (s *MemoryStore) LLen(key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.readList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	return l.len(), nil
+}
+
+// LRange returns the elements of the list at key from index start to stop,
+// both included, negative indexes counting from the end
+func (s *MemoryStore) LRange(key string, start, stop int64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.readList(key)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return []string{}, nil
+	}
+	first, last, ok := listRange(start, stop, l.len())
+	if !ok {
+		return []string{}, nil
+	}
+	return l.rangeOf(first, last), nil
+}
+
+// LIndex returns the element at index of the list at key, negative indexes
+// counting from the end, or nil if there is none
+func (s *MemoryStore) LIndex(key string, index int64) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.readList(key)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	i, ok := listIndex(index, l.len())
+	if !ok {
+		return nil, nil
+	}
+	return l.index(i), nil
+}
+
+// LSet replaces the element at index of the list at key
+func (s *MemoryStore) LSet(key string, index int64, element string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return errs.ErrNoSuchKey
+	}
+	i, ok := listIndex(index, l.len())
+	if !ok {
+		return errs.Errorf("index out of range")
+	}
+	l.set(i, element)
+	s.touch(key)
+	s.notify(notify.List, "lset", key)
+	return nil
+}
+
+// LInsert inserts element before or after the first occurrence of pivot in
+// the list at key. It returns the new length, -1 if pivot wasn't found and 0
+// if the list is missing.
+func (s *MemoryStore) LInsert(key string, before bool, pivot, element string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	at := -1
+	l.each(false, func(i int, v string) bool {
+		if v == pivot {
+			at = i
+			return false
+		}
+		return true
+	})
+	if at < 0 {
+		return -1, nil
+	}
+	if !before {
+		at++
+	}
+	l.insert(at, element)
+	s.touch(key)
+	s.notify(notify.List, "linsert", key)
+	return l.len(), nil
+}
+
+// LRem removes the first count occurrences of element from the list at key,
+// the last -count ones if count is negative and all of them if it is 0. It
+// returns how many it removed.
+func (s *MemoryStore) LRem(key string, count int64, element string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	reverse := count < 0
+	if reverse {
+		count = -count
+	}
+	// A count beyond the length removes every occurrence
+	removed := l.remove(element, int(min(count, int64(l.len()))), reverse)
+	if removed > 0 {
+		s.touch(key)
+		s.notify(notify.List, "lrem", key)
+		s.deleteIfEmpty(key, l)
+	}
+	return removed, nil
+}
+
+// LTrim keeps only the elements of the list at key from index start to
+// stop, both included, deleting the key if that leaves none
+func (s *MemoryStore) LTrim(key string, start, stop int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.lookupList(key)
+	if err != nil || l == nil {
+		return err
+	}
+	n := l.len()
+	first, last, ok := listRange(start, stop, n)
+	if !ok {
+		first, last = n, n-1
+	}
+	l.trimBack(n - 1 - last)
+	l.trimFront(first)
+	s.touch(key)
+	s.notify(notify.List, "ltrim", key)
+	s.deleteIfEmpty(key, l)
+	return nil
+}
+
+// LPos returns the indexes of up to count elements of the list at key equal
+// to element, all of them if count is 0. With a negative rank the search
+// starts from the end, and the first |rank|-1 matches are skipped. Only the
+// first maxLen elements searched are compared, all of them if it is 0.
+func (s *MemoryStore) LPos(key, element string, rank, count, maxLen int64) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.readList(key)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	reverse := rank < 0
+	skip := rank - 1
+	if reverse {
+		skip = -rank - 1
+	}
+
+	var matches []int64
+	var compared int64
+	l.each(reverse, func(i int, v string) bool {
+		if maxLen > 0 && compared >= maxLen {
+			return false
+		}
+		compared++
+		if v != element {
+			return true
+		}
+		if skip > 0 {
+			skip--
+			return true
+		}
+		matches = append(matches, int64(i))
+		return count == 0 || int64(len(matches)) < count
+	})
+	return matches, nil
+}
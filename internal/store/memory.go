@@ -2,11 +2,15 @@ package store
 
 import (
 	"fmt"
-	"regexp"
+	"math"
 	"sync"
 	"time"
 
+	"github.com/hardikphalet/go-redis/internal/clock"
 	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/glob"
+	"github.com/hardikphalet/go-redis/internal/notify"
 	"github.com/hardikphalet/go-redis/internal/types"
 )
 
@@ -18,28 +22,28 @@ type SortedSetMember struct {
 
 // SortedSet represents a Redis sorted set
 type SortedSet struct {
-	dict    map[string]float64 // For O(1) member lookups
-	sl      *skiplist          // For ordered operations
-	scores  []float64
-	members []string
+	dict *dict[float64] // For O(1) member lookups and ZSCAN
+	sl   *skiplist      // For ordered operations
 }
 
 // Add adds or updates a member in the sorted set
 func (s *SortedSet) Add(member string, score float64) {
+	oldScore, exists := s.dict.get(member)
+
 	// Update dictionary
-	s.dict[member] = score
+	s.dict.set(member, score)
 
-	// Update skiplist
+	// Update skiplist. The skiplist is ordered by score, so a member whose
+	// score changes has to be removed from its old position first.
+	if exists && oldScore != score {
+		s.sl.delete(oldScore, member)
+	}
 	s.sl.insert(score, member)
-
-	// Update slices
-	s.scores = append(s.scores, score)
-	s.members = append(s.members, member)
 }
 
 // Range returns a range of members from the sorted set
 func (s *SortedSet) Range(start, stop int, withScores bool) []interface{} {
-	if s == nil || s.sl == nil || len(s.dict) == 0 {
+	if s == nil || s.sl == nil || s.dict.len() == 0 {
 		return []interface{}{}
 	}
 
@@ -60,7 +64,7 @@ func (s *SortedSet) Range(start, stop int, withScores bool) []interface{} {
 
 // RangeByScore returns elements with scores between min and max
 func (s *SortedSet) RangeByScore(min, max float64, rev bool, withScores bool) []interface{} {
-	if s == nil || s.sl == nil || len(s.dict) == 0 {
+	if s == nil || s.sl == nil || s.dict.len() == 0 {
 		return []interface{}{}
 	}
 
@@ -111,21 +115,22 @@ func (s *SortedSet) RangeByScore(min, max float64, rev bool, withScores bool) []
 // RangeByLex returns elements with lexicographical ordering between min and max
 func (s *SortedSet) RangeByLex(min, max string, rev bool) []interface{} {
 	var result []interface{}
+	if s == nil || s.sl == nil {
+		return result
+	}
 
 	if rev {
 		// Reverse order
-		for i := len(s.members) - 1; i >= 0; i-- {
-			member := s.members[i]
-			if member >= min && member <= max {
-				result = append(result, member)
+		for node := s.sl.tail; node != nil && node != s.sl.head; node = node.backward {
+			if node.member >= min && node.member <= max {
+				result = append(result, node.member)
 			}
 		}
 	} else {
 		// Forward order
-		for i := 0; i < len(s.members); i++ {
-			member := s.members[i]
-			if member >= min && member <= max {
-				result = append(result, member)
+		for node := s.sl.head.forward[0]; node != nil; node = node.forward[0] {
+			if node.member >= min && node.member <= max {
+				result = append(result, node.member)
 			}
 		}
 	}
@@ -134,15 +139,49 @@ func (s *SortedSet) RangeByLex(min, max string, rev bool) []interface{} {
 }
 
 type MemoryStore struct {
-	data    map[string]interface{}
+	data    *dict[interface{}]
 	expires map[string]time.Time
-	mu      sync.RWMutex
+	// fieldExpires holds the keys of hashes that had field timeouts set,
+	// for the active expire cycle. Keys are only removed from it when the
+	// cycle finds they no longer have any.
+	fieldExpires map[string]struct{}
+	// watchers maps each watched key to the connections watching it
+	watchers map[string]map[*Watcher]struct{}
+	// notifier is told about keyspace events, under the store's lock
+	notifier notify.Func
+	// clock decides when keys expire
+	clock clock.Clock
+	mu    sync.RWMutex
 }
 
-func NewMemoryStore() *MemoryStore {
+// NewMemoryStore returns an empty store whose keys expire according to clk
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
 	return &MemoryStore{
-		data:    make(map[string]interface{}),
-		expires: make(map[string]time.Time),
+		data:         newDict[interface{}](),
+		expires:      make(map[string]time.Time),
+		fieldExpires: make(map[string]struct{}),
+		watchers:     make(map[string]map[*Watcher]struct{}),
+		clock:        clk,
+	}
+}
+
+// Now returns the time according to the store's clock
+func (s *MemoryStore) Now() time.Time {
+	return s.clock.Now()
+}
+
+// SetNotifier sets the function told about every keyspace event. It is
+// called with the store locked, so it must not call back into the store.
+func (s *MemoryStore) SetNotifier(fn notify.Func) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = fn
+}
+
+// notify reports a keyspace event. The caller must hold the lock.
+func (s *MemoryStore) notify(class notify.Class, event, key string) {
+	if s.notifier != nil {
+		s.notifier(class, event, key)
 	}
 }
 
@@ -168,43 +207,52 @@ func (s *MemoryStore) Get(key string) (interface{}, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.isExpired(key) {
-		delete(s.data, key)
-		delete(s.expires, key)
+	val, ok := s.lookup(key)
+	if !ok {
+		s.notify(notify.KeyMiss, "keymiss", key)
 		return nil, nil
 	}
-
-	if val, ok := s.data[key]; ok {
-		return val, nil
+	str, isString := val.([]byte)
+	if !isString {
+		return nil, errs.ErrWrongType
 	}
-
-	return nil, nil
+	return string(str), nil
 }
 
-func (s *MemoryStore) Set(key string, value interface{}, opts *options.SetOptions) (interface{}, error) {
+// Set stores value at key. It returns the previous value, for the GET option,
+// and whether the value was stored, which the NX and XX conditions may prevent.
+func (s *MemoryStore) Set(key string, value string, opts *options.SetOptions) (interface{}, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check if key exists
-	exists := false
+	val, exists := s.lookup(key)
+
+	// With GET the old value is returned, so it has to be a string
 	var oldValue interface{}
-	if val, ok := s.data[key]; ok {
-		exists = true
-		oldValue = val
+	if str, isString := val.([]byte); isString {
+		oldValue = string(str)
+	} else if opts != nil && opts.IsGET() && exists {
+		return nil, false, errs.ErrWrongType
 	}
 
 	// Handle NX option - only set if key doesn't exist
 	if opts != nil && opts.IsNX() && exists {
-		return nil, fmt.Errorf("key already exists")
+		return oldValue, false, nil
 	}
 
 	// Handle XX option - only set if key exists
 	if opts != nil && opts.IsXX() && !exists {
-		return nil, fmt.Errorf("key does not exist")
+		return nil, false, nil
 	}
 
-	// Store the value
-	s.data[key] = value
+	// Store the value. Strings are kept as bytes, which the bit and range
+	// commands modify in place.
+	s.data.set(key, []byte(value))
+
+	if !exists {
+		s.notify(notify.New, "new", key)
+	}
+	s.notify(notify.String, "set", key)
 
 	// Handle expiry
 	if opts != nil {
@@ -215,7 +263,8 @@ func (s *MemoryStore) Set(key string, value interface{}, opts *options.SetOption
 			}
 		} else if opts.ExpiryType != "" {
 			// Set new expiry
-			s.expires[key] = opts.ExpiryTime
+			s.expires[key] = opts.ExpiryTime(s.clock.Now())
+			s.notify(notify.Generic, "expire", key)
 		} else {
 			// No expiry specified, remove any existing expiry
 			delete(s.expires, key)
@@ -225,252 +274,254 @@ func (s *MemoryStore) Set(key string, value interface{}, opts *options.SetOption
 		delete(s.expires, key)
 	}
 
-	// Return old value if GET option is set
-	if opts != nil && opts.IsGET() {
-		return oldValue, nil
-	}
-
-	return nil, nil
+	s.touch(key)
+	return oldValue, true, nil
 }
 
-func (s *MemoryStore) Del(key string) error {
+// Del deletes key and reports whether it existed
+func (s *MemoryStore) Del(key string) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.data, key)
+	if _, exists := s.lookup(key); !exists {
+		return false, nil
+	}
+	s.data.delete(key)
 	delete(s.expires, key)
-	return nil
+	s.touch(key)
+	s.notify(notify.Generic, "del", key)
+	return true, nil
 }
 
-func (s *MemoryStore) Expire(key string, ttl time.Duration, opts *options.ExpireOptions) error {
+// Expire makes key expire at the given time and reports whether the timeout
+// was set. It is not set when the key doesn't exist or when the NX, XX, GT or
+// LT condition fails.
+func (s *MemoryStore) Expire(key string, at time.Time, opts *options.ExpireOptions) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.data[key]; !exists {
-		return fmt.Errorf("key does not exist")
+	if _, exists := s.lookup(key); !exists {
+		return false, nil
 	}
 
 	// Handle options
 	if opts != nil {
 		// Check if key has an existing expiry
-		hasExpiry := false
-		if expiry, ok := s.expires[key]; ok {
-			hasExpiry = !time.Now().After(expiry)
-		}
+		expiry, hasExpiry := s.expires[key]
 
 		// Handle NX option - only set expiry if key has no expiry
 		if opts.IsNX() && hasExpiry {
-			return fmt.Errorf("key already has an expiry")
+			return false, nil
 		}
 
 		// Handle XX option - only set expiry if key has an existing expiry
 		if opts.IsXX() && !hasExpiry {
-			return fmt.Errorf("key has no expiry")
+			return false, nil
 		}
 
-		// Handle GT option - only set expiry if new expiry is greater than current one
-		if opts.IsGT() && hasExpiry {
-			currentTTL := time.Until(s.expires[key])
-			if ttl <= currentTTL {
-				return fmt.Errorf("new expiry is not greater than current one")
-			}
+		// Handle GT option - only set expiry if new expiry is greater than
+		// current one. A key without expiry counts as an infinite TTL.
+		if opts.IsGT() && (!hasExpiry || !at.After(expiry)) {
+			return false, nil
 		}
 
 		// Handle LT option - only set expiry if new expiry is less than current one
-		if opts.IsLT() && hasExpiry {
-			currentTTL := time.Until(s.expires[key])
-			if ttl >= currentTTL {
-				return fmt.Errorf("new expiry is not less than current one")
-			}
+		if opts.IsLT() && hasExpiry && !at.Before(expiry) {
+			return false, nil
 		}
 	}
 
-	if ttl <= 0 {
+	// A time already past deletes the key
+	event := "expire"
+	if !at.After(s.clock.Now()) {
 		delete(s.expires, key)
-		delete(s.data, key)
-		return nil
+		s.data.delete(key)
+		event = "del"
+	} else {
+		s.expires[key] = at
 	}
+	s.touch(key)
+	s.notify(notify.Generic, event, key)
+	return true, nil
+}
 
-	s.expires[key] = time.Now().Add(ttl)
-	return nil
+// ExpireTime returns when key expires, or the zero time if it has no
+// timeout. exists is false if the key doesn't exist.
+func (s *MemoryStore) ExpireTime(key string) (at time.Time, exists bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(key); !ok {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		return time.Time{}, false, nil
+	}
+	return s.expires[key], true, nil
 }
 
-func (s *MemoryStore) TTL(key string) (int, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Persist removes the timeout of key and reports whether it had one
+func (s *MemoryStore) Persist(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if expiry, ok := s.expires[key]; ok {
-		if ttl := time.Until(expiry); ttl > 0 {
-			return int(ttl.Seconds()), nil
-		}
-		return -2, nil // -2 indicates that the key has expired
+	if _, ok := s.lookup(key); !ok {
+		return false, nil
 	}
-	if _, ok := s.data[key]; !ok {
-		return -2, nil // Key doesn't exist
+	if _, ok := s.expires[key]; !ok {
+		return false, nil
 	}
-	return -1, nil // -1 indicates no expiry set
+	delete(s.expires, key)
+	s.touch(key)
+	s.notify(notify.Generic, "persist", key)
+	return true, nil
 }
 
 func (s *MemoryStore) Keys(pattern string) ([]string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	keys := make([]string, 0, len(s.data))
-	for k := range s.data {
-		if !s.isExpired(k) && matchPattern(k, pattern) {
+	allKeys := pattern == "*"
+	keys := make([]string, 0, s.data.len())
+	s.data.each(func(k string, _ interface{}) bool {
+		if !s.isExpired(k) && (allKeys || glob.Match(pattern, k)) {
 			keys = append(keys, k)
 		}
-	}
+		return true
+	})
 	return keys, nil
 }
 
-func (s *MemoryStore) isExpired(key string) bool {
-	if expiry, ok := s.expires[key]; ok {
-		return time.Now().After(expiry)
+// lookup returns the value at key, deleting the key first if it has expired.
+// The caller must hold the write lock.
+func (s *MemoryStore) lookup(key string) (interface{}, bool) {
+	if s.isExpired(key) {
+		s.expireKey(key)
+		return nil, false
 	}
-	return false
+	return s.data.get(key)
 }
 
-// matchPattern implements Redis-style pattern matching
-// Supports:
-// * - matches zero or more characters
-// ? - matches exactly one character
-// [...] - matches any character within the brackets
-// [^...] - matches any character not within the brackets
-func matchPattern(str, pattern string) bool {
-	if pattern == "*" {
-		return true
-	}
+// container is a value holding elements, such as a hash or a list
+type container interface {
+	len() int
+}
 
-	// Convert the pattern to a regex pattern
-	regexPattern := ""
-	i := 0
-	for i < len(pattern) {
-		switch pattern[i] {
-		case '*':
-			regexPattern += ".*"
-		case '?':
-			regexPattern += "."
-		case '[':
-			j := i + 1
-			if j < len(pattern) && pattern[j] == '^' {
-				regexPattern += "[^"
-				j++
-			} else {
-				regexPattern += "["
-			}
-			for j < len(pattern) && pattern[j] != ']' {
-				if pattern[j] == '\\' && j+1 < len(pattern) {
-					regexPattern += "\\" + string(pattern[j+1])
-					j += 2
-				} else {
-					regexPattern += string(pattern[j])
-					j++
-				}
-			}
-			if j < len(pattern) && pattern[j] == ']' {
-				regexPattern += "]"
-				i = j
-			} else {
-				regexPattern += "\\["
-			}
-		case '\\':
-			if i+1 < len(pattern) {
-				regexPattern += "\\" + string(pattern[i+1])
-				i++
-			} else {
-				regexPattern += "\\\\"
-			}
-		default:
-			if c := string(pattern[i]); c == "." || c == "+" || c == "(" || c == ")" || c == "|" || c == "{" || c == "}" || c == "$" || c == "^" {
-				regexPattern += "\\" + c
-			} else {
-				regexPattern += c
-			}
-		}
-		i++
+// deleteIfEmpty deletes the key of a container left without elements, as
+// Redis never keeps empty ones, and reports whether it did
+func (s *MemoryStore) deleteIfEmpty(key string, c container) bool {
+	if c.len() > 0 {
+		return false
 	}
+	s.data.delete(key)
+	delete(s.expires, key)
+	s.notify(notify.Generic, "del", key)
+	return true
+}
 
-	// Use the regex package to match
-	matched, err := regexp.MatchString("^"+regexPattern+"$", str)
-	if err != nil {
-		return false
+func (s *MemoryStore) isExpired(key string) bool {
+	if expiry, ok := s.expires[key]; ok {
+		return s.clock.Now().After(expiry)
 	}
-	return matched
+	return false
 }
 
+// ZAdd adds or updates the members of the sorted set at key. It returns the
+// number of members added, or added and updated with CH. With INCR it returns
+// the member's new score, or nil when a condition prevented the update.
 func (s *MemoryStore) ZAdd(key string, members []types.ScoreMember, opts *options.ZAddOptions) (interface{}, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if opts == nil {
+		opts = options.NewZAddOptions()
+	}
+
 	// Check if key exists and is a sorted set
 	var zset *SortedSet
-	if val, exists := s.data[key]; exists {
+	if val, exists := s.lookup(key); exists {
 		var ok bool
 		zset, ok = val.(*SortedSet)
 		if !ok {
-			return nil, fmt.Errorf("WRONGTYPE Operation against a key holding the wrong kind of value")
+			return nil, errs.ErrWrongType
 		}
 	} else {
+		// XX never adds members, so it doesn't create the key either
+		if opts.IsXX() {
+			if opts.IsINCR() {
+				return nil, nil
+			}
+			return 0, nil
+		}
 		zset = &SortedSet{
-			dict: make(map[string]float64),
+			dict: newDict[float64](),
 			sl:   newSkiplist(),
 		}
-		s.data[key] = zset
+		s.data.set(key, zset)
+		s.notify(notify.New, "new", key)
 	}
 
-	// Handle INCR option - only one score-member pair allowed
-	if opts != nil && opts.IsINCR() {
-		if len(members) != 1 {
-			return nil, fmt.Errorf("INCR option requires exactly one score-member pair")
-		}
-		sm := members[0]
-		oldScore, exists := zset.dict[sm.Member]
+	added, updated := 0, 0
+	var newScore interface{}
+	for _, sm := range members {
+		score := sm.Score
+		oldScore, exists := zset.dict.get(sm.Member)
+
 		if !exists {
-			return nil, fmt.Errorf("element does not exist")
+			// Handle XX option - only update existing elements
+			if opts.IsXX() {
+				continue
+			}
+			zset.Add(sm.Member, score)
+			added++
+			newScore = score
+			continue
 		}
-		newScore := oldScore + sm.Score
-		zset.Add(sm.Member, newScore)
-		return newScore, nil
-	}
-
-	// Handle other options
-	changed := 0
-	for _, sm := range members {
-		oldScore, exists := zset.dict[sm.Member]
 
 		// Handle NX option - only add new elements
-		if opts != nil && opts.IsNX() && exists {
+		if opts.IsNX() {
 			continue
 		}
 
-		// Handle XX option - only update existing elements
-		if opts != nil && opts.IsXX() && !exists {
-			continue
+		// Handle INCR option - the score is an increment
+		if opts.IsINCR() {
+			score += oldScore
+			if math.IsNaN(score) {
+				return nil, errs.Errorf("resulting score is not a number (NaN)")
+			}
 		}
 
-		// Handle GT option - only update if new score is greater
-		if opts != nil && opts.IsGT() && exists && sm.Score <= oldScore {
+		// Handle GT and LT options - only update if the new score is
+		// greater or less than the current one
+		if (opts.IsGT() && score <= oldScore) || (opts.IsLT() && score >= oldScore) {
 			continue
 		}
 
-		// Handle LT option - only update if new score is less
-		if opts != nil && opts.IsLT() && exists && sm.Score >= oldScore {
-			continue
+		newScore = score
+		if score != oldScore {
+			zset.Add(sm.Member, score)
+			updated++
 		}
+	}
 
-		// Add or update the element
-		zset.Add(sm.Member, sm.Score)
-		changed++
+	if added+updated > 0 {
+		s.touch(key)
+		event := "zadd"
+		if opts.IsINCR() {
+			event = "zincr"
+		}
+		s.notify(notify.ZSet, event, key)
+	}
+
+	if opts.IsINCR() {
+		return newScore, nil
 	}
 
 	// Return number of changed elements if CH option is set
-	if opts != nil && opts.IsCH() {
-		return changed, nil
+	if opts.IsCH() {
+		return added + updated, nil
 	}
 
 	// Return number of new elements added
-	return len(members), nil
+	return added, nil
 }
 
 func (s *MemoryStore) ZRange(key string, start, stop interface{}, opts *options.ZRangeOptions) ([]interface{}, error) {
@@ -478,7 +529,7 @@ func (s *MemoryStore) ZRange(key string, start, stop interface{}, opts *options.
 	defer s.mu.RUnlock()
 
 	// Check if key exists and is a sorted set
-	if val, exists := s.data[key]; exists {
+	if val, exists := s.data.get(key); exists && !s.isExpired(key) {
 		if zset, ok := val.(*SortedSet); ok {
 			var result []interface{}
 
@@ -518,6 +569,11 @@ func (s *MemoryStore) ZRange(key string, start, stop interface{}, opts *options.
 				result = zset.Range(startIdx, stopIdx, opts != nil && opts.IsWithScores())
 			}
 
+			// An empty range is an empty array, not a null one
+			if result == nil {
+				result = []interface{}{}
+			}
+
 			// Apply LIMIT if specified
 			if opts != nil && opts.Limit.Count > 0 {
 				offset := opts.Limit.Offset
@@ -547,7 +603,8 @@ func (s *MemoryStore) ZRange(key string, start, stop interface{}, opts *options.
 
 			return result, nil
 		}
-		return nil, fmt.Errorf("WRONGTYPE Operation against a key holding the wrong kind of value")
+		return nil, errs.ErrWrongType
 	}
+	s.notify(notify.KeyMiss, "keymiss", key)
 	return []interface{}{}, nil
 }
@@ -0,0 +1,332 @@
+package store
+
+import "slices"
+
+// listNodeSize is the most elements a list node holds. Redis bounds its
+// nodes by size in bytes instead, 8kb by default.
+const listNodeSize = 128
+
+// listNode is a node of a List, holding a run of its elements
+type listNode struct {
+	prev, next *listNode
+	elems      []string
+}
+
+// List is a Redis list, kept as a quicklist: a doubly linked list of nodes
+// holding up to listNodeSize elements each. Pushes and pops at both ends are
+// O(1), and finding an element by index skips whole nodes, walking from the
+// nearer end.
+type List struct {
+	head, tail *listNode
+	length     int
+}
+
+func newList() *List {
+	return &List{}
+}
+
+// len returns the number of elements
+func (l *List) len() int {
+	return l.length
+}
+
+// pushFront adds v before the first element
+func (l *List) pushFront(v string) {
+	if l.head == nil || len(l.head.elems) >= listNodeSize {
+		l.linkAfter(nil, &listNode{elems: make([]string, 0, 8)})
+	}
+	l.head.elems = slices.Insert(l.head.elems, 0, v)
+	l.length++
+}
+
+// pushBack adds v after the last element
+func (l *List) pushBack(v string) {
+	if l.tail == nil || len(l.tail.elems) >= listNodeSize {
+		l.linkAfter(l.tail, &listNode{elems: make([]string, 0, 8)})
+	}
+	l.tail.elems = append(l.tail.elems, v)
+	l.length++
+}
+
+// popFront removes and returns the first element. The list must not be
+// empty.
+func (l *List) popFront() string {
+	n := l.head
+	v := n.elems[0]
+	n.elems[0] = ""
+	n.elems = n.elems[1:]
+	l.length--
+	if len(n.elems) == 0 {
+		l.unlink(n)
+	}
+	return v
+}
+
+// popBack removes and returns the last element. The list must not be empty.
+func (l *List) popBack() string {
+	n := l.tail
+	last := len(n.elems) - 1
+	v := n.elems[last]
+	n.elems[last] = ""
+	n.elems = n.elems[:last]
+	l.length--
+	if len(n.elems) == 0 {
+		l.unlink(n)
+	}
+	return v
+}
+
+// pop is popFront or, unless front, popBack
+func (l *List) pop(front bool) string {
+	if front {
+		return l.popFront()
+	}
+	return l.popBack()
+}
+
+// push is pushFront or, unless front, pushBack
+func (l *List) push(v string, front bool) {
+	if front {
+		l.pushFront(v)
+	} else {
+		l.pushBack(v)
+	}
+}
+
+// locate returns the node holding the element at index i, which must be in
+// range, and the position of the element in it
+func (l *List) locate(i int) (*listNode, int) {
+	if i < l.length/2 {
+		n := l.head
+		for i >= len(n.elems) {
+			i -= len(n.elems)
+			n = n.next
+		}
+		return n, i
+	}
+	n := l.tail
+	i = l.length - 1 - i
+	for i >= len(n.elems) {
+		i -= len(n.elems)
+		n = n.prev
+	}
+	return n, len(n.elems) - 1 - i
+}
+
+// index returns the element at index i, which must be in range
+func (l *List) index(i int) string {
+	n, pos := l.locate(i)
+	return n.elems[pos]
+}
+
+// set replaces the element at index i, which must be in range
+func (l *List) set(i int, v string) {
+	n, pos := l.locate(i)
+	n.elems[pos] = v
+}
+
+// insert inserts v at index i, shifting the element there and the ones
+// after it. i may be the length of the list, appending v. A full node is
+// split in two to make room.
+func (l *List) insert(i int, v string) {
+	switch {
+	case i == 0:
+		l.pushFront(v)
+		return
+	case i == l.length:
+		l.pushBack(v)
+		return
+	}
+
+	n, pos := l.locate(i)
+	if len(n.elems) >= listNodeSize {
+		half := len(n.elems) / 2
+		next := &listNode{elems: slices.Clone(n.elems[half:])}
+		clear(n.elems[half:])
+		n.elems = n.elems[:half]
+		l.linkAfter(n, next)
+		if pos >= half {
+			n, pos = next, pos-half
+		}
+	}
+	n.elems = slices.Insert(n.elems, pos, v)
+	l.length++
+}
+
+// rangeOf returns the elements from index start to stop, both included and
+// in range
+func (l *List) rangeOf(start, stop int) []string {
+	out := make([]string, 0, stop-start+1)
+	n, pos := l.locate(start)
+	for len(out) < cap(out) {
+		end := min(len(n.elems), pos+cap(out)-len(out))
+		out = append(out, n.elems[pos:end]...)
+		n, pos = n.next, 0
+	}
+	return out
+}
+
+// each calls fn with the index and value of every element, from the last
+// one if reverse, until fn returns false
+func (l *List) each(reverse bool, fn func(i int, v string) bool) {
+	if !reverse {
+		i := 0
+		for n := l.head; n != nil; n = n.next {
+			for _, v := range n.elems {
+				if !fn(i, v) {
+					return
+				}
+				i++
+			}
+		}
+		return
+	}
+	i := l.length - 1
+	for n := l.tail; n != nil; n = n.prev {
+		for j := len(n.elems) - 1; j >= 0; j-- {
+			if !fn(i, n.elems[j]) {
+				return
+			}
+			i--
+		}
+	}
+}
+
+// trimFront removes the first count elements, dropping whole nodes where it
+// can
+func (l *List) trimFront(count int) {
+	for count > 0 {
+		n := l.head
+		if count >= len(n.elems) {
+			count -= len(n.elems)
+			l.length -= len(n.elems)
+			l.unlink(n)
+			continue
+		}
+		clear(n.elems[:count])
+		n.elems = n.elems[count:]
+		l.length -= count
+		return
+	}
+}
+
+// trimBack removes the last count elements, dropping whole nodes where it
+// can
+func (l *List) trimBack(count int) {
+	for count > 0 {
+		n := l.tail
+		if count >= len(n.elems) {
+			count -= len(n.elems)
+			l.length -= len(n.elems)
+			l.unlink(n)
+			continue
+		}
+		keep := len(n.elems) - count
+		clear(n.elems[keep:])
+		n.elems = n.elems[:keep]
+		l.length -= count
+		return
+	}
+}
+
+// remove removes up to count elements equal to v, all of them if count is
+// 0, starting from the last one if reverse. It returns how many it removed.
+func (l *List) remove(v string, count int, reverse bool) int {
+	removed := 0
+	n := l.head
+	if reverse {
+		n = l.tail
+	}
+	for n != nil && (count == 0 || removed < count) {
+		next := n.next
+		if reverse {
+			next = n.prev
+		}
+
+		kept := n.elems[:0]
+		if reverse {
+			// Filter from the end so the last matches go first
+			keep := make([]bool, len(n.elems))
+			for j := len(n.elems) - 1; j >= 0; j-- {
+				if n.elems[j] == v && (count == 0 || removed < count) {
+					removed++
+					continue
+				}
+				keep[j] = true
+			}
+			for j, e := range n.elems {
+				if keep[j] {
+					kept = append(kept, e)
+				}
+			}
+		} else {
+			for _, e := range n.elems {
+				if e == v && (count == 0 || removed < count) {
+					removed++
+					continue
+				}
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) < len(n.elems) {
+			clear(n.elems[len(kept):])
+			l.length -= len(n.elems) - len(kept)
+			n.elems = kept
+			if len(n.elems) == 0 {
+				l.unlink(n)
+			} else {
+				l.merge(n, reverse)
+			}
+		}
+		n = next
+	}
+	return removed
+}
+
+// merge joins n, when less than half full, with the neighbour remove
+// already went through, prev or when reverse next, if both fit in one node.
+// This keeps deletions from leaving long chains of nearly empty nodes.
+func (l *List) merge(n *listNode, reverse bool) {
+	if len(n.elems) > listNodeSize/2 {
+		return
+	}
+	if p := n.prev; !reverse && p != nil && len(p.elems)+len(n.elems) <= listNodeSize {
+		p.elems = append(p.elems, n.elems...)
+		l.unlink(n)
+	} else if next := n.next; reverse && next != nil && len(n.elems)+len(next.elems) <= listNodeSize {
+		next.elems = append(n.elems, next.elems...)
+		l.unlink(n)
+	}
+}
+
+// linkAfter links n after prev, or at the front if prev is nil
+func (l *List) linkAfter(prev, n *listNode) {
+	n.prev = prev
+	if prev == nil {
+		n.next = l.head
+		l.head = n
+	} else {
+		n.next = prev.next
+		prev.next = n
+	}
+	if n.next == nil {
+		l.tail = n
+	} else {
+		n.next.prev = n
+	}
+}
+
+// unlink removes n from the list, without changing the length
+func (l *List) unlink(n *listNode) {
+	if n.prev == nil {
+		l.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"math"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/glob"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// scanIterationsPerCount bounds the buckets a SCAN call visits, as a multiple
+// of COUNT, so that a sparse table doesn't make one call walk all of it
+const scanIterationsPerCount = 10
+
+// TypeNames are the names TYPE replies with and SCAN's TYPE option accepts
+var TypeNames = []string{"string", "list", "set", "zset", "hash", "stream"}
+
+// scanIterations is the number of buckets a scan for count items visits at
+// most
+func scanIterations(count int) int {
+	if count > math.MaxInt/scanIterationsPerCount {
+		return math.MaxInt
+	}
+	return count * scanIterationsPerCount
+}
+
+// typeName returns the name of the type of val, as TYPE replies
+func typeName(val interface{}) string {
+	switch val.(type) {
+	case []byte:
+		return "string"
+	case *List:
+		return "list"
+	case *SortedSet:
+		return "zset"
+	case *Hash:
+		return "hash"
+	default:
+		return "none"
+	}
+}
+
+// Scan returns the next cursor of a walk over the keyspace started at cursor
+// 0, and about count keys matching pattern and, unless empty, of type typ.
+// A walk returns every key that exists for its whole duration at least once,
+// even if the keyspace grows or shrinks meanwhile, and may return some twice.
+// The walk is complete when the cursor returned is 0.
+func (s *MemoryStore) Scan(cursor uint64, count int, pattern, typ string) (uint64, []string, error) {
+	// The write lock, as expired keys are deleted along the way
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	iterations := scanIterations(count)
+	for {
+		cursor = s.data.scan(cursor, func(key string, _ interface{}) {
+			keys = append(keys, key)
+		})
+		iterations--
+		if cursor == 0 || iterations == 0 || len(keys) >= count {
+			break
+		}
+	}
+
+	// Like Redis, filter after collecting, so that COUNT bounds the work done
+	// rather than the keys returned
+	matched := keys[:0]
+	for _, key := range keys {
+		if pattern != "*" && !glob.Match(pattern, key) {
+			continue
+		}
+		val, ok := s.lookup(key)
+		if !ok {
+			continue
+		}
+		if typ != "" && typeName(val) != typ {
+			continue
+		}
+		matched = append(matched, key)
+	}
+	return cursor, matched, nil
+}
+
+// ZScan is Scan over the members of the sorted set at key. A missing key is
+// an empty set.
+func (s *MemoryStore) ZScan(key string, cursor uint64, count int, pattern string) (uint64, []types.ScoreMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists := s.lookup(key)
+	if !exists {
+		return 0, nil, nil
+	}
+	zset, ok := val.(*SortedSet)
+	if !ok {
+		return 0, nil, errs.ErrWrongType
+	}
+
+	var members []types.ScoreMember
+	iterations := scanIterations(count)
+	for {
+		cursor = zset.dict.scan(cursor, func(member string, score float64) {
+			members = append(members, types.ScoreMember{Score: score, Member: member})
+		})
+		iterations--
+		if cursor == 0 || iterations == 0 || len(members) >= count {
+			break
+		}
+	}
+
+	if pattern == "*" {
+		return cursor, members, nil
+	}
+	matched := members[:0]
+	for _, sm := range members {
+		if glob.Match(pattern, sm.Member) {
+			matched = append(matched, sm)
+		}
+	}
+	return cursor, matched, nil
+}
+
+// HScan is Scan over the fields of the hash at key, returned with their
+// values. A missing key is an empty hash.
+func (s *MemoryStore) HScan(key string, cursor uint64, count int, pattern string) (uint64, []types.MapEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.lookupHash(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	if h != nil {
+		h = s.expireFields(key, h, nil)
+	}
+	if h == nil {
+		return 0, nil, nil
+	}
+
+	// Fields still due after expireFields are skipped
+	now := s.clock.Now()
+	var entries []types.MapEntry
+	iterations := scanIterations(count)
+	for {
+		cursor = h.fields.scan(cursor, func(field, val string) {
+			if !h.expires.expired(field, now) {
+				entries = append(entries, types.MapEntry{Key: field, Value: val})
+			}
+		})
+		iterations--
+		if cursor == 0 || iterations == 0 || len(entries) >= count {
+			break
+		}
+	}
+
+	if pattern == "*" {
+		return cursor, entries, nil
+	}
+	matched := entries[:0]
+	for _, e := range entries {
+		if glob.Match(pattern, e.Key.(string)) {
+			matched = append(matched, e)
+		}
+	}
+	return cursor, matched, nil
+}
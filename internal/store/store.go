@@ -10,13 +10,78 @@ import (
 // Store defines the interface for the Redis data store
 type Store interface {
 	Get(key string) (interface{}, error)
-	Set(key string, value interface{}, opts *options.SetOptions) (interface{}, error)
-	Del(key string) error
-	Expire(key string, ttl time.Duration, opts *options.ExpireOptions) error
-	TTL(key string) (int, error)
+	Set(key string, value string, opts *options.SetOptions) (interface{}, bool, error)
+	Del(key string) (bool, error)
+	Expire(key string, at time.Time, opts *options.ExpireOptions) (bool, error)
+	ExpireTime(key string) (at time.Time, exists bool, err error)
+	Persist(key string) (bool, error)
+	// Now returns the time keys expire by
+	Now() time.Time
 	Keys(pattern string) ([]string, error)
+	Scan(cursor uint64, count int, pattern, typ string) (uint64, []string, error)
+
+	// String operations
+	IncrBy(key string, delta int64) (int64, error)
+	IncrByFloat(key string, delta float64) (string, error)
+	Append(key, value string) (int, error)
+	SetRange(key string, offset int64, value string) (int, error)
+	MGet(keys ...string) []interface{}
+	MSet(keyValues []string, nx bool) (bool, error)
+	GetDel(key string) (interface{}, error)
+	GetEx(key string, opts *options.GetExOptions) (interface{}, error)
+
+	// Bitmap operations
+	SetBit(key string, offset int64, on bool) (int, error)
+	GetBit(key string, offset int64) (int, error)
+	BitCount(key string, r *options.BitRange) (int64, error)
+	BitPos(key string, bit int, r *options.BitRange) (int64, error)
+	BitOp(op, dest string, keys []string) (int, error)
+	BitField(key string, ops []options.BitFieldOp) ([]interface{}, error)
+
+	// Hash operations
+	HSet(key string, fieldValues []string) (int, error)
+	HSetNX(key, field, value string) (bool, error)
+	HGet(key, field string) (interface{}, error)
+	HMGet(key string, fields []string) ([]interface{}, error)
+	HDel(key string, fields []string) (int, error)
+	HLen(key string) (int, error)
+	HGetAll(key string) (types.Map, error)
+	HIncrBy(key, field string, delta int64) (int64, error)
+	HIncrByFloat(key, field string, delta float64) (string, error)
+	HRandField(key string, count int64) ([]types.MapEntry, error)
+	HScan(key string, cursor uint64, count int, pattern string) (uint64, []types.MapEntry, error)
+	HExpire(key string, at time.Time, opts *options.ExpireOptions, fields []string) ([]int, error)
+	HExpireTime(key string, fields []string) ([]int64, error)
+	HPersist(key string, fields []string) ([]int, error)
+	HGetEx(key string, fields []string, opts *options.GetExOptions) ([]interface{}, error)
+	HSetEx(key string, fieldValues []string, opts *options.HSetExOptions) (bool, error)
+
+	// List operations
+	Push(key string, elements []string, front, existing bool) (int, error)
+	Pop(key string, front bool, count int) ([]string, error)
+	LMPop(keys []string, front bool, count int) (string, []string, error)
+	LMove(src, dst string, srcFront, dstFront bool) (interface{}, error)
+	LLen(key string) (int, error)
+	LRange(key string, start, stop int64) ([]string, error)
+	LIndex(key string, index int64) (interface{}, error)
+	LSet(key string, index int64, element string) error
+	LInsert(key string, before bool, pivot, element string) (int, error)
+	LRem(key string, count int64, element string) (int, error)
+	LTrim(key string, start, stop int64) error
+	LPos(key, element string, rank, count, maxLen int64) ([]int64, error)
 
 	// Sorted Set operations
 	ZAdd(key string, members []types.ScoreMember, opts *options.ZAddOptions) (interface{}, error)
 	ZRange(key string, start, stop interface{}, opts *options.ZRangeOptions) ([]interface{}, error)
+	ZScan(key string, cursor uint64, count int, pattern string) (uint64, []types.ScoreMember, error)
+	ZPop(key string, max bool, count int) ([]types.ScoreMember, error)
+	ZMPop(keys []string, max bool, count int) (string, []types.ScoreMember, error)
+
+	// Deletes keys whose TTL elapsed, spending about timeLimit at most
+	ActiveExpireCycle(timeLimit time.Duration) int
+
+	// Optimistic locking for transactions
+	Watch(w *Watcher, keys ...string)
+	Unwatch(w *Watcher)
+	Modified(w *Watcher) bool
 }
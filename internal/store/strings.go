@@ -0,0 +1,296 @@
+package store
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hardikphalet/go-redis/internal/commands/options"
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/notify"
+)
+
+// MaxStringLength is the largest string a command may build, Redis's
+// default proto-max-bulk-len
+const MaxStringLength = 512 * 1024 * 1024
+
+var errStringTooLong = errs.Errorf("string exceeds maximum allowed size (proto-max-bulk-len)")
+
+// ParseInt parses s the way Redis's string2ll does, which is stricter than
+// strconv: no sign but a leading '-', no leading zeros and no spaces
+func ParseInt(s string) (int64, bool) {
+	// 20 bytes fit the longest int64, "-9223372036854775808"
+	if s == "" || len(s) > 20 {
+		return 0, false
+	}
+	if s == "0" {
+		return 0, true
+	}
+	digits := s
+	if digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if digits == "" || digits[0] < '1' || digits[0] > '9' {
+		return 0, false
+	}
+	for i := 1; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+// ParseFloat parses s as a float the way INCRBYFLOAT does, rejecting NaN
+func ParseFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// lookupString is lookup for commands that only work on strings. The bytes
+// returned are the stored ones, which the caller may modify in place. The
+// caller must hold the write lock.
+func (s *MemoryStore) lookupString(key string) ([]byte, bool, error) {
+	val, exists := s.lookup(key)
+	if !exists {
+		return nil, false, nil
+	}
+	str, ok := val.([]byte)
+	if !ok {
+		return nil, false, errs.ErrWrongType
+	}
+	return str, true, nil
+}
+
+// setString stores val at key, keeping any timeout. The caller must hold the
+// write lock, and touch the key and notify the event afterwards.
+func (s *MemoryStore) setString(key string, val []byte) {
+	if s.data.set(key, val) {
+		s.notify(notify.New, "new", key)
+	}
+}
+
+// IncrBy adds delta to the integer stored at key, a missing key counting as
+// 0, and returns the result
+func (s *MemoryStore) IncrBy(key string, delta int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if exists {
+		var ok bool
+		if n, ok = ParseInt(string(cur)); !ok {
+			return 0, errs.ErrNotInteger
+		}
+	}
+	if (delta < 0 && n < 0 && delta < math.MinInt64-n) ||
+		(delta > 0 && n > 0 && delta > math.MaxInt64-n) {
+		return 0, errs.Errorf("increment or decrement would overflow")
+	}
+
+	n += delta
+	s.setString(key, strconv.AppendInt(nil, n, 10))
+	s.touch(key)
+	s.notify(notify.String, "incrby", key)
+	return n, nil
+}
+
+// IncrByFloat adds delta to the number stored at key, a missing key counting
+// as 0, and returns the result as it is stored
+func (s *MemoryStore) IncrByFloat(key string, delta float64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, exists, err := s.lookupString(key)
+	if err != nil {
+		return "", err
+	}
+	var f float64
+	if exists {
+		var ok bool
+		if f, ok = ParseFloat(string(cur)); !ok {
+			return "", errs.ErrNotFloat
+		}
+	}
+	f += delta
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errs.Errorf("increment would produce NaN or Infinity")
+	}
+
+	// Like Redis, never use an exponent, so that the result reads back as
+	// the same number for clients that only parse plain decimals
+	result := strconv.FormatFloat(f, 'f', -1, 64)
+	s.setString(key, []byte(result))
+	s.touch(key)
+	s.notify(notify.String, "incrbyfloat", key)
+	return result, nil
+}
+
+// Append appends value to the string at key, creating it if missing, and
+// returns its new length
+func (s *MemoryStore) Append(key, value string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, _, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(cur)+len(value) > MaxStringLength {
+		return 0, errStringTooLong
+	}
+
+	cur = append(cur, value...)
+	s.setString(key, cur)
+	s.touch(key)
+	s.notify(notify.String, "append", key)
+	return len(cur), nil
+}
+
+// SetRange overwrites the string at key from offset with value, padding it
+// with zero bytes if it is shorter, and returns its new length. A missing key
+// is an empty string, which an empty value doesn't create.
+func (s *MemoryStore) SetRange(key string, offset int64, value string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, _, err := s.lookupString(key)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return len(cur), nil
+	}
+	if offset > int64(MaxStringLength-len(value)) {
+		return 0, errStringTooLong
+	}
+
+	cur = grow(cur, int(offset)+len(value))
+	copy(cur[offset:], value)
+	s.setString(key, cur)
+	s.touch(key)
+	s.notify(notify.String, "setrange", key)
+	return len(cur), nil
+}
+
+// MGet returns the values of keys, with nil for keys that are missing or
+// don't hold a string
+func (s *MemoryStore) MGet(keys ...string) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		val, exists := s.lookup(key)
+		if !exists {
+			s.notify(notify.KeyMiss, "keymiss", key)
+			continue
+		}
+		if str, ok := val.([]byte); ok {
+			values[i] = string(str)
+		}
+	}
+	return values
+}
+
+// MSet stores each key of keyValues, which alternates keys and values, and
+// removes their timeouts. With nx nothing is stored if any of the keys
+// exists. It reports whether the values were stored.
+func (s *MemoryStore) MSet(keyValues []string, nx bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if nx {
+		for i := 0; i < len(keyValues); i += 2 {
+			if _, exists := s.lookup(keyValues[i]); exists {
+				return false, nil
+			}
+		}
+	}
+
+	for i := 0; i < len(keyValues); i += 2 {
+		key := keyValues[i]
+		if s.data.set(key, []byte(keyValues[i+1])) {
+			s.notify(notify.New, "new", key)
+		}
+		delete(s.expires, key)
+		s.touch(key)
+		s.notify(notify.String, "set", key)
+	}
+	return true, nil
+}
+
+// GetDel deletes the string at key and returns it, or nil if it was missing
+func (s *MemoryStore) GetDel(key string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists, err := s.lookupString(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		return nil, nil
+	}
+
+	s.data.delete(key)
+	delete(s.expires, key)
+	s.touch(key)
+	s.notify(notify.Generic, "del", key)
+	return string(val), nil
+}
+
+// GetEx returns the string at key, or nil if it is missing, and sets or
+// removes its timeout as opts says. A time already past deletes the key.
+func (s *MemoryStore) GetEx(key string, opts *options.GetExOptions) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists, err := s.lookupString(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		s.notify(notify.KeyMiss, "keymiss", key)
+		return nil, nil
+	}
+
+	switch {
+	case opts == nil || opts.ExpiryType == "":
+	case opts.IsPERSIST():
+		if _, ok := s.expires[key]; ok {
+			delete(s.expires, key)
+			s.touch(key)
+			s.notify(notify.Generic, "persist", key)
+		}
+	default:
+		now := s.clock.Now()
+		if at := opts.ExpiryTime(now); at.After(now) {
+			s.expires[key] = at
+			s.touch(key)
+			s.notify(notify.Generic, "expire", key)
+		} else {
+			s.data.delete(key)
+			delete(s.expires, key)
+			s.touch(key)
+			s.notify(notify.Generic, "del", key)
+		}
+	}
+	return string(val), nil
+}
+
+// grow returns b extended with zero bytes to at least n bytes
+func grow(b []byte, n int) []byte {
+	if n <= len(b) {
+		return b
+	}
+	return append(b, make([]byte, n-len(b))...)
+}
@@ -0,0 +1,88 @@
+package store
+
+// Watcher holds the keys a connection watches ahead of a transaction, and
+// whether any of them has been modified since. Its state is guarded by the
+// store's lock.
+type Watcher struct {
+	// keys maps each watched key to whether it had already expired when it
+	// was watched. Deleting such a key isn't a change, as in Redis.
+	keys  map[string]bool
+	dirty bool
+}
+
+func NewWatcher() *Watcher {
+	return &Watcher{keys: make(map[string]bool)}
+}
+
+// Watch adds keys to the keys w watches
+func (s *MemoryStore) Watch(w *Watcher, keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if _, watched := w.keys[key]; watched {
+			continue
+		}
+		w.keys[key] = s.isExpired(key)
+
+		watchers, ok := s.watchers[key]
+		if !ok {
+			watchers = make(map[*Watcher]struct{})
+			s.watchers[key] = watchers
+		}
+		watchers[w] = struct{}{}
+	}
+}
+
+// Unwatch forgets every key w watches and clears its dirty state
+func (s *MemoryStore) Unwatch(w *Watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range w.keys {
+		delete(s.watchers[key], w)
+		if len(s.watchers[key]) == 0 {
+			delete(s.watchers, key)
+		}
+	}
+	clear(w.keys)
+	w.dirty = false
+}
+
+// Modified reports whether a key w watches was modified since it was
+// watched. A key that expired in the meantime counts as modified even if it
+// hasn't been deleted yet.
+func (s *MemoryStore) Modified(w *Watcher) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if w.dirty {
+		return true
+	}
+	for key, expired := range w.keys {
+		if !expired && s.isExpired(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// touch marks the watchers of key dirty. Every write path calls it after
+// modifying key.
+func (s *MemoryStore) touch(key string) {
+	for w := range s.watchers[key] {
+		w.dirty = true
+	}
+}
+
+// touchExpired is touch for a key deleted because it expired. Watchers that
+// saw the key already expired don't consider its deletion a change.
+func (s *MemoryStore) touchExpired(key string) {
+	for w := range s.watchers[key] {
+		if w.keys[key] {
+			w.keys[key] = false
+		} else {
+			w.dirty = true
+		}
+	}
+}
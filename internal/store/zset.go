@@ -0,0 +1,91 @@
+package store
+
+import (
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/notify"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// len returns the number of members
+func (s *SortedSet) len() int {
+	return s.dict.len()
+}
+
+// remove removes member, which must be in the sorted set
+func (s *SortedSet) remove(member string, score float64) {
+	s.dict.delete(member)
+	s.sl.delete(score, member)
+}
+
+// lookupZSet returns the sorted set at key, or nil if it is missing. The
+// caller must hold the write lock.
+func (s *MemoryStore) lookupZSet(key string) (*SortedSet, error) {
+	val, exists := s.lookup(key)
+	if !exists {
+		return nil, nil
+	}
+	zset, ok := val.(*SortedSet)
+	if !ok {
+		return nil, errs.ErrWrongType
+	}
+	return zset, nil
+}
+
+// ZPop removes up to count members with the lowest scores from the sorted
+// set at key, or the highest ones if max, and the key if none is left. It
+// returns them in the order popped, or nil if the sorted set is missing.
+func (s *MemoryStore) ZPop(key string, max bool, count int) ([]types.ScoreMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zset, err := s.lookupZSet(key)
+	if err != nil || zset == nil {
+		return nil, err
+	}
+	return s.popZSet(key, zset, max, count), nil
+}
+
+// popZSet pops up to count members from the sorted set at key, notifying
+// the pop and the deletion of the key if it is left empty
+func (s *MemoryStore) popZSet(key string, zset *SortedSet, max bool, count int) []types.ScoreMember {
+	count = min(count, zset.len())
+	popped := make([]types.ScoreMember, count)
+	for i := range popped {
+		n := zset.sl.head.forward[0]
+		if max {
+			n = zset.sl.tail
+		}
+		popped[i] = types.ScoreMember{Score: n.score, Member: n.member}
+		zset.remove(n.member, n.score)
+	}
+	if count > 0 {
+		s.touch(key)
+		event := "zpopmin"
+		if max {
+			event = "zpopmax"
+		}
+		s.notify(notify.ZSet, event, key)
+		s.deleteIfEmpty(key, zset)
+	}
+	return popped
+}
+
+// ZMPop pops up to count members from the first sorted set of keys that
+// isn't empty, the lowest-scoring ones or if max the highest. It returns the
+// key popped from and the members, or nil members if all of the sorted sets
+// are missing.
+func (s *MemoryStore) ZMPop(keys []string, max bool, count int) (string, []types.ScoreMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		zset, err := s.lookupZSet(key)
+		if err != nil {
+			return "", nil, err
+		}
+		if zset != nil {
+			return key, s.popZSet(key, zset, max, count), nil
+		}
+	}
+	return "", nil, nil
+}
@@ -2,3 +2,38 @@ package types
 
 // SimpleString represents a RESP Simple String that should be written with a + prefix
 type SimpleString string
+
+// Map represents an ordered RESP map. It is written as a RESP3 map, and as a
+// flat array of alternating keys and values only to RESP2 clients.
+type Map []MapEntry
+
+// MapEntry is a single key-value pair of a Map
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Set represents an unordered RESP set. It is written as a RESP3 set, and as
+// an array only to RESP2 clients.
+type Set []interface{}
+
+// Pairs represents a flat list of alternating elements and values, such as a
+// sorted set's members and scores. It is written as an array of two-element
+// arrays to RESP3 clients, and as a flat array to RESP2 clients.
+type Pairs []interface{}
+
+// BigNumber represents a RESP3 big number. It is written as a bulk string to
+// RESP2 clients.
+type BigNumber string
+
+// VerbatimString represents a RESP3 verbatim string with a three character
+// format such as "txt" or "mkd". It is written as a bulk string to RESP2
+// clients.
+type VerbatimString struct {
+	Format string
+	Text   string
+}
+
+// Push represents an out-of-band RESP3 push message. It is written as an
+// array to RESP2 clients.
+type Push []interface{}
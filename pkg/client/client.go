@@ -0,0 +1,139 @@
+// Package client is a Go client for the server.
+//
+// A Client is safe for concurrent use and keeps a pool of connections:
+//
+//	c := client.New(client.Options{Addr: "localhost:6379"})
+//	defer c.Close()
+//
+//	if err := c.Set(ctx, "greeting", "hello", 0).Err(); err != nil {
+//		return err
+//	}
+//	greeting, err := c.Get(ctx, "greeting").Result()
+//	if err == client.Nil {
+//		// the key doesn't exist
+//	}
+//
+// Every command has a typed helper returning a command whose Result holds the
+// decoded reply; Do sends any other command. Error replies from the server
+// are returned as *Error, which carries the prefix (such as WRONGTYPE) to
+// tell errors apart.
+//
+// Pipeline batches commands into a single round trip, and TxPipeline also
+// wraps them in MULTI/EXEC. Subscribe and PSubscribe return a PubSub, which
+// receives published messages on a connection of its own.
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+// Client is a pool of connections to a server
+type Client struct {
+	cmdable
+	opts *Options
+	pool *pool
+}
+
+// New returns a client for the server described by opts. Connections are
+// dialed lazily, when the first commands are sent.
+func New(opts Options) *Client {
+	c := &Client{opts: opts.withDefaults()}
+	c.pool = newPool(c.opts)
+	c.cmdable = c.Process
+	return c
+}
+
+// Options returns the client's options, with defaults filled in
+func (c *Client) Options() Options {
+	return *c.opts
+}
+
+// Do sends a command built from args and returns its decoded reply
+func (c *Client) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(args...)
+	c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process sends cmd and waits for its reply, retrying on a new connection
+// when the connection fails. It returns the command's error.
+func (c *Client) Process(ctx context.Context, cmd Cmder) error {
+	err := c.withRetries(ctx, func(cn *conn) error {
+		return cn.roundTrip(ctx, c.opts, []Cmder{cmd})
+	})
+	if err != nil {
+		cmd.setErr(err)
+	}
+	return cmd.Err()
+}
+
+// withRetries runs fn on a pooled connection, retrying with a growing
+// backoff while it fails with a network error
+func (c *Client) withRetries(ctx context.Context, fn func(cn *conn) error) error {
+	var err error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.retryBackoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		var cn *conn
+		cn, err = c.pool.Get(ctx)
+		if err == nil {
+			err = fn(cn)
+			c.pool.Put(cn)
+		}
+		if err == nil || !shouldRetry(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// retryBackoff returns how long to wait before the given attempt
+func (c *Client) retryBackoff(attempt int) time.Duration {
+	backoff := c.opts.MinRetryBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > c.opts.MaxRetryBackoff {
+		return c.opts.MaxRetryBackoff
+	}
+	return backoff
+}
+
+// shouldRetry reports whether err is a connection failure worth retrying on
+// a new connection. Timeouts aren't retried, since the server may still be
+// executing the command.
+func shouldRetry(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && !netErr.Timeout()
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PoolStats returns the connection pool's counters
+func (c *Client) PoolStats() PoolStats {
+	return c.pool.Stats()
+}
+
+// Close closes the client's connections. It is rare to Close a Client, as it
+// is meant to be long-lived and shared.
+func (c *Client) Close() error {
+	return c.pool.Close()
+}
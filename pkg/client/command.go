@@ -0,0 +1,901 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/resp"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Cmder is a command together with its reply. Commands are filled in when
+// their reply arrives, right away for a Client and on Exec for a Pipeline.
+type Cmder interface {
+	// Name returns the lower-case command name
+	Name() string
+	// Args returns the command name and its arguments
+	Args() []interface{}
+	// Err returns the error reply or the error that prevented the command
+	// from running
+	Err() error
+
+	setErr(err error)
+	argStrings() []string
+	readReply(reply interface{}) error
+	blockTimeout() (time.Duration, bool)
+}
+
+type baseCmd struct {
+	args []interface{}
+	err  error
+	// block is how long a blocking command waits on the server, 0 meaning
+	// forever, if blocking is set
+	block    time.Duration
+	blocking bool
+}
+
+func (c *baseCmd) Name() string {
+	if len(c.args) == 0 {
+		return ""
+	}
+	return strings.ToLower(argString(c.args[0]))
+}
+
+func (c *baseCmd) Args() []interface{} {
+	return c.args
+}
+
+func (c *baseCmd) Err() error {
+	return c.err
+}
+
+func (c *baseCmd) setErr(err error) {
+	c.err = err
+}
+
+func (c *baseCmd) blockTimeout() (time.Duration, bool) {
+	return c.block, c.blocking
+}
+
+// setBlockTimeout marks a blocking command, whose reply may take up to
+// timeout to arrive
+func (c *baseCmd) setBlockTimeout(timeout time.Duration) {
+	c.block, c.blocking = timeout, true
+}
+
+func (c *baseCmd) argStrings() []string {
+	strs := make([]string, len(c.args))
+	for i, arg := range c.args {
+		strs[i] = argString(arg)
+	}
+	return strs
+}
+
+// argString converts a command argument to the string sent to the server
+func argString(arg interface{}) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return resp.FormatFloat(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// unexpectedReply is the error for a reply the command can't decode
+func unexpectedReply(reply interface{}) error {
+	return fmt.Errorf("client: unexpected reply type %T", reply)
+}
+
+// Cmd is a command whose reply is kept as decoded, for use with Do
+type Cmd struct {
+	baseCmd
+	val interface{}
+}
+
+func NewCmd(args ...interface{}) *Cmd {
+	return &Cmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *Cmd) readReply(reply interface{}) error {
+	c.val = reply
+	if reply == nil {
+		return Nil
+	}
+	return nil
+}
+
+// Val returns the reply
+func (c *Cmd) Val() interface{} {
+	return c.val
+}
+
+// Result returns the reply and the error
+func (c *Cmd) Result() (interface{}, error) {
+	return c.val, c.err
+}
+
+// StatusCmd is a command replying with a status such as OK
+type StatusCmd struct {
+	baseCmd
+	val string
+}
+
+func NewStatusCmd(args ...interface{}) *StatusCmd {
+	return &StatusCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *StatusCmd) readReply(reply interface{}) error {
+	switch v := reply.(type) {
+	case nil:
+		return Nil
+	case types.SimpleString:
+		c.val = string(v)
+	case string:
+		c.val = v
+	default:
+		return unexpectedReply(reply)
+	}
+	return nil
+}
+
+// Val returns the status
+func (c *StatusCmd) Val() string {
+	return c.val
+}
+
+// Result returns the status and the error
+func (c *StatusCmd) Result() (string, error) {
+	return c.val, c.err
+}
+
+// StringCmd is a command replying with a string. A nil reply is reported as
+// the Nil error.
+type StringCmd struct {
+	baseCmd
+	val string
+}
+
+func NewStringCmd(args ...interface{}) *StringCmd {
+	return &StringCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *StringCmd) readReply(reply interface{}) error {
+	switch v := reply.(type) {
+	case nil:
+		return Nil
+	case string:
+		c.val = v
+	case types.SimpleString:
+		c.val = string(v)
+	case types.VerbatimString:
+		c.val = v.Text
+	case int64:
+		c.val = strconv.FormatInt(v, 10)
+	case float64:
+		c.val = resp.FormatFloat(v)
+	default:
+		return unexpectedReply(reply)
+	}
+	return nil
+}
+
+// Val returns the string
+func (c *StringCmd) Val() string {
+	return c.val
+}
+
+// Result returns the string and the error
+func (c *StringCmd) Result() (string, error) {
+	return c.val, c.err
+}
+
+// Int64 parses the string as an integer
+func (c *StringCmd) Int64() (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return strconv.ParseInt(c.val, 10, 64)
+}
+
+// Float64 parses the string as a float
+func (c *StringCmd) Float64() (float64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return strconv.ParseFloat(c.val, 64)
+}
+
+// IntCmd is a command replying with an integer
+type IntCmd struct {
+	baseCmd
+	val int64
+}
+
+func NewIntCmd(args ...interface{}) *IntCmd {
+	return &IntCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *IntCmd) readReply(reply interface{}) error {
+	switch v := reply.(type) {
+	case nil:
+		return Nil
+	case int64:
+		c.val = v
+	default:
+		return unexpectedReply(reply)
+	}
+	return nil
+}
+
+// Val returns the integer
+func (c *IntCmd) Val() int64 {
+	return c.val
+}
+
+// Result returns the integer and the error
+func (c *IntCmd) Result() (int64, error) {
+	return c.val, c.err
+}
+
+// BoolCmd is a command replying with 1 or 0, or with OK or nil, such as
+// EXPIRE or SET NX
+type BoolCmd struct {
+	baseCmd
+	val bool
+}
+
+func NewBoolCmd(args ...interface{}) *BoolCmd {
+	return &BoolCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *BoolCmd) readReply(reply interface{}) error {
+	switch v := reply.(type) {
+	case nil:
+		c.val = false
+	case int64:
+		c.val = v == 1
+	case bool:
+		c.val = v
+	case types.SimpleString:
+		c.val = v == "OK"
+	default:
+		return unexpectedReply(reply)
+	}
+	return nil
+}
+
+// Val returns the boolean
+func (c *BoolCmd) Val() bool {
+	return c.val
+}
+
+// Result returns the boolean and the error
+func (c *BoolCmd) Result() (bool, error) {
+	return c.val, c.err
+}
+
+// FloatCmd is a command replying with a floating point number. A nil reply
+// is reported as the Nil error.
+type FloatCmd struct {
+	baseCmd
+	val float64
+}
+
+func NewFloatCmd(args ...interface{}) *FloatCmd {
+	return &FloatCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *FloatCmd) readReply(reply interface{}) error {
+	var err error
+	c.val, err = toFloat(reply)
+	return err
+}
+
+// Val returns the number
+func (c *FloatCmd) Val() float64 {
+	return c.val
+}
+
+// Result returns the number and the error
+func (c *FloatCmd) Result() (float64, error) {
+	return c.val, c.err
+}
+
+// toFloat decodes a double, which RESP2 sends as a bulk string
+func toFloat(reply interface{}) (float64, error) {
+	switch v := reply.(type) {
+	case nil:
+		return 0, Nil
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, unexpectedReply(reply)
+	}
+}
+
+// DurationCmd is a command replying with a time to live. The negative
+// replies keep their meaning: -1 for a key without expiry and -2 for a
+// missing key.
+type DurationCmd struct {
+	baseCmd
+	val       time.Duration
+	precision time.Duration
+}
+
+func NewDurationCmd(precision time.Duration, args ...interface{}) *DurationCmd {
+	return &DurationCmd{baseCmd: baseCmd{args: args}, precision: precision}
+}
+
+func (c *DurationCmd) readReply(reply interface{}) error {
+	n, ok := reply.(int64)
+	if !ok {
+		return unexpectedReply(reply)
+	}
+	if n < 0 {
+		c.val = time.Duration(n)
+	} else {
+		c.val = time.Duration(n) * c.precision
+	}
+	return nil
+}
+
+// Val returns the duration
+func (c *DurationCmd) Val() time.Duration {
+	return c.val
+}
+
+// Result returns the duration and the error
+func (c *DurationCmd) Result() (time.Duration, error) {
+	return c.val, c.err
+}
+
+// StringSliceCmd is a command replying with an array of strings
+type StringSliceCmd struct {
+	baseCmd
+	val []string
+}
+
+func NewStringSliceCmd(args ...interface{}) *StringSliceCmd {
+	return &StringSliceCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *StringSliceCmd) readReply(reply interface{}) error {
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+
+	// Pairs, such as HRANDFIELD's fields and values, arrive nested as
+	// [field, value] arrays on RESP3 and are flattened
+	var flat []interface{}
+	for i, el := range elements {
+		if pair, ok := el.([]interface{}); ok && len(pair) == 2 {
+			if flat == nil {
+				flat = append(make([]interface{}, 0, len(elements)*2), elements[:i]...)
+			}
+			flat = append(flat, pair...)
+		} else if flat != nil {
+			flat = append(flat, el)
+		}
+	}
+	if flat != nil {
+		elements = flat
+	}
+
+	c.val = make([]string, len(elements))
+	for i, el := range elements {
+		switch v := el.(type) {
+		case nil:
+		case string:
+			c.val[i] = v
+		case types.SimpleString:
+			c.val[i] = string(v)
+		default:
+			c.val[i] = argString(v)
+		}
+	}
+	return nil
+}
+
+// Val returns the strings
+func (c *StringSliceCmd) Val() []string {
+	return c.val
+}
+
+// Result returns the strings and the error
+func (c *StringSliceCmd) Result() ([]string, error) {
+	return c.val, c.err
+}
+
+// SliceCmd is a command replying with an array of values that may be nil,
+// such as MGET
+type SliceCmd struct {
+	baseCmd
+	val []interface{}
+}
+
+func NewSliceCmd(args ...interface{}) *SliceCmd {
+	return &SliceCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *SliceCmd) readReply(reply interface{}) error {
+	var err error
+	c.val, err = toSlice(reply)
+	return err
+}
+
+// Val returns the values
+func (c *SliceCmd) Val() []interface{} {
+	return c.val
+}
+
+// Result returns the values and the error
+func (c *SliceCmd) Result() ([]interface{}, error) {
+	return c.val, c.err
+}
+
+// ScanCmd is a command replying with a cursor and a page of items, such as
+// SCAN
+type ScanCmd struct {
+	baseCmd
+	page   []string
+	cursor uint64
+}
+
+func NewScanCmd(args ...interface{}) *ScanCmd {
+	return &ScanCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *ScanCmd) readReply(reply interface{}) error {
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return unexpectedReply(reply)
+	}
+	cursor, ok := elements[0].(string)
+	if !ok {
+		return unexpectedReply(elements[0])
+	}
+	if c.cursor, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+		return err
+	}
+	page := &StringSliceCmd{}
+	if err := page.readReply(elements[1]); err != nil {
+		return err
+	}
+	c.page = page.val
+	return nil
+}
+
+// Val returns the page of items and the cursor to continue from, 0 once the
+// iteration is complete
+func (c *ScanCmd) Val() (page []string, cursor uint64) {
+	return c.page, c.cursor
+}
+
+// Result returns the page of items, the cursor and the error
+func (c *ScanCmd) Result() (page []string, cursor uint64, err error) {
+	return c.page, c.cursor, c.err
+}
+
+// toSlice returns the elements of an array or set reply
+func toSlice(reply interface{}) ([]interface{}, error) {
+	switch v := reply.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case types.Set:
+		return v, nil
+	default:
+		return nil, unexpectedReply(reply)
+	}
+}
+
+// Z is a sorted set member with its score
+type Z struct {
+	Score  float64
+	Member string
+}
+
+// ZSliceCmd is a command replying with sorted set members and their scores
+type ZSliceCmd struct {
+	baseCmd
+	val []Z
+}
+
+func NewZSliceCmd(args ...interface{}) *ZSliceCmd {
+	return &ZSliceCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *ZSliceCmd) readReply(reply interface{}) error {
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+
+	// Pairs arrive either flat, member then score, or nested as
+	// [member, score] arrays
+	c.val = make([]Z, 0, len(elements)/2)
+	for i := 0; i < len(elements); i++ {
+		member, score := elements[i], interface{}(nil)
+		if pair, ok := member.([]interface{}); ok && len(pair) == 2 {
+			member, score = pair[0], pair[1]
+		} else if i+1 < len(elements) {
+			i++
+			score = elements[i]
+		}
+
+		m, ok := member.(string)
+		if !ok {
+			return unexpectedReply(member)
+		}
+		s, err := toFloat(score)
+		if err != nil {
+			return err
+		}
+		c.val = append(c.val, Z{Score: s, Member: m})
+	}
+	return nil
+}
+
+// Val returns the members
+func (c *ZSliceCmd) Val() []Z {
+	return c.val
+}
+
+// Result returns the members and the error
+func (c *ZSliceCmd) Result() ([]Z, error) {
+	return c.val, c.err
+}
+
+// MapStringIntCmd is a command replying with names and counts, such as
+// PUBSUB NUMSUB
+type MapStringIntCmd struct {
+	baseCmd
+	val map[string]int64
+}
+
+func NewMapStringIntCmd(args ...interface{}) *MapStringIntCmd {
+	return &MapStringIntCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *MapStringIntCmd) readReply(reply interface{}) error {
+	var elements []interface{}
+	switch v := reply.(type) {
+	case types.Map:
+		for _, entry := range v {
+			elements = append(elements, entry.Key, entry.Value)
+		}
+	default:
+		var err error
+		if elements, err = toSlice(reply); err != nil {
+			return err
+		}
+	}
+
+	c.val = make(map[string]int64, len(elements)/2)
+	for i := 0; i+1 < len(elements); i += 2 {
+		name, ok := elements[i].(string)
+		if !ok {
+			return unexpectedReply(elements[i])
+		}
+		n, ok := elements[i+1].(int64)
+		if !ok {
+			return unexpectedReply(elements[i+1])
+		}
+		c.val[name] = n
+	}
+	return nil
+}
+
+// Val returns the counts by name
+func (c *MapStringIntCmd) Val() map[string]int64 {
+	return c.val
+}
+
+// Result returns the counts by name and the error
+func (c *MapStringIntCmd) Result() (map[string]int64, error) {
+	return c.val, c.err
+}
+
+// MapStringStringCmd is a command replying with names and values, such as
+// HGETALL
+type MapStringStringCmd struct {
+	baseCmd
+	val map[string]string
+}
+
+func NewMapStringStringCmd(args ...interface{}) *MapStringStringCmd {
+	return &MapStringStringCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *MapStringStringCmd) readReply(reply interface{}) error {
+	var elements []interface{}
+	switch v := reply.(type) {
+	case types.Map:
+		for _, entry := range v {
+			elements = append(elements, entry.Key, entry.Value)
+		}
+	default:
+		var err error
+		if elements, err = toSlice(reply); err != nil {
+			return err
+		}
+	}
+
+	c.val = make(map[string]string, len(elements)/2)
+	for i := 0; i+1 < len(elements); i += 2 {
+		name, ok := elements[i].(string)
+		if !ok {
+			return unexpectedReply(elements[i])
+		}
+		value, ok := elements[i+1].(string)
+		if !ok {
+			return unexpectedReply(elements[i+1])
+		}
+		c.val[name] = value
+	}
+	return nil
+}
+
+// Val returns the values by name
+func (c *MapStringStringCmd) Val() map[string]string {
+	return c.val
+}
+
+// Result returns the values by name and the error
+func (c *MapStringStringCmd) Result() (map[string]string, error) {
+	return c.val, c.err
+}
+
+// IntSliceCmd is a command replying with an array of integers, such as
+// HEXPIRE
+type IntSliceCmd struct {
+	baseCmd
+	val []int64
+}
+
+func NewIntSliceCmd(args ...interface{}) *IntSliceCmd {
+	return &IntSliceCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *IntSliceCmd) readReply(reply interface{}) error {
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	c.val = make([]int64, len(elements))
+	for i, el := range elements {
+		n, ok := el.(int64)
+		if !ok {
+			return unexpectedReply(el)
+		}
+		c.val[i] = n
+	}
+	return nil
+}
+
+// Val returns the integers
+func (c *IntSliceCmd) Val() []int64 {
+	return c.val
+}
+
+// Result returns the integers and the error
+func (c *IntSliceCmd) Result() ([]int64, error) {
+	return c.val, c.err
+}
+
+// KeyValuesCmd is a command replying with a key and values taken from it,
+// such as LMPOP
+type KeyValuesCmd struct {
+	baseCmd
+	key string
+	val []string
+}
+
+func NewKeyValuesCmd(args ...interface{}) *KeyValuesCmd {
+	return &KeyValuesCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *KeyValuesCmd) readReply(reply interface{}) error {
+	if reply == nil {
+		return Nil
+	}
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return unexpectedReply(reply)
+	}
+	key, ok := elements[0].(string)
+	if !ok {
+		return unexpectedReply(elements[0])
+	}
+	values, err := toSlice(elements[1])
+	if err != nil {
+		return err
+	}
+	c.key = key
+	c.val = make([]string, len(values))
+	for i, v := range values {
+		if c.val[i], ok = v.(string); !ok {
+			return unexpectedReply(v)
+		}
+	}
+	return nil
+}
+
+// Val returns the key and the values
+func (c *KeyValuesCmd) Val() (string, []string) {
+	return c.key, c.val
+}
+
+// Result returns the key, the values and the error
+func (c *KeyValuesCmd) Result() (string, []string, error) {
+	return c.key, c.val, c.err
+}
+
+// KeyValueCmd is a command replying with a key and a value taken from it,
+// such as BLPOP
+type KeyValueCmd struct {
+	baseCmd
+	key string
+	val string
+}
+
+func NewKeyValueCmd(args ...interface{}) *KeyValueCmd {
+	return &KeyValueCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *KeyValueCmd) readReply(reply interface{}) error {
+	if reply == nil {
+		return Nil
+	}
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return unexpectedReply(reply)
+	}
+	var ok bool
+	for i, dst := range []*string{&c.key, &c.val} {
+		if *dst, ok = elements[i].(string); !ok {
+			return unexpectedReply(elements[i])
+		}
+	}
+	return nil
+}
+
+// Val returns the key and the value
+func (c *KeyValueCmd) Val() (string, string) {
+	return c.key, c.val
+}
+
+// Result returns the key, the value and the error
+func (c *KeyValueCmd) Result() (string, string, error) {
+	return c.key, c.val, c.err
+}
+
+// ZWithKeyCmd is a command replying with a key and a sorted set member
+// taken from it, such as BZPOPMIN
+type ZWithKeyCmd struct {
+	baseCmd
+	key string
+	val Z
+}
+
+func NewZWithKeyCmd(args ...interface{}) *ZWithKeyCmd {
+	return &ZWithKeyCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *ZWithKeyCmd) readReply(reply interface{}) error {
+	if reply == nil {
+		return Nil
+	}
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 3 {
+		return unexpectedReply(reply)
+	}
+	key, ok := elements[0].(string)
+	if !ok {
+		return unexpectedReply(elements[0])
+	}
+	member, ok := elements[1].(string)
+	if !ok {
+		return unexpectedReply(elements[1])
+	}
+	score, err := toFloat(elements[2])
+	if err != nil {
+		return err
+	}
+	c.key, c.val = key, Z{Score: score, Member: member}
+	return nil
+}
+
+// Val returns the key and the member
+func (c *ZWithKeyCmd) Val() (string, Z) {
+	return c.key, c.val
+}
+
+// Result returns the key, the member and the error
+func (c *ZWithKeyCmd) Result() (string, Z, error) {
+	return c.key, c.val, c.err
+}
+
+// KeyZSliceCmd is a command replying with a key and sorted set members
+// taken from it, such as ZMPOP
+type KeyZSliceCmd struct {
+	baseCmd
+	key string
+	val []Z
+}
+
+func NewKeyZSliceCmd(args ...interface{}) *KeyZSliceCmd {
+	return &KeyZSliceCmd{baseCmd: baseCmd{args: args}}
+}
+
+func (c *KeyZSliceCmd) readReply(reply interface{}) error {
+	if reply == nil {
+		return Nil
+	}
+	elements, err := toSlice(reply)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return unexpectedReply(reply)
+	}
+	key, ok := elements[0].(string)
+	if !ok {
+		return unexpectedReply(elements[0])
+	}
+	members := &ZSliceCmd{}
+	if err := members.readReply(elements[1]); err != nil {
+		return err
+	}
+	c.key, c.val = key, members.val
+	return nil
+}
+
+// Val returns the key and the members
+func (c *KeyZSliceCmd) Val() (string, []Z) {
+	return c.key, c.val
+}
+
+// Result returns the key, the members and the error
+func (c *KeyZSliceCmd) Result() (string, []Z, error) {
+	return c.key, c.val, c.err
+}
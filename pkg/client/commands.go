@@ -0,0 +1,1272 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// KeepTTL, given as the expiration of Set, keeps the key's current TTL
+const KeepTTL = -1
+
+// cmdable holds the typed command helpers. It sends or queues a command,
+// depending on who provides it.
+type cmdable func(ctx context.Context, cmd Cmder) error
+
+// Ping checks the connection; the reply is PONG
+func (c cmdable) Ping(ctx context.Context) *StatusCmd {
+	cmd := NewStatusCmd("ping")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Get returns the value of key, or the Nil error when it doesn't exist
+func (c cmdable) Get(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd("get", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Set sets key to value. A positive expiration sets a TTL, KeepTTL keeps
+// the current one and zero removes it.
+func (c cmdable) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd {
+	args := appendExpiration([]interface{}{"set", key, value}, expiration)
+	cmd := NewStatusCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SetNX sets key only if it doesn't exist, and reports whether it was set
+func (c cmdable) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *BoolCmd {
+	args := appendExpiration([]interface{}{"set", key, value}, expiration)
+	cmd := NewBoolCmd(append(args, "nx")...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SetXX sets key only if it already exists, and reports whether it was set
+func (c cmdable) SetXX(ctx context.Context, key string, value interface{}, expiration time.Duration) *BoolCmd {
+	args := appendExpiration([]interface{}{"set", key, value}, expiration)
+	cmd := NewBoolCmd(append(args, "xx")...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SetArgs are the options of SET
+type SetArgs struct {
+	// Mode is "NX" to only set missing keys or "XX" to only set existing ones
+	Mode string
+	// TTL sets a relative expiration, ExpireAt an absolute one
+	TTL      time.Duration
+	ExpireAt time.Time
+	// KeepTTL keeps the key's current expiration
+	KeepTTL bool
+	// Get makes the command reply with the previous value
+	Get bool
+}
+
+// SetArgs runs SET with all its options. The reply is OK, or the previous
+// value with Get; the Nil error means the key wasn't set or had no value.
+func (c cmdable) SetArgs(ctx context.Context, key string, value interface{}, a SetArgs) *StatusCmd {
+	args := []interface{}{"set", key, value}
+	if a.Mode != "" {
+		args = append(args, a.Mode)
+	}
+	if a.Get {
+		args = append(args, "get")
+	}
+	switch {
+	case a.KeepTTL:
+		args = append(args, "keepttl")
+	case !a.ExpireAt.IsZero():
+		args = append(args, "pxat", a.ExpireAt.UnixMilli())
+	case a.TTL > 0:
+		args = appendExpiration(args, a.TTL)
+	}
+	cmd := NewStatusCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// appendExpiration adds the SET arguments for an expiration, using seconds
+// when they are exact and milliseconds otherwise
+func appendExpiration(args []interface{}, expiration time.Duration) []interface{} {
+	switch {
+	case expiration == KeepTTL:
+		return append(args, "keepttl")
+	case expiration <= 0:
+		return args
+	case expiration < time.Second || expiration%time.Second != 0:
+		return append(args, "px", expiration.Milliseconds())
+	default:
+		return append(args, "ex", int64(expiration/time.Second))
+	}
+}
+
+// SetEx sets key to value with a timeout in whole seconds
+func (c cmdable) SetEx(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd {
+	cmd := NewStatusCmd("setex", key, int64(expiration/time.Second), value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetDel returns the value of key and deletes it, or the Nil error when it
+// doesn't exist
+func (c cmdable) GetDel(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd("getdel", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetEx returns the value of key and sets its timeout like Set: a positive
+// expiration sets it and zero leaves it alone. Use GetPersist to remove it.
+func (c cmdable) GetEx(ctx context.Context, key string, expiration time.Duration) *StringCmd {
+	args := []interface{}{"getex", key}
+	if expiration > 0 {
+		args = appendExpiration(args, expiration)
+	}
+	cmd := NewStringCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetPersist returns the value of key and removes its timeout
+func (c cmdable) GetPersist(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd("getex", key, "persist")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// MGet returns the values of keys, nil for the ones that are missing or
+// don't hold a string
+func (c cmdable) MGet(ctx context.Context, keys ...string) *SliceCmd {
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, "mget")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := NewSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// MSet sets keys and values given in turn
+func (c cmdable) MSet(ctx context.Context, keyValues ...interface{}) *StatusCmd {
+	cmd := NewStatusCmd(append([]interface{}{"mset"}, keyValues...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// MSetNX is MSet only setting the keys if none of them exists. It reports
+// whether they were set.
+func (c cmdable) MSetNX(ctx context.Context, keyValues ...interface{}) *BoolCmd {
+	cmd := NewBoolCmd(append([]interface{}{"msetnx"}, keyValues...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Incr adds one to the integer at key and returns the result
+func (c cmdable) Incr(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd("incr", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Decr subtracts one from the integer at key and returns the result
+func (c cmdable) Decr(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd("decr", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// IncrBy adds value to the integer at key and returns the result
+func (c cmdable) IncrBy(ctx context.Context, key string, value int64) *IntCmd {
+	cmd := NewIntCmd("incrby", key, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// DecrBy subtracts value from the integer at key and returns the result
+func (c cmdable) DecrBy(ctx context.Context, key string, value int64) *IntCmd {
+	cmd := NewIntCmd("decrby", key, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// IncrByFloat adds value to the number at key and returns the result
+func (c cmdable) IncrByFloat(ctx context.Context, key string, value float64) *FloatCmd {
+	cmd := NewFloatCmd("incrbyfloat", key, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Append appends value to the string at key and returns its new length
+func (c cmdable) Append(ctx context.Context, key, value string) *IntCmd {
+	cmd := NewIntCmd("append", key, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// StrLen returns the length of the string at key
+func (c cmdable) StrLen(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd("strlen", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetRange returns the bytes of the string at key from start to end, both
+// included. Negative offsets count from the end.
+func (c cmdable) GetRange(ctx context.Context, key string, start, end int64) *StringCmd {
+	cmd := NewStringCmd("getrange", key, start, end)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SetRange overwrites the string at key from offset with value and returns
+// its new length
+func (c cmdable) SetRange(ctx context.Context, key string, offset int64, value string) *IntCmd {
+	cmd := NewIntCmd("setrange", key, offset, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LCS returns the longest common subsequence of the strings at two keys
+func (c cmdable) LCS(ctx context.Context, key1, key2 string) *StringCmd {
+	cmd := NewStringCmd("lcs", key1, key2)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LCSLen returns the length of the longest common subsequence of the
+// strings at two keys
+func (c cmdable) LCSLen(ctx context.Context, key1, key2 string) *IntCmd {
+	cmd := NewIntCmd("lcs", key1, key2, "len")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SetBit sets the bit at offset of the string at key to value and returns
+// its previous value
+func (c cmdable) SetBit(ctx context.Context, key string, offset int64, value int) *IntCmd {
+	cmd := NewIntCmd("setbit", key, offset, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetBit returns the bit at offset of the string at key
+func (c cmdable) GetBit(ctx context.Context, key string, offset int64) *IntCmd {
+	cmd := NewIntCmd("getbit", key, offset)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BitCount is the range BitCount counts in. Unit is "byte", the default,
+// or "bit".
+type BitCount struct {
+	Start, End int64
+	Unit       string
+}
+
+// BitCount returns the number of set bits of the string at key, in bitCount
+// or in all of it if bitCount is nil
+func (c cmdable) BitCount(ctx context.Context, key string, bitCount *BitCount) *IntCmd {
+	args := []interface{}{"bitcount", key}
+	if bitCount != nil {
+		args = append(args, bitCount.Start, bitCount.End)
+		if bitCount.Unit != "" {
+			args = append(args, bitCount.Unit)
+		}
+	}
+	cmd := NewIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BitPos returns the offset of the first bit set to bit in the string at
+// key, or -1. pos is an optional start and end, in bytes.
+func (c cmdable) BitPos(ctx context.Context, key string, bit int64, pos ...int64) *IntCmd {
+	args := []interface{}{"bitpos", key, bit}
+	for _, p := range pos {
+		args = append(args, p)
+	}
+	cmd := NewIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BitOpAnd stores the bitwise AND of the strings at keys at destKey and
+// returns its length
+func (c cmdable) BitOpAnd(ctx context.Context, destKey string, keys ...string) *IntCmd {
+	return c.bitOp(ctx, "and", destKey, keys...)
+}
+
+// BitOpOr stores the bitwise OR of the strings at keys at destKey
+func (c cmdable) BitOpOr(ctx context.Context, destKey string, keys ...string) *IntCmd {
+	return c.bitOp(ctx, "or", destKey, keys...)
+}
+
+// BitOpXor stores the bitwise XOR of the strings at keys at destKey
+func (c cmdable) BitOpXor(ctx context.Context, destKey string, keys ...string) *IntCmd {
+	return c.bitOp(ctx, "xor", destKey, keys...)
+}
+
+// BitOpNot stores the bitwise NOT of the string at key at destKey
+func (c cmdable) BitOpNot(ctx context.Context, destKey string, key string) *IntCmd {
+	return c.bitOp(ctx, "not", destKey, key)
+}
+
+// BitOpDiff stores at destKey the bits of the string at the first key that
+// are set in none of the others
+func (c cmdable) BitOpDiff(ctx context.Context, destKey string, keys ...string) *IntCmd {
+	return c.bitOp(ctx, "diff", destKey, keys...)
+}
+
+func (c cmdable) bitOp(ctx context.Context, op, destKey string, keys ...string) *IntCmd {
+	args := make([]interface{}, 0, len(keys)+3)
+	args = append(args, "bitop", op, destKey)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := NewIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BitField runs the GET, SET, INCRBY and OVERFLOW subcommands in values
+// against the string at key. The results are integers, or nil for writes
+// that failed with OVERFLOW FAIL.
+func (c cmdable) BitField(ctx context.Context, key string, values ...interface{}) *SliceCmd {
+	cmd := NewSliceCmd(append([]interface{}{"bitfield", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BitFieldRO is BitField limited to GET subcommands
+func (c cmdable) BitFieldRO(ctx context.Context, key string, values ...interface{}) *SliceCmd {
+	cmd := NewSliceCmd(append([]interface{}{"bitfield_ro", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Del deletes keys and returns how many existed
+func (c cmdable) Del(ctx context.Context, keys ...string) *IntCmd {
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, "del")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := NewIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Expire sets a timeout on key, in whole seconds, and reports whether it
+// was set
+func (c cmdable) Expire(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return c.expire(ctx, key, expiration, "")
+}
+
+// ExpireNX sets a timeout on key only if it has none
+func (c cmdable) ExpireNX(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return c.expire(ctx, key, expiration, "nx")
+}
+
+// ExpireXX sets a timeout on key only if it already has one
+func (c cmdable) ExpireXX(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return c.expire(ctx, key, expiration, "xx")
+}
+
+// ExpireGT sets a timeout on key only if it is greater than the current one
+func (c cmdable) ExpireGT(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return c.expire(ctx, key, expiration, "gt")
+}
+
+// ExpireLT sets a timeout on key only if it is less than the current one
+func (c cmdable) ExpireLT(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return c.expire(ctx, key, expiration, "lt")
+}
+
+func (c cmdable) expire(ctx context.Context, key string, expiration time.Duration, mode string) *BoolCmd {
+	args := []interface{}{"expire", key, int64(expiration / time.Second)}
+	if mode != "" {
+		args = append(args, mode)
+	}
+	cmd := NewBoolCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PExpire sets a timeout on key with millisecond precision
+func (c cmdable) PExpire(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	cmd := NewBoolCmd("pexpire", key, int64(expiration/time.Millisecond))
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ExpireAt makes key expire at tm, with second precision. A time in the past
+// deletes the key.
+func (c cmdable) ExpireAt(ctx context.Context, key string, tm time.Time) *BoolCmd {
+	cmd := NewBoolCmd("expireat", key, tm.Unix())
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PExpireAt makes key expire at tm, with millisecond precision
+func (c cmdable) PExpireAt(ctx context.Context, key string, tm time.Time) *BoolCmd {
+	cmd := NewBoolCmd("pexpireat", key, tm.UnixMilli())
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Persist removes the timeout of key and reports whether it had one
+func (c cmdable) Persist(ctx context.Context, key string) *BoolCmd {
+	cmd := NewBoolCmd("persist", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TTL returns the time to live of key, -1 if it has no expiry and -2 if it
+// doesn't exist
+func (c cmdable) TTL(ctx context.Context, key string) *DurationCmd {
+	cmd := NewDurationCmd(time.Second, "ttl", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PTTL is TTL with millisecond precision
+func (c cmdable) PTTL(ctx context.Context, key string) *DurationCmd {
+	cmd := NewDurationCmd(time.Millisecond, "pttl", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ExpireTime returns when key expires as the time since the Unix epoch, or
+// -1 and -2 like TTL
+func (c cmdable) ExpireTime(ctx context.Context, key string) *DurationCmd {
+	cmd := NewDurationCmd(time.Second, "expiretime", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PExpireTime is ExpireTime with millisecond precision
+func (c cmdable) PExpireTime(ctx context.Context, key string) *DurationCmd {
+	cmd := NewDurationCmd(time.Millisecond, "pexpiretime", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Keys returns the keys matching a glob-style pattern
+func (c cmdable) Keys(ctx context.Context, pattern string) *StringSliceCmd {
+	cmd := NewStringSliceCmd("keys", pattern)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Scan returns a page of the keys matching a glob-style pattern, walking the
+// keyspace from cursor, which is 0 to start. count hints at the page size and
+// is left to the server when 0.
+func (c cmdable) Scan(ctx context.Context, cursor uint64, match string, count int64) *ScanCmd {
+	cmd := NewScanCmd(appendScanArgs([]interface{}{"scan", cursor}, match, count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ScanType is Scan returning only keys of the given type
+func (c cmdable) ScanType(ctx context.Context, cursor uint64, match string, count int64, keyType string) *ScanCmd {
+	args := appendScanArgs([]interface{}{"scan", cursor}, match, count)
+	cmd := NewScanCmd(append(args, "type", keyType)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func appendScanArgs(args []interface{}, match string, count int64) []interface{} {
+	if match != "" {
+		args = append(args, "match", match)
+	}
+	if count > 0 {
+		args = append(args, "count", count)
+	}
+	return args
+}
+
+// ZAdd adds members to the sorted set at key, or updates their scores, and
+// returns how many were added
+func (c cmdable) ZAdd(ctx context.Context, key string, members ...Z) *IntCmd {
+	return c.ZAddArgs(ctx, key, ZAddArgs{Members: members})
+}
+
+// ZAddArgs are the options of ZADD
+type ZAddArgs struct {
+	// NX only adds new members, XX only updates existing ones
+	NX bool
+	XX bool
+	// GT and LT only update scores that grow or shrink
+	GT bool
+	LT bool
+	// Ch counts updated members in the reply as well as added ones
+	Ch bool
+
+	Members []Z
+}
+
+func (a ZAddArgs) args(key string) []interface{} {
+	args := []interface{}{"zadd", key}
+	for _, opt := range []struct {
+		set  bool
+		name string
+	}{{a.NX, "nx"}, {a.XX, "xx"}, {a.GT, "gt"}, {a.LT, "lt"}, {a.Ch, "ch"}} {
+		if opt.set {
+			args = append(args, opt.name)
+		}
+	}
+	return args
+}
+
+func appendMembers(args []interface{}, members []Z) []interface{} {
+	for _, m := range members {
+		args = append(args, m.Score, m.Member)
+	}
+	return args
+}
+
+// ZAddArgs runs ZADD with all its options
+func (c cmdable) ZAddArgs(ctx context.Context, key string, a ZAddArgs) *IntCmd {
+	cmd := NewIntCmd(appendMembers(a.args(key), a.Members)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZAddArgsIncr runs ZADD INCR, adding the score of the single member to its
+// current score. It returns the new score, or the Nil error when the options
+// prevented the update.
+func (c cmdable) ZAddArgsIncr(ctx context.Context, key string, a ZAddArgs) *FloatCmd {
+	args := append(a.args(key), "incr")
+	cmd := NewFloatCmd(appendMembers(args, a.Members)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZRange returns the members of the sorted set at key between two indexes
+func (c cmdable) ZRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd {
+	return c.ZRangeArgs(ctx, ZRangeArgs{Key: key, Start: start, Stop: stop})
+}
+
+// ZRangeWithScores returns the members between two indexes with their scores
+func (c cmdable) ZRangeWithScores(ctx context.Context, key string, start, stop int64) *ZSliceCmd {
+	return c.ZRangeArgsWithScores(ctx, ZRangeArgs{Key: key, Start: start, Stop: stop})
+}
+
+// ZRangeArgs are the arguments of ZRANGE
+type ZRangeArgs struct {
+	Key string
+	// Start and Stop are indexes, scores with ByScore or lexicographical
+	// bounds with ByLex
+	Start interface{}
+	Stop  interface{}
+
+	ByScore bool
+	ByLex   bool
+	Rev     bool
+
+	// Offset and Count limit the reply; they need ByScore or ByLex
+	Offset int64
+	Count  int64
+}
+
+func (z ZRangeArgs) args(withScores bool) []interface{} {
+	args := []interface{}{"zrange", z.Key, z.Start, z.Stop}
+	switch {
+	case z.ByScore:
+		args = append(args, "byscore")
+	case z.ByLex:
+		args = append(args, "bylex")
+	}
+	if z.Rev {
+		args = append(args, "rev")
+	}
+	if z.Offset != 0 || z.Count != 0 {
+		args = append(args, "limit", z.Offset, z.Count)
+	}
+	if withScores {
+		args = append(args, "withscores")
+	}
+	return args
+}
+
+// ZRangeArgs runs ZRANGE with all its options
+func (c cmdable) ZRangeArgs(ctx context.Context, z ZRangeArgs) *StringSliceCmd {
+	cmd := NewStringSliceCmd(z.args(false)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZRangeArgsWithScores runs ZRANGE with all its options and WITHSCORES
+func (c cmdable) ZRangeArgsWithScores(ctx context.Context, z ZRangeArgs) *ZSliceCmd {
+	cmd := NewZSliceCmd(z.args(true)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZScan is Scan over the members of the sorted set at key. The page holds
+// members and their scores in turn.
+func (c cmdable) ZScan(ctx context.Context, key string, cursor uint64, match string, count int64) *ScanCmd {
+	cmd := NewScanCmd(appendScanArgs([]interface{}{"zscan", key, cursor}, match, count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZPopMin removes up to count members with the lowest scores from the
+// sorted set at key and returns them
+func (c cmdable) ZPopMin(ctx context.Context, key string, count int64) *ZSliceCmd {
+	cmd := NewZSliceCmd("zpopmin", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZPopMax is ZPopMin for the highest scores
+func (c cmdable) ZPopMax(ctx context.Context, key string, count int64) *ZSliceCmd {
+	cmd := NewZSliceCmd("zpopmax", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ZMPop pops up to count members from the first non-empty sorted set of
+// keys, the lowest-scoring ones or the highest as order, "min" or "max",
+// says. It returns the key and the members, or the Nil error if all of the
+// sorted sets are missing.
+func (c cmdable) ZMPop(ctx context.Context, order string, count int64, keys ...string) *KeyZSliceCmd {
+	cmd := NewKeyZSliceCmd(mpopArgs("zmpop", nil, keys, order, count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BZPopMin is ZPopMin of a single member from the first non-empty sorted
+// set of keys, waiting up to timeout for one, forever if it is 0. It returns
+// the key and the member, or the Nil error if the timeout elapsed.
+func (c cmdable) BZPopMin(ctx context.Context, timeout time.Duration, keys ...string) *ZWithKeyCmd {
+	cmd := NewZWithKeyCmd(blockingArgs("bzpopmin", keys, timeout)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BZPopMax is BZPopMin for the highest score
+func (c cmdable) BZPopMax(ctx context.Context, timeout time.Duration, keys ...string) *ZWithKeyCmd {
+	cmd := NewZWithKeyCmd(blockingArgs("bzpopmax", keys, timeout)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BZMPop is ZMPop waiting up to timeout for a sorted set to have members,
+// forever if it is 0
+func (c cmdable) BZMPop(ctx context.Context, timeout time.Duration, order string, count int64, keys ...string) *KeyZSliceCmd {
+	cmd := NewKeyZSliceCmd(mpopArgs("bzmpop", []interface{}{timeoutArg(timeout)}, keys, order, count)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HSet sets fields of the hash at key, given as fields and values in turn,
+// and returns how many were added
+func (c cmdable) HSet(ctx context.Context, key string, fieldValues ...interface{}) *IntCmd {
+	cmd := NewIntCmd(append([]interface{}{"hset", key}, fieldValues...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HSetNX sets a field of the hash at key only if it doesn't exist. It
+// reports whether the field was set.
+func (c cmdable) HSetNX(ctx context.Context, key, field string, value interface{}) *BoolCmd {
+	cmd := NewBoolCmd("hsetnx", key, field, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HGet returns the value of a field of the hash at key, or the Nil error
+func (c cmdable) HGet(ctx context.Context, key, field string) *StringCmd {
+	cmd := NewStringCmd("hget", key, field)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HMGet returns the values of fields of the hash at key, nil for the ones
+// missing
+func (c cmdable) HMGet(ctx context.Context, key string, fields ...string) *SliceCmd {
+	args := []interface{}{"hmget", key}
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HDel deletes fields of the hash at key and returns how many existed
+func (c cmdable) HDel(ctx context.Context, key string, fields ...string) *IntCmd {
+	args := []interface{}{"hdel", key}
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HExists reports whether a field of the hash at key exists
+func (c cmdable) HExists(ctx context.Context, key, field string) *BoolCmd {
+	cmd := NewBoolCmd("hexists", key, field)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HLen returns the number of fields of the hash at key
+func (c cmdable) HLen(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd("hlen", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HStrLen returns the length of the value of a field of the hash at key
+func (c cmdable) HStrLen(ctx context.Context, key, field string) *IntCmd {
+	cmd := NewIntCmd("hstrlen", key, field)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HKeys returns the fields of the hash at key
+func (c cmdable) HKeys(ctx context.Context, key string) *StringSliceCmd {
+	cmd := NewStringSliceCmd("hkeys", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HVals returns the values of the hash at key
+func (c cmdable) HVals(ctx context.Context, key string) *StringSliceCmd {
+	cmd := NewStringSliceCmd("hvals", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HGetAll returns the fields and values of the hash at key
+func (c cmdable) HGetAll(ctx context.Context, key string) *MapStringStringCmd {
+	cmd := NewMapStringStringCmd("hgetall", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HIncrBy adds incr to the integer field of the hash at key and returns the
+// result
+func (c cmdable) HIncrBy(ctx context.Context, key, field string, incr int64) *IntCmd {
+	cmd := NewIntCmd("hincrby", key, field, incr)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HIncrByFloat adds incr to the floating point field of the hash at key and
+// returns the result
+func (c cmdable) HIncrByFloat(ctx context.Context, key, field string, incr float64) *FloatCmd {
+	cmd := NewFloatCmd("hincrbyfloat", key, field, incr)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HRandField returns up to count distinct random fields of the hash at key,
+// or exactly -count fields that may repeat when count is negative
+func (c cmdable) HRandField(ctx context.Context, key string, count int) *StringSliceCmd {
+	cmd := NewStringSliceCmd("hrandfield", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HRandFieldWithValues is HRandField returning fields and their values in
+// turn
+func (c cmdable) HRandFieldWithValues(ctx context.Context, key string, count int) *StringSliceCmd {
+	cmd := NewStringSliceCmd("hrandfield", key, count, "withvalues")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HScan is Scan over the fields of the hash at key. The page holds fields
+// and their values in turn.
+func (c cmdable) HScan(ctx context.Context, key string, cursor uint64, match string, count int64) *ScanCmd {
+	cmd := NewScanCmd(appendScanArgs([]interface{}{"hscan", key, cursor}, match, count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HScanNoValues is HScan returning only the fields
+func (c cmdable) HScanNoValues(ctx context.Context, key string, cursor uint64, match string, count int64) *ScanCmd {
+	args := appendScanArgs([]interface{}{"hscan", key, cursor}, match, count)
+	cmd := NewScanCmd(append(args, "novalues")...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HExpire sets a timeout on fields of the hash at key and returns a code per
+// field: -2 if it doesn't exist, 1 if the timeout was set and 2 if the field
+// was deleted because the timeout is 0
+func (c cmdable) HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *IntSliceCmd {
+	return c.hExpire(ctx, "hexpire", key, int64(expiration/time.Second), fields)
+}
+
+// HPExpire is HExpire with millisecond precision
+func (c cmdable) HPExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *IntSliceCmd {
+	return c.hExpire(ctx, "hpexpire", key, int64(expiration/time.Millisecond), fields)
+}
+
+// HExpireAt is HExpire with an absolute time
+func (c cmdable) HExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *IntSliceCmd {
+	return c.hExpire(ctx, "hexpireat", key, tm.Unix(), fields)
+}
+
+// HPExpireAt is HExpireAt with millisecond precision
+func (c cmdable) HPExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *IntSliceCmd {
+	return c.hExpire(ctx, "hpexpireat", key, tm.UnixMilli(), fields)
+}
+
+func (c cmdable) hExpire(ctx context.Context, name, key string, when int64, fields []string) *IntSliceCmd {
+	args := []interface{}{name, key, when, "fields", len(fields)}
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewIntSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HTTL returns the time to live in seconds of fields of the hash at key, -1
+// for the ones without a timeout and -2 for the ones missing
+func (c cmdable) HTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hFields(ctx, "httl", key, fields)
+}
+
+// HPTTL is HTTL in milliseconds
+func (c cmdable) HPTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hFields(ctx, "hpttl", key, fields)
+}
+
+// HExpireTime returns when fields of the hash at key expire as Unix times in
+// seconds, or -1 and -2 like HTTL
+func (c cmdable) HExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hFields(ctx, "hexpiretime", key, fields)
+}
+
+// HPExpireTime is HExpireTime in milliseconds
+func (c cmdable) HPExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hFields(ctx, "hpexpiretime", key, fields)
+}
+
+// HPersist removes the timeouts of fields of the hash at key and returns a
+// code per field: -2 if it doesn't exist, -1 if it had no timeout and 1 if
+// the timeout was removed
+func (c cmdable) HPersist(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hFields(ctx, "hpersist", key, fields)
+}
+
+func (c cmdable) hFields(ctx context.Context, name, key string, fields []string) *IntSliceCmd {
+	args := []interface{}{name, key, "fields", len(fields)}
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewIntSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPush adds values at the head of the list at key, one by one, and returns
+// the length of the list
+func (c cmdable) LPush(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	cmd := NewIntCmd(append([]interface{}{"lpush", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPushX is LPush only if the list exists. It returns 0 otherwise.
+func (c cmdable) LPushX(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	cmd := NewIntCmd(append([]interface{}{"lpushx", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// RPush adds values at the tail of the list at key and returns the length
+// of the list
+func (c cmdable) RPush(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	cmd := NewIntCmd(append([]interface{}{"rpush", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// RPushX is RPush only if the list exists. It returns 0 otherwise.
+func (c cmdable) RPushX(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	cmd := NewIntCmd(append([]interface{}{"rpushx", key}, values...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPop removes and returns the first element of the list at key, or the Nil
+// error
+func (c cmdable) LPop(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd("lpop", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPopCount removes and returns up to count elements from the head of the
+// list at key
+func (c cmdable) LPopCount(ctx context.Context, key string, count int) *StringSliceCmd {
+	cmd := NewStringSliceCmd("lpop", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// RPop removes and returns the last element of the list at key, or the Nil
+// error
+func (c cmdable) RPop(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd("rpop", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// RPopCount removes and returns up to count elements from the tail of the
+// list at key
+func (c cmdable) RPopCount(ctx context.Context, key string, count int) *StringSliceCmd {
+	cmd := NewStringSliceCmd("rpop", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LLen returns the length of the list at key
+func (c cmdable) LLen(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd("llen", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LRange returns the elements of the list at key from start to stop, both
+// included, negative indexes counting from the end
+func (c cmdable) LRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd {
+	cmd := NewStringSliceCmd("lrange", key, start, stop)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LIndex returns the element at index of the list at key, or the Nil error
+func (c cmdable) LIndex(ctx context.Context, key string, index int64) *StringCmd {
+	cmd := NewStringCmd("lindex", key, index)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LSet replaces the element at index of the list at key
+func (c cmdable) LSet(ctx context.Context, key string, index int64, value interface{}) *StatusCmd {
+	cmd := NewStatusCmd("lset", key, index, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LInsertBefore inserts value before the first occurrence of pivot in the
+// list at key and returns the length of the list, or -1 if pivot wasn't
+// found
+func (c cmdable) LInsertBefore(ctx context.Context, key string, pivot, value interface{}) *IntCmd {
+	cmd := NewIntCmd("linsert", key, "before", pivot, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LInsertAfter is LInsertBefore inserting after pivot
+func (c cmdable) LInsertAfter(ctx context.Context, key string, pivot, value interface{}) *IntCmd {
+	cmd := NewIntCmd("linsert", key, "after", pivot, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LRem removes the first count occurrences of value from the list at key,
+// the last -count ones if count is negative and all of them if it is 0
+func (c cmdable) LRem(ctx context.Context, key string, count int64, value interface{}) *IntCmd {
+	cmd := NewIntCmd("lrem", key, count, value)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LTrim keeps only the elements of the list at key from start to stop
+func (c cmdable) LTrim(ctx context.Context, key string, start, stop int64) *StatusCmd {
+	cmd := NewStatusCmd("ltrim", key, start, stop)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPosArgs are the options of LPOS
+type LPosArgs struct {
+	// Rank is the match to start from, counting from the tail if negative.
+	// 0 leaves it to the server, which starts from the first.
+	Rank int64
+	// MaxLen limits the number of elements compared, all of them if 0
+	MaxLen int64
+}
+
+func (a LPosArgs) args(args []interface{}) []interface{} {
+	if a.Rank != 0 {
+		args = append(args, "rank", a.Rank)
+	}
+	if a.MaxLen != 0 {
+		args = append(args, "maxlen", a.MaxLen)
+	}
+	return args
+}
+
+// LPos returns the index of the first element of the list at key equal to
+// value, or the Nil error
+func (c cmdable) LPos(ctx context.Context, key string, value string, a LPosArgs) *IntCmd {
+	cmd := NewIntCmd(a.args([]interface{}{"lpos", key, value})...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LPosCount is LPos returning the indexes of up to count matches, all of
+// them if count is 0
+func (c cmdable) LPosCount(ctx context.Context, key string, value string, count int64, a LPosArgs) *IntSliceCmd {
+	args := a.args([]interface{}{"lpos", key, value})
+	cmd := NewIntSliceCmd(append(args, "count", count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LMove pops an element from the source list, at the "left" or "right" end
+// as srcpos says, and pushes it to the destination at destpos. It returns
+// the element, or the Nil error if the source is missing.
+func (c cmdable) LMove(ctx context.Context, source, destination, srcpos, destpos string) *StringCmd {
+	cmd := NewStringCmd("lmove", source, destination, srcpos, destpos)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LMPop pops up to count elements from the first non-empty list of keys, at
+// the "left" or "right" end as direction says. It returns the key and the
+// elements, or the Nil error if all of the lists are missing.
+func (c cmdable) LMPop(ctx context.Context, direction string, count int64, keys ...string) *KeyValuesCmd {
+	cmd := NewKeyValuesCmd(mpopArgs("lmpop", nil, keys, direction, count)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// mpopArgs builds the arguments of LMPOP, ZMPOP and their blocking
+// variants, whose arguments before numkeys are given in pre
+func mpopArgs(name string, pre []interface{}, keys []string, where string, count int64) []interface{} {
+	args := append([]interface{}{name}, pre...)
+	args = append(args, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return append(args, where, "count", count)
+}
+
+// blockingArgs builds the arguments of BLPOP and the like: the keys then the
+// timeout
+func blockingArgs(name string, keys []string, timeout time.Duration) []interface{} {
+	args := []interface{}{name}
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return append(args, timeoutArg(timeout))
+}
+
+// timeoutArg is a blocking command timeout, in seconds
+func timeoutArg(timeout time.Duration) float64 {
+	return timeout.Seconds()
+}
+
+// BLPop pops the first element of the first non-empty list of keys, waiting
+// up to timeout for one, forever if it is 0. It returns the key and the
+// element, or the Nil error if the timeout elapsed.
+func (c cmdable) BLPop(ctx context.Context, timeout time.Duration, keys ...string) *KeyValueCmd {
+	cmd := NewKeyValueCmd(blockingArgs("blpop", keys, timeout)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BRPop is BLPop popping the last element
+func (c cmdable) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *KeyValueCmd {
+	cmd := NewKeyValueCmd(blockingArgs("brpop", keys, timeout)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BLMove is LMove waiting up to timeout for the source list to have an
+// element, forever if it is 0
+func (c cmdable) BLMove(ctx context.Context, source, destination, srcpos, destpos string, timeout time.Duration) *StringCmd {
+	cmd := NewStringCmd("blmove", source, destination, srcpos, destpos, timeoutArg(timeout))
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// BLMPop is LMPop waiting up to timeout for a list to have elements,
+// forever if it is 0
+func (c cmdable) BLMPop(ctx context.Context, timeout time.Duration, direction string, count int64, keys ...string) *KeyValuesCmd {
+	cmd := NewKeyValuesCmd(mpopArgs("blmpop", []interface{}{timeoutArg(timeout)}, keys, direction, count)...)
+	cmd.setBlockTimeout(timeout)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// CommandCount returns the number of commands the server supports
+func (c cmdable) CommandCount(ctx context.Context) *IntCmd {
+	cmd := NewIntCmd("command", "count")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FilterBy narrows CommandList to the commands of a module, an ACL category
+// or matching a pattern. Only one field may be set.
+type FilterBy struct {
+	Module  string
+	ACLCat  string
+	Pattern string
+}
+
+// CommandList returns the names of the commands the server supports,
+// optionally filtered
+func (c cmdable) CommandList(ctx context.Context, filter *FilterBy) *StringSliceCmd {
+	args := []interface{}{"command", "list"}
+	if filter != nil {
+		switch {
+		case filter.Module != "":
+			args = append(args, "filterby", "module", filter.Module)
+		case filter.ACLCat != "":
+			args = append(args, "filterby", "aclcat", filter.ACLCat)
+		case filter.Pattern != "":
+			args = append(args, "filterby", "pattern", filter.Pattern)
+		}
+	}
+	cmd := NewStringSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// CommandGetKeys returns the keys a command would access
+func (c cmdable) CommandGetKeys(ctx context.Context, commands ...interface{}) *StringSliceCmd {
+	cmd := NewStringSliceCmd(append([]interface{}{"command", "getkeys"}, commands...)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// CommandInfo returns the details of the named commands, or of all of them,
+// as the server's nested arrays
+func (c cmdable) CommandInfo(ctx context.Context, names ...string) *Cmd {
+	args := []interface{}{"command", "info"}
+	for _, name := range names {
+		args = append(args, name)
+	}
+	cmd := NewCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// CommandDocs returns the documentation of the named commands, or of all of
+// them, as a map from command name to its documentation
+func (c cmdable) CommandDocs(ctx context.Context, names ...string) *Cmd {
+	args := []interface{}{"command", "docs"}
+	for _, name := range names {
+		args = append(args, name)
+	}
+	cmd := NewCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// Publish posts message to channel and returns how many subscribers
+// received it
+func (c cmdable) Publish(ctx context.Context, channel string, message interface{}) *IntCmd {
+	cmd := NewIntCmd("publish", channel, message)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubChannels returns the channels with subscribers matching pattern, or
+// all of them for an empty pattern
+func (c cmdable) PubSubChannels(ctx context.Context, pattern string) *StringSliceCmd {
+	args := []interface{}{"pubsub", "channels"}
+	if pattern != "" {
+		args = append(args, pattern)
+	}
+	cmd := NewStringSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubNumSub returns the number of subscribers of each channel, not
+// counting pattern subscriptions
+func (c cmdable) PubSubNumSub(ctx context.Context, channels ...string) *MapStringIntCmd {
+	args := []interface{}{"pubsub", "numsub"}
+	for _, channel := range channels {
+		args = append(args, channel)
+	}
+	cmd := NewMapStringIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubNumPat returns the number of patterns subscribed to
+func (c cmdable) PubSubNumPat(ctx context.Context) *IntCmd {
+	cmd := NewIntCmd("pubsub", "numpat")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// SPublish posts message to a shard channel and returns how many subscribers
+// received it
+func (c cmdable) SPublish(ctx context.Context, channel string, message interface{}) *IntCmd {
+	cmd := NewIntCmd("spublish", channel, message)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubShardChannels returns the shard channels with subscribers matching
+// pattern, or all of them for an empty pattern
+func (c cmdable) PubSubShardChannels(ctx context.Context, pattern string) *StringSliceCmd {
+	args := []interface{}{"pubsub", "shardchannels"}
+	if pattern != "" {
+		args = append(args, pattern)
+	}
+	cmd := NewStringSliceCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubShardNumSub returns the number of subscribers of each shard channel
+func (c cmdable) PubSubShardNumSub(ctx context.Context, channels ...string) *MapStringIntCmd {
+	args := []interface{}{"pubsub", "shardnumsub"}
+	for _, channel := range channels {
+		args = append(args, channel)
+	}
+	cmd := NewMapStringIntCmd(args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ClientID returns the ID of the connection the command runs on. A Client
+// may use a different connection for each command.
+func (c cmdable) ClientID(ctx context.Context) *IntCmd {
+	cmd := NewIntCmd("client", "id")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ClientUnblock ends the wait of the connection with the given ID in a
+// blocking command as if its timeout elapsed, and reports 1 if it was
+// blocked
+func (c cmdable) ClientUnblock(ctx context.Context, id int64) *IntCmd {
+	cmd := NewIntCmd("client", "unblock", id)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ClientUnblockWithError is ClientUnblock making the blocking command fail
+// with an UNBLOCKED error instead
+func (c cmdable) ClientUnblockWithError(ctx context.Context, id int64) *IntCmd {
+	cmd := NewIntCmd("client", "unblock", id, "error")
+	_ = c(ctx, cmd)
+	return cmd
+}
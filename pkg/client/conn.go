@@ -0,0 +1,228 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"time"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/resp"
+)
+
+// conn is a single connection to the server
+type conn struct {
+	netConn net.Conn
+	reader  *resp.Reader
+	writer  *resp.Writer
+
+	// usedAt is when the connection was last returned to the pool
+	usedAt time.Time
+	// broken connections may have a reply half read and are never reused
+	broken bool
+}
+
+func newConn(netConn net.Conn) *conn {
+	return &conn{
+		netConn: netConn,
+		reader:  resp.NewReader(bufio.NewReader(netConn)),
+		writer:  resp.NewWriter(bufio.NewWriter(netConn)),
+		usedAt:  time.Now(),
+	}
+}
+
+// roundTrip sends cmds in a single write and reads their replies in order.
+// Error replies are stored on the commands; the returned error is only set
+// when the connection failed, in which case it is marked broken.
+func (cn *conn) roundTrip(ctx context.Context, opts *Options, cmds []Cmder) error {
+	return cn.run(ctx, opts, cmds, func() error {
+		return cn.readReplies(cmds)
+	})
+}
+
+// roundTripTx sends cmds wrapped in MULTI and EXEC, and stores the replies
+// from the EXEC array on them
+func (cn *conn) roundTripTx(ctx context.Context, opts *Options, cmds []Cmder) error {
+	wrapped := make([]Cmder, 0, len(cmds)+2)
+	wrapped = append(wrapped, NewStatusCmd("multi"))
+	wrapped = append(wrapped, cmds...)
+	wrapped = append(wrapped, NewCmd("exec"))
+	return cn.run(ctx, opts, wrapped, func() error {
+		return cn.readTxReplies(cmds)
+	})
+}
+
+// run writes cmds and calls read to read their replies, bounded by the
+// timeouts and the context
+func (cn *conn) run(ctx context.Context, opts *Options, cmds []Cmder, read func() error) error {
+	// Cancelling the context unblocks any pending read or write by moving
+	// the deadline into the past
+	stop := context.AfterFunc(ctx, func() {
+		cn.netConn.SetDeadline(time.Unix(1, 0))
+	})
+	err := cn.exchange(ctx, opts, cmds, read)
+	if !stop() {
+		cn.broken = true
+		return ctx.Err()
+	}
+	if err != nil {
+		cn.broken = true
+	}
+	return err
+}
+
+func (cn *conn) exchange(ctx context.Context, opts *Options, cmds []Cmder, read func() error) error {
+	if err := cn.netConn.SetWriteDeadline(deadline(ctx, opts.WriteTimeout)); err != nil {
+		return err
+	}
+	for _, cmd := range cmds {
+		if err := cn.writer.WriteArray(cmd.argStrings()); err != nil {
+			return err
+		}
+	}
+	if err := cn.writer.Flush(); err != nil {
+		return err
+	}
+
+	if err := cn.netConn.SetReadDeadline(deadline(ctx, readTimeout(opts, cmds))); err != nil {
+		return err
+	}
+	return read()
+}
+
+// send writes cmds without reading replies, for subscriptions whose replies
+// arrive along with the messages
+func (cn *conn) send(ctx context.Context, opts *Options, cmds []Cmder) error {
+	return cn.run(ctx, opts, cmds, func() error { return nil })
+}
+
+// receive reads the next reply, waiting for as long as the context allows
+// rather than for the read timeout
+func (cn *conn) receive(ctx context.Context) (interface{}, error) {
+	stop := context.AfterFunc(ctx, func() {
+		cn.netConn.SetReadDeadline(time.Unix(1, 0))
+	})
+	var reply interface{}
+	err := cn.netConn.SetReadDeadline(deadline(ctx, 0))
+	if err == nil {
+		reply, err = cn.reader.ReadReply()
+	}
+	if !stop() {
+		cn.broken = true
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		cn.broken = true
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (cn *conn) readReplies(cmds []Cmder) error {
+	for _, cmd := range cmds {
+		reply, err := cn.reader.ReadReply()
+		if err != nil {
+			return err
+		}
+		setReply(cmd, reply)
+	}
+	return nil
+}
+
+// readTxReplies reads the replies to MULTI, to every queued command and to
+// EXEC. A command rejected while queueing keeps its own error, and the others
+// get the error that aborted EXEC.
+func (cn *conn) readTxReplies(cmds []Cmder) error {
+	replies := make([]interface{}, len(cmds)+2)
+	for i := range replies {
+		reply, err := cn.reader.ReadReply()
+		if err != nil {
+			return err
+		}
+		replies[i] = reply
+	}
+
+	if e, ok := replies[0].(*errs.Error); ok {
+		setErrs(cmds, e)
+		return nil
+	}
+	for i, cmd := range cmds {
+		if e, ok := replies[i+1].(*errs.Error); ok {
+			cmd.setErr(e)
+		}
+	}
+
+	switch exec := replies[len(replies)-1].(type) {
+	case nil:
+		// A watched key changed and the transaction didn't run
+		setErrs(cmds, ErrTxFailed)
+	case *errs.Error:
+		for _, cmd := range cmds {
+			if cmd.Err() == nil {
+				cmd.setErr(exec)
+			}
+		}
+	case []interface{}:
+		if len(exec) != len(cmds) {
+			setErrs(cmds, unexpectedReply(exec))
+			break
+		}
+		for i, cmd := range cmds {
+			setReply(cmd, exec[i])
+		}
+	default:
+		setErrs(cmds, unexpectedReply(exec))
+	}
+	return nil
+}
+
+// setReply stores a reply on cmd, turning error replies into the command's
+// error
+func setReply(cmd Cmder, reply interface{}) {
+	if e, ok := reply.(*errs.Error); ok {
+		cmd.setErr(e)
+		return
+	}
+	cmd.setErr(cmd.readReply(reply))
+}
+
+func setErrs(cmds []Cmder, err error) {
+	for _, cmd := range cmds {
+		cmd.setErr(err)
+	}
+}
+
+func (cn *conn) Close() error {
+	return cn.netConn.Close()
+}
+
+// readTimeout returns how long to wait for the replies to cmds: the read
+// timeout, extended by as long as blocking commands wait on the server
+func readTimeout(opts *Options, cmds []Cmder) time.Duration {
+	timeout := opts.ReadTimeout
+	for _, cmd := range cmds {
+		block, ok := cmd.blockTimeout()
+		if !ok || timeout <= 0 {
+			continue
+		}
+		if block == 0 {
+			timeout = 0
+		} else {
+			timeout = max(timeout, block+opts.ReadTimeout)
+		}
+	}
+	return timeout
+}
+
+// deadline returns the deadline for an operation bounded by timeout and by
+// the context, or the zero time for none
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var d time.Time
+	if timeout > 0 {
+		d = time.Now().Add(timeout)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (d.IsZero() || ctxDeadline.Before(d)) {
+		d = ctxDeadline
+	}
+	return d
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"runtime"
+	"time"
+)
+
+// Options configures a Client. The zero value of every field but Addr picks
+// a sensible default.
+type Options struct {
+	// Addr is the host:port of the server. Defaults to localhost:6379.
+	Addr string
+
+	// Username and Password authenticate every new connection. Without a
+	// username the connection authenticates as the default user.
+	Username string
+	Password string
+
+	// Protocol is the RESP version to speak, 2 or 3. Defaults to 2.
+	Protocol int
+
+	// ClientName is set on every new connection with HELLO SETNAME
+	ClientName string
+
+	// DialTimeout bounds establishing a connection. Defaults to 5 seconds.
+	DialTimeout time.Duration
+	// ReadTimeout and WriteTimeout bound each read and write of a round
+	// trip. Defaults to 3 seconds; -1 disables them. A context deadline
+	// shorter than the timeout takes precedence.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// PoolSize is the maximum number of connections in use at once. Callers
+	// wait for a connection when all of them are busy. Defaults to 10 per
+	// CPU.
+	PoolSize int
+	// PoolTimeout bounds the wait for a free connection when the context
+	// has no deadline. Defaults to ReadTimeout + 1 second.
+	PoolTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle connections kept open for
+	// reuse. Defaults to PoolSize.
+	MaxIdleConns int
+	// ConnMaxIdleTime closes connections that have been idle for longer.
+	// Defaults to 30 minutes; -1 keeps idle connections forever.
+	ConnMaxIdleTime time.Duration
+	// HealthCheckInterval is how long a connection may sit idle before it
+	// is checked with a PING on reuse. Defaults to 1 minute; -1 disables
+	// health checks.
+	HealthCheckInterval time.Duration
+
+	// MaxRetries is how many times a command is retried after a network
+	// error, on a fresh connection. Defaults to 3; -1 disables retries.
+	MaxRetries int
+	// MinRetryBackoff and MaxRetryBackoff bound the wait between retries,
+	// which doubles with every attempt. Default to 8ms and 512ms.
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// withDefaults returns a copy of the options with defaults filled in
+func (o Options) withDefaults() *Options {
+	if o.Addr == "" {
+		o.Addr = "localhost:6379"
+	}
+	if o.Protocol == 0 {
+		o.Protocol = 2
+	}
+	if o.DialTimeout == 0 {
+		o.DialTimeout = 5 * time.Second
+	}
+	o.ReadTimeout = defaultTimeout(o.ReadTimeout, 3*time.Second)
+	o.WriteTimeout = defaultTimeout(o.WriteTimeout, 3*time.Second)
+	if o.PoolSize <= 0 {
+		o.PoolSize = 10 * runtime.GOMAXPROCS(0)
+	}
+	if o.PoolTimeout == 0 {
+		o.PoolTimeout = max(o.ReadTimeout, 0) + time.Second
+	}
+	if o.MaxIdleConns <= 0 || o.MaxIdleConns > o.PoolSize {
+		o.MaxIdleConns = o.PoolSize
+	}
+	o.ConnMaxIdleTime = defaultTimeout(o.ConnMaxIdleTime, 30*time.Minute)
+	o.HealthCheckInterval = defaultTimeout(o.HealthCheckInterval, time.Minute)
+	switch {
+	case o.MaxRetries == 0:
+		o.MaxRetries = 3
+	case o.MaxRetries < 0:
+		o.MaxRetries = 0
+	}
+	if o.MinRetryBackoff == 0 {
+		o.MinRetryBackoff = 8 * time.Millisecond
+	}
+	if o.MaxRetryBackoff == 0 {
+		o.MaxRetryBackoff = 512 * time.Millisecond
+	}
+	return &o
+}
+
+// defaultTimeout returns def for an unset timeout and 0, meaning none, for a
+// disabled one
+func defaultTimeout(d, def time.Duration) time.Duration {
+	switch {
+	case d == 0:
+		return def
+	case d < 0:
+		return 0
+	}
+	return d
+}
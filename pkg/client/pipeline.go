@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrTxFailed is the error of every command of a transaction that didn't run
+// because a watched key changed
+var ErrTxFailed = errors.New("client: transaction failed")
+
+// Pipeline queues commands and sends them all in a single round trip on
+// Exec. The typed helpers return the queued command right away; it works as
+// a future, holding its reply once Exec returns.
+//
+//	pipe := c.Pipeline()
+//	incr := pipe.ZAddArgsIncr(ctx, "scores", client.ZAddArgs{Members: []client.Z{{Score: 1, Member: "a"}}})
+//	ttl := pipe.TTL(ctx, "scores")
+//	if _, err := pipe.Exec(ctx); err != nil {
+//		return err
+//	}
+//	fmt.Println(incr.Val(), ttl.Val())
+type Pipeline struct {
+	cmdable
+	exec func(ctx context.Context, cmds []Cmder) error
+
+	mu   sync.Mutex
+	cmds []Cmder
+}
+
+func newPipeline(exec func(ctx context.Context, cmds []Cmder) error) *Pipeline {
+	p := &Pipeline{exec: exec}
+	p.cmdable = p.Process
+	return p
+}
+
+// Pipeline returns a pipeline sending its commands on one connection
+func (c *Client) Pipeline() *Pipeline {
+	return newPipeline(c.processPipeline)
+}
+
+// TxPipeline returns a pipeline whose commands are wrapped in MULTI/EXEC, so
+// the server runs them as a single transaction
+func (c *Client) TxPipeline() *Pipeline {
+	return newPipeline(c.processTxPipeline)
+}
+
+// Pipelined queues the commands added by fn on a Pipeline and executes them
+func (c *Client) Pipelined(ctx context.Context, fn func(pipe *Pipeline) error) ([]Cmder, error) {
+	return c.Pipeline().Pipelined(ctx, fn)
+}
+
+// TxPipelined queues the commands added by fn on a TxPipeline and executes
+// them as a transaction
+func (c *Client) TxPipelined(ctx context.Context, fn func(pipe *Pipeline) error) ([]Cmder, error) {
+	return c.TxPipeline().Pipelined(ctx, fn)
+}
+
+func (c *Client) processPipeline(ctx context.Context, cmds []Cmder) error {
+	return c.withRetries(ctx, func(cn *conn) error {
+		return cn.roundTrip(ctx, c.opts, cmds)
+	})
+}
+
+func (c *Client) processTxPipeline(ctx context.Context, cmds []Cmder) error {
+	return c.withRetries(ctx, func(cn *conn) error {
+		return cn.roundTripTx(ctx, c.opts, cmds)
+	})
+}
+
+// Process queues cmd. It always returns nil; the command's error is set by
+// Exec.
+func (p *Pipeline) Process(ctx context.Context, cmd Cmder) error {
+	p.mu.Lock()
+	p.cmds = append(p.cmds, cmd)
+	p.mu.Unlock()
+	return nil
+}
+
+// Do queues a command built from args
+func (p *Pipeline) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(args...)
+	p.Process(ctx, cmd)
+	return cmd
+}
+
+// Len returns the number of queued commands
+func (p *Pipeline) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.cmds)
+}
+
+// Discard drops the queued commands
+func (p *Pipeline) Discard() {
+	p.mu.Lock()
+	p.cmds = nil
+	p.mu.Unlock()
+}
+
+// Exec sends the queued commands and fills in their replies. It returns the
+// commands, and the first error among them other than Nil; a connection
+// failure is set on every command. The pipeline is empty afterwards and can
+// be reused.
+func (p *Pipeline) Exec(ctx context.Context) ([]Cmder, error) {
+	p.mu.Lock()
+	cmds := p.cmds
+	p.cmds = nil
+	p.mu.Unlock()
+
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+	if err := p.exec(ctx, cmds); err != nil {
+		setErrs(cmds, err)
+		return cmds, err
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != Nil {
+			return cmds, err
+		}
+	}
+	return cmds, nil
+}
+
+// Pipelined calls fn to queue commands and then executes them. Nothing is
+// sent when fn fails.
+func (p *Pipeline) Pipelined(ctx context.Context, fn func(pipe *Pipeline) error) ([]Cmder, error) {
+	if err := fn(p); err != nil {
+		p.Discard()
+		return nil, err
+	}
+	return p.Exec(ctx)
+}
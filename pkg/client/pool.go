@@ -0,0 +1,260 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrClosed is returned when the client has been closed
+	ErrClosed = errors.New("client: closed")
+	// ErrPoolTimeout is returned when no connection became free in time
+	ErrPoolTimeout = errors.New("client: connection pool timeout")
+)
+
+// PoolStats describes the state of the connection pool
+type PoolStats struct {
+	Hits     uint32 // times an idle connection was reused
+	Misses   uint32 // times a new connection had to be dialed
+	Timeouts uint32 // times waiting for a free connection timed out
+
+	TotalConns uint32 // open connections, in use or idle
+	IdleConns  uint32 // idle connections
+}
+
+// pool keeps connections to the server for reuse. At most PoolSize
+// connections are handed out at once; returned connections are kept idle up
+// to MaxIdleConns.
+type pool struct {
+	opts *Options
+
+	// slots holds a token for every connection handed out
+	slots chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	total  int
+	closed bool
+
+	hits, misses, timeouts atomic.Uint32
+}
+
+func newPool(opts *Options) *pool {
+	return &pool{
+		opts:  opts,
+		slots: make(chan struct{}, opts.PoolSize),
+	}
+}
+
+// Get returns an idle connection, or dials a new one, waiting for a free
+// slot while PoolSize connections are in use
+func (p *pool) Get(ctx context.Context) (*conn, error) {
+	if err := p.waitSlot(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		cn, err := p.popIdle()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		if cn == nil {
+			break
+		}
+		if p.healthy(ctx, cn) {
+			p.hits.Add(1)
+			return cn, nil
+		}
+		p.remove(cn)
+	}
+
+	p.misses.Add(1)
+	cn, err := p.dial(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.total++
+	p.mu.Unlock()
+	return cn, nil
+}
+
+// waitSlot takes a slot, waiting until one is free, the context is done or
+// PoolTimeout passes
+func (p *pool) waitSlot(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(p.opts.PoolTimeout)
+	defer timer.Stop()
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		p.timeouts.Add(1)
+		return ctx.Err()
+	case <-timer.C:
+		p.timeouts.Add(1)
+		return ErrPoolTimeout
+	}
+}
+
+// popIdle takes the most recently used idle connection, closing the ones
+// that have been idle for too long on the way
+func (p *pool) popIdle() (*conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+	for len(p.idle) > 0 {
+		cn := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if p.opts.ConnMaxIdleTime > 0 && time.Since(cn.usedAt) > p.opts.ConnMaxIdleTime {
+			cn.Close()
+			p.total--
+			continue
+		}
+		return cn, nil
+	}
+	return nil, nil
+}
+
+// healthy checks a connection that has been idle for a while with a PING
+func (p *pool) healthy(ctx context.Context, cn *conn) bool {
+	if p.opts.HealthCheckInterval <= 0 || time.Since(cn.usedAt) < p.opts.HealthCheckInterval {
+		return true
+	}
+	ping := NewStatusCmd("ping")
+	return cn.roundTrip(ctx, p.opts, []Cmder{ping}) == nil && ping.Err() == nil
+}
+
+// Put returns a connection to the pool. Broken connections are closed, as
+// are connections beyond MaxIdleConns.
+func (p *pool) Put(cn *conn) {
+	defer func() { <-p.slots }()
+
+	p.mu.Lock()
+	if cn.broken || p.closed || len(p.idle) >= p.opts.MaxIdleConns {
+		p.mu.Unlock()
+		p.remove(cn)
+		return
+	}
+	cn.usedAt = time.Now()
+	p.idle = append(p.idle, cn)
+	p.mu.Unlock()
+}
+
+// remove closes a connection that won't be reused
+func (p *pool) remove(cn *conn) {
+	cn.Close()
+	p.mu.Lock()
+	p.total--
+	p.mu.Unlock()
+}
+
+// dial opens and initializes a new connection
+func (p *pool) dial(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: p.opts.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", p.opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := newConn(netConn)
+	if err := p.initConn(ctx, cn); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// initConn negotiates the protocol, authenticates and names a new
+// connection. HELLO does all three at once; plain RESP2 connections without
+// a name only need AUTH.
+func (p *pool) initConn(ctx context.Context, cn *conn) error {
+	var cmd *StatusCmd
+	switch {
+	case p.opts.Protocol != 2 || p.opts.ClientName != "":
+		args := []interface{}{"hello", strconv.Itoa(p.opts.Protocol)}
+		if p.opts.Password != "" {
+			args = append(args, "auth", p.username(), p.opts.Password)
+		}
+		if p.opts.ClientName != "" {
+			args = append(args, "setname", p.opts.ClientName)
+		}
+		// The HELLO reply is a map, which isn't worth decoding
+		hello := NewCmd(args...)
+		if err := cn.roundTrip(ctx, p.opts, []Cmder{hello}); err != nil {
+			return err
+		}
+		return hello.Err()
+	case p.opts.Password != "":
+		if p.opts.Username != "" {
+			cmd = NewStatusCmd("auth", p.opts.Username, p.opts.Password)
+		} else {
+			cmd = NewStatusCmd("auth", p.opts.Password)
+		}
+	default:
+		return nil
+	}
+
+	if err := cn.roundTrip(ctx, p.opts, []Cmder{cmd}); err != nil {
+		return err
+	}
+	return cmd.Err()
+}
+
+func (p *pool) username() string {
+	if p.opts.Username == "" {
+		return "default"
+	}
+	return p.opts.Username
+}
+
+// Stats returns a snapshot of the pool's counters
+func (p *pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{
+		Hits:       p.hits.Load(),
+		Misses:     p.misses.Load(),
+		Timeouts:   p.timeouts.Load(),
+		TotalConns: uint32(p.total),
+		IdleConns:  uint32(len(p.idle)),
+	}
+}
+
+// Close closes the idle connections and makes the pool refuse new requests.
+// Connections in use are closed when they are returned.
+func (p *pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	p.closed = true
+
+	var firstErr error
+	for _, cn := range p.idle {
+		if err := cn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		p.total--
+	}
+	p.idle = nil
+	return firstErr
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Message is a message published to a channel the PubSub is subscribed to.
+// Messages published with SPublish arrive with Shard set.
+type Message struct {
+	Channel string
+	// Pattern is the pattern that matched the channel, for messages received
+	// through PSubscribe
+	Pattern string
+	Payload string
+	Shard   bool
+}
+
+// Subscription confirms a change to the subscriptions of a PubSub
+type Subscription struct {
+	// Kind is "subscribe", "unsubscribe", "psubscribe", "punsubscribe",
+	// "ssubscribe" or "sunsubscribe"
+	Kind    string
+	Channel string
+	// Count is the number of subscriptions left. Shard channels are counted
+	// apart from the others.
+	Count int64
+}
+
+// Pong is the reply to Ping while subscribed
+type Pong struct {
+	Payload string
+}
+
+// PubSub is a connection dedicated to subscriptions, outside the pool.
+// Subscribing and receiving may happen from different goroutines, but only
+// one goroutine should receive. After a connection error the PubSub has to
+// be closed and created again.
+//
+//	ps := c.Subscribe(ctx, "news")
+//	defer ps.Close()
+//	for {
+//		msg, err := ps.ReceiveMessage(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(msg.Channel, msg.Payload)
+//	}
+type PubSub struct {
+	client *Client
+
+	mu     sync.Mutex
+	cn     *conn
+	closed bool
+	// err is the error of a subscription made by Client.Subscribe, returned
+	// by the next Receive
+	err error
+}
+
+// Subscribe returns a PubSub subscribed to channels. Subscription errors
+// are returned by the first Receive.
+func (c *Client) Subscribe(ctx context.Context, channels ...string) *PubSub {
+	ps := &PubSub{client: c}
+	if len(channels) > 0 {
+		ps.err = ps.Subscribe(ctx, channels...)
+	}
+	return ps
+}
+
+// PSubscribe returns a PubSub subscribed to the channels matching patterns
+func (c *Client) PSubscribe(ctx context.Context, patterns ...string) *PubSub {
+	ps := &PubSub{client: c}
+	if len(patterns) > 0 {
+		ps.err = ps.PSubscribe(ctx, patterns...)
+	}
+	return ps
+}
+
+// SSubscribe returns a PubSub subscribed to shard channels
+func (c *Client) SSubscribe(ctx context.Context, channels ...string) *PubSub {
+	ps := &PubSub{client: c}
+	if len(channels) > 0 {
+		ps.err = ps.SSubscribe(ctx, channels...)
+	}
+	return ps
+}
+
+// Subscribe subscribes to more channels
+func (ps *PubSub) Subscribe(ctx context.Context, channels ...string) error {
+	return ps.send(ctx, "subscribe", channels)
+}
+
+// PSubscribe subscribes to the channels matching more patterns
+func (ps *PubSub) PSubscribe(ctx context.Context, patterns ...string) error {
+	return ps.send(ctx, "psubscribe", patterns)
+}
+
+// Unsubscribe unsubscribes from channels, or from all of them when none are
+// given
+func (ps *PubSub) Unsubscribe(ctx context.Context, channels ...string) error {
+	return ps.send(ctx, "unsubscribe", channels)
+}
+
+// PUnsubscribe unsubscribes from patterns, or from all of them when none are
+// given
+func (ps *PubSub) PUnsubscribe(ctx context.Context, patterns ...string) error {
+	return ps.send(ctx, "punsubscribe", patterns)
+}
+
+// SSubscribe subscribes to more shard channels
+func (ps *PubSub) SSubscribe(ctx context.Context, channels ...string) error {
+	return ps.send(ctx, "ssubscribe", channels)
+}
+
+// SUnsubscribe unsubscribes from shard channels, or from all of them when
+// none are given
+func (ps *PubSub) SUnsubscribe(ctx context.Context, channels ...string) error {
+	return ps.send(ctx, "sunsubscribe", channels)
+}
+
+// Ping asks the server for a Pong, which arrives through Receive
+func (ps *PubSub) Ping(ctx context.Context, payload ...string) error {
+	return ps.send(ctx, "ping", payload)
+}
+
+// send writes a command whose replies are read by Receive, connecting first
+// if needed
+func (ps *PubSub) send(ctx context.Context, name string, names []string) error {
+	args := make([]interface{}, 0, len(names)+1)
+	args = append(args, name)
+	for _, n := range names {
+		args = append(args, n)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	cn, err := ps.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return cn.send(ctx, ps.client.opts, []Cmder{NewCmd(args...)})
+}
+
+// conn returns the connection, dialing it on first use. The caller must hold
+// ps.mu.
+func (ps *PubSub) conn(ctx context.Context) (*conn, error) {
+	if ps.closed {
+		return nil, ErrClosed
+	}
+	if ps.cn == nil {
+		cn, err := ps.client.pool.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ps.cn = cn
+	}
+	return ps.cn, nil
+}
+
+// Receive waits for the next *Message, *Subscription or *Pong, for as long
+// as the context allows
+func (ps *PubSub) Receive(ctx context.Context) (interface{}, error) {
+	ps.mu.Lock()
+	if err := ps.err; err != nil {
+		ps.err = nil
+		ps.mu.Unlock()
+		return nil, err
+	}
+	cn, err := ps.conn(ctx)
+	ps.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodePubSubReply(reply)
+}
+
+// ReceiveMessage waits for the next message, skipping subscription
+// confirmations and pongs
+func (ps *PubSub) ReceiveMessage(ctx context.Context) (*Message, error) {
+	for {
+		reply, err := ps.Receive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if msg, ok := reply.(*Message); ok {
+			return msg, nil
+		}
+	}
+}
+
+// decodePubSubReply decodes a reply received by a subscribed connection.
+// RESP3 connections receive them as pushes, RESP2 ones as arrays.
+func decodePubSubReply(reply interface{}) (interface{}, error) {
+	var elements []interface{}
+	switch v := reply.(type) {
+	case *Error:
+		return nil, v
+	case types.SimpleString:
+		// PING on a RESP3 connection gets a plain reply
+		return &Pong{Payload: string(v)}, nil
+	case string:
+		return &Pong{Payload: v}, nil
+	case types.Push:
+		elements = v
+	case []interface{}:
+		elements = v
+	default:
+		return nil, unexpectedReply(reply)
+	}
+	if len(elements) == 0 {
+		return nil, unexpectedReply(reply)
+	}
+
+	strs := make([]string, len(elements))
+	for i, el := range elements {
+		if s, ok := el.(string); ok {
+			strs[i] = s
+		}
+	}
+	switch kind := strs[0]; {
+	case kind == "message" && len(elements) == 3:
+		return &Message{Channel: strs[1], Payload: strs[2]}, nil
+	case kind == "smessage" && len(elements) == 3:
+		return &Message{Channel: strs[1], Payload: strs[2], Shard: true}, nil
+	case kind == "pmessage" && len(elements) == 4:
+		return &Message{Pattern: strs[1], Channel: strs[2], Payload: strs[3]}, nil
+	case kind == "pong" && len(elements) == 2:
+		return &Pong{Payload: strs[1]}, nil
+	case len(elements) == 3:
+		count, ok := elements[2].(int64)
+		if !ok {
+			return nil, unexpectedReply(reply)
+		}
+		return &Subscription{Kind: kind, Channel: strs[1], Count: count}, nil
+	default:
+		return nil, unexpectedReply(reply)
+	}
+}
+
+// Close closes the connection, which drops every subscription
+func (ps *PubSub) Close() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.closed {
+		return ErrClosed
+	}
+	ps.closed = true
+	if ps.cn == nil {
+		return nil
+	}
+	return ps.cn.Close()
+}
@@ -0,0 +1,98 @@
+package client
+
+import "context"
+
+// Tx is a connection reserved for a check-and-set transaction. Commands run
+// on it right away, so their replies can decide what the transaction does;
+// TxPipelined then runs the transaction, which fails with ErrTxFailed if a
+// watched key changed in the meantime.
+type Tx struct {
+	cmdable
+	client *Client
+	cn     *conn
+}
+
+// Watch reserves a connection, watches keys on it and calls fn with it. The
+// keys are unwatched and the connection returned to the pool afterwards.
+//
+//	err := c.Watch(ctx, func(tx *client.Tx) error {
+//		n, err := tx.Get(ctx, "counter").Int64()
+//		if err != nil && err != client.Nil {
+//			return err
+//		}
+//		_, err = tx.TxPipelined(ctx, func(pipe *client.Pipeline) error {
+//			pipe.Set(ctx, "counter", n+1, 0)
+//			return nil
+//		})
+//		return err
+//	}, "counter")
+func (c *Client) Watch(ctx context.Context, fn func(tx *Tx) error, keys ...string) error {
+	cn, err := c.pool.Get(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.pool.Put(cn)
+
+	tx := &Tx{client: c, cn: cn}
+	tx.cmdable = tx.Process
+	if len(keys) > 0 {
+		if err := tx.Watch(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+
+	err = fn(tx)
+	if !cn.broken {
+		tx.Unwatch(ctx)
+	}
+	return err
+}
+
+// Process sends cmd on the reserved connection. Failed commands aren't
+// retried, since a new connection wouldn't watch the keys.
+func (tx *Tx) Process(ctx context.Context, cmd Cmder) error {
+	if err := tx.cn.roundTrip(ctx, tx.client.opts, []Cmder{cmd}); err != nil {
+		cmd.setErr(err)
+	}
+	return cmd.Err()
+}
+
+// Do sends a command built from args on the reserved connection
+func (tx *Tx) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(args...)
+	tx.Process(ctx, cmd)
+	return cmd
+}
+
+// Watch watches more keys
+func (tx *Tx) Watch(ctx context.Context, keys ...string) *StatusCmd {
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, "watch")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := NewStatusCmd(args...)
+	tx.Process(ctx, cmd)
+	return cmd
+}
+
+// Unwatch forgets the watched keys
+func (tx *Tx) Unwatch(ctx context.Context) *StatusCmd {
+	cmd := NewStatusCmd("unwatch")
+	tx.Process(ctx, cmd)
+	return cmd
+}
+
+// TxPipeline returns a pipeline running its commands as a transaction on the
+// reserved connection
+func (tx *Tx) TxPipeline() *Pipeline {
+	return newPipeline(func(ctx context.Context, cmds []Cmder) error {
+		return tx.cn.roundTripTx(ctx, tx.client.opts, cmds)
+	})
+}
+
+// TxPipelined queues the commands added by fn and runs them as a transaction
+// on the reserved connection
+func (tx *Tx) TxPipelined(ctx context.Context, fn func(pipe *Pipeline) error) ([]Cmder, error) {
+	return tx.TxPipeline().Pipelined(ctx, fn)
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/hardikphalet/go-redis/internal/errs"
+	"github.com/hardikphalet/go-redis/internal/types"
+)
+
+// Nil is returned by commands whose reply is nil, such as GET on a missing
+// key
+var Nil = errors.New("client: nil")
+
+// The reply types are shared with the server, so the two always agree on
+// how values are encoded. They appear in the replies of Do.
+type (
+	// Error is an error reply, such as "WRONGTYPE Operation against a key
+	// holding the wrong kind of value"
+	Error = errs.Error
+	// SimpleString is a status reply, such as OK
+	SimpleString = types.SimpleString
+	// Map is a RESP3 map reply, in the order the server sent it
+	Map = types.Map
+	// MapEntry is a key-value pair of a Map
+	MapEntry = types.MapEntry
+	// Set is a RESP3 set reply
+	Set = types.Set
+	// Push is a RESP3 out of band message
+	Push = types.Push
+	// BigNumber is a RESP3 big number reply
+	BigNumber = types.BigNumber
+	// VerbatimString is a RESP3 verbatim string reply
+	VerbatimString = types.VerbatimString
+)